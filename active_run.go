@@ -0,0 +1,134 @@
+package mlflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ActiveRun is a run started with StartRun. It remembers the run id, so
+// that values can be logged without passing it around.
+type ActiveRun struct {
+	client *Client
+	Run    Run
+}
+
+// StartRun creates a run in experimentId and returns it as an ActiveRun.
+// End it with End once done.
+func (p *Client) StartRun(ctx context.Context, experimentId string, opts ...RunOption) (*ActiveRun, error) {
+	run, err := p.CreateRun(ctx, experimentId, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ActiveRun{client: p, Run: *run}, nil
+}
+
+func (r *ActiveRun) Id() string {
+	return r.Run.Info.RunId
+}
+
+// LogMetric logs a metric value at step, timestamped now.
+func (r *ActiveRun) LogMetric(ctx context.Context, key string, value float64, step int64) error {
+	return r.client.LogMetric(ctx, r.Id(), key, value, Millis(time.Now()), step)
+}
+
+func (r *ActiveRun) LogParam(ctx context.Context, key string, value string) error {
+	return r.client.LogParam(ctx, r.Id(), key, value)
+}
+
+func (r *ActiveRun) SetTag(ctx context.Context, key string, value string) error {
+	return r.client.SetTag(ctx, r.Id(), key, value)
+}
+
+func (r *ActiveRun) LogBatch(ctx context.Context, metrics []Metric, params []Param, tags []RunTag) error {
+	return r.client.LogBatch(ctx, r.Id(), metrics, params, tags)
+}
+
+func (r *ActiveRun) LogMetrics(ctx context.Context, metrics map[string]float64, step int64) error {
+	return r.client.LogMetrics(ctx, r.Id(), metrics, step)
+}
+
+func (r *ActiveRun) LogParams(ctx context.Context, params map[string]string) error {
+	return r.client.LogParams(ctx, r.Id(), params)
+}
+
+func (r *ActiveRun) SetTags(ctx context.Context, tags map[string]string) error {
+	return r.client.SetTags(ctx, r.Id(), tags)
+}
+
+func (r *ActiveRun) LogArtifact(ctx context.Context, localPath string, artifactPath string) error {
+	return r.client.LogArtifact(ctx, r.Id(), localPath, artifactPath)
+}
+
+func (r *ActiveRun) LogArtifacts(ctx context.Context, localDir string, artifactPath string) error {
+	return r.client.LogArtifacts(ctx, r.Id(), localDir, artifactPath)
+}
+
+func (r *ActiveRun) LogModel(ctx context.Context, artifactPath string, model ModelSpec) (*ModelInfo, error) {
+	return r.client.LogModel(ctx, r.Id(), artifactPath, model)
+}
+
+func (r *ActiveRun) LogOnnxModel(ctx context.Context, artifactPath string, onnxPath string, opts OnnxOptions) (*ModelInfo, error) {
+	return r.client.LogOnnxModel(ctx, r.Id(), artifactPath, onnxPath, opts)
+}
+
+// End sets the final status of the run and its end time to now.
+func (r *ActiveRun) End(ctx context.Context, status RunStatus) error {
+	info, err := r.client.UpdateRunWithEndTime(ctx, r.Id(), status, Millis(time.Now()))
+	if err != nil {
+		return err
+	}
+	r.Run.Info = *info
+	return nil
+}
+
+// ErrorTag is the run tag WithRun sets to the error or panic message of a
+// failed run.
+const ErrorTag = "error"
+
+const maxTagValue = 5000
+
+// WithRun starts a run in experimentId, calls fn with it and always ends
+// it: FINISHED when fn returns nil, FAILED when it returns an error or
+// panics. The message of the failure is set as the ErrorTag tag, and a
+// panic is resumed once the run is ended. The run is ended even when ctx is
+// done by then.
+func (p *Client) WithRun(ctx context.Context, experimentId string, fn func(r *ActiveRun) error, opts ...RunOption) error {
+	run, err := p.StartRun(ctx, experimentId, opts...)
+	if err != nil {
+		return err
+	}
+	end := func(status RunStatus, message string) error {
+		if ctx.Err() != nil {
+			ctx = context.Background()
+		}
+		if message != "" {
+			if len(message) > maxTagValue {
+				message = message[:maxTagValue]
+			}
+			run.SetTag(ctx, ErrorTag, message)
+		}
+		return run.End(ctx, status)
+	}
+	returned := false
+	defer func() {
+		if returned {
+			return
+		}
+		// fn panicked or called runtime.Goexit.
+		recovered := recover()
+		if recovered == nil {
+			end(Failed, "runtime.Goexit called")
+			return
+		}
+		end(Failed, fmt.Sprint("panic: ", recovered))
+		panic(recovered)
+	}()
+	fnErr := fn(run)
+	returned = true
+	if fnErr != nil {
+		end(Failed, fnErr.Error())
+		return fnErr
+	}
+	return end(Finished, "")
+}
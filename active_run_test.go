@@ -0,0 +1,188 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStartRun(t *testing.T) {
+	artifacts := t.TempDir()
+	requests := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests[r.URL.Path] = request
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/create":
+			w.Write([]byte(`{"run": {"info": {"run_id": "run1", "experiment_id": "1", "status": "RUNNING", "artifact_uri": "mlflow-artifacts:/1/run1/artifacts"}}}`))
+		case "/api/2.0/mlflow/runs/get":
+			json.NewEncoder(w).Encode(map[string]interface{}{"run": map[string]interface{}{"info": map[string]interface{}{"run_id": "run1", "artifact_uri": "file://" + filepath.ToSlash(artifacts)}}})
+		case "/api/2.0/mlflow/runs/update":
+			w.Write([]byte(`{"run_info": {"run_id": "run1", "status": "FINISHED"}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	start := time.Now().UnixMilli()
+	run, err := client.StartRun(ctx, "1", WithRunName("train"), WithTags(map[string]string{"team": "nlp"}), WithParentRun("parent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := requests["/api/2.0/mlflow/runs/create"]
+	if create["run_name"] != "train" || int64(create["start_time"].(float64)) < start {
+		t.Errorf("unexpected create request %v", create)
+	}
+	if tags := create["tags"].([]interface{}); len(tags) != 2 || tags[1].(map[string]interface{})["value"] != "parent" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if run.Id() != "run1" {
+		t.Errorf("unexpected run id %s", run.Id())
+	}
+
+	if err := run.LogMetric(ctx, "loss", 0.5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if request := requests["/api/2.0/mlflow/runs/log-metric"]; request["run_id"] != "run1" || request["key"] != "loss" || request["step"] != 1.0 {
+		t.Errorf("unexpected log-metric request %v", request)
+	}
+	if err := run.LogParam(ctx, "lr", "0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run.SetTag(ctx, "stage", "eval"); err != nil {
+		t.Fatal(err)
+	}
+	if request := requests["/api/2.0/mlflow/runs/set-tag"]; request["run_id"] != "run1" || request["value"] != "eval" {
+		t.Errorf("unexpected set-tag request %v", request)
+	}
+	path := filepath.Join(t.TempDir(), "model.txt")
+	if err := os.WriteFile(path, []byte("model"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run.LogArtifact(ctx, path, "models"); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(artifacts, "models", "model.txt")); err != nil || string(b) != "model" {
+		t.Errorf("unexpected logged artifact %q, %v", b, err)
+	}
+	if err := run.End(ctx, Finished); err != nil {
+		t.Fatal(err)
+	}
+	update := requests["/api/2.0/mlflow/runs/update"]
+	if update["status"] != "FINISHED" || int64(update["end_time"].(float64)) < start {
+		t.Errorf("unexpected update request %v", update)
+	}
+	if run.Run.Info.Status != "FINISHED" {
+		t.Errorf("unexpected status %s", run.Run.Info.Status)
+	}
+}
+
+func TestWithRun(t *testing.T) {
+	var statuses []string
+	tags := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/create":
+			w.Write([]byte(`{"run": {"info": {"run_id": "run1", "status": "RUNNING"}}}`))
+		case "/api/2.0/mlflow/runs/set-tag":
+			tags[request["key"].(string)] = request["value"].(string)
+			w.Write([]byte(`{}`))
+		case "/api/2.0/mlflow/runs/update":
+			statuses = append(statuses, request["status"].(string))
+			w.Write([]byte(`{"run_info": {"run_id": "run1"}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	if err := client.WithRun(ctx, "1", func(r *ActiveRun) error { return r.LogParam(ctx, "lr", "0.1") }); err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("diverged")
+	if err := client.WithRun(ctx, "1", func(r *ActiveRun) error { return failure }); err != failure {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if tags[ErrorTag] != "diverged" {
+		t.Errorf("unexpected error tag %q", tags[ErrorTag])
+	}
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "out of memory" {
+				t.Errorf("expected the panic to be resumed, got %v", recovered)
+			}
+		}()
+		client.WithRun(ctx, "1", func(r *ActiveRun) error { panic("out of memory") })
+	}()
+	if tags[ErrorTag] != "panic: out of memory" {
+		t.Errorf("unexpected error tag %q", tags[ErrorTag])
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	err := client.WithRun(canceled, "1", func(r *ActiveRun) error {
+		cancel()
+		return canceled.Err()
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	expected := []string{"FINISHED", "FAILED", "FAILED", "FAILED"}
+	if len(statuses) != len(expected) {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Errorf("unexpected statuses %v", statuses)
+		}
+	}
+}
+
+func TestWithRunGoexit(t *testing.T) {
+	var status string
+	tags := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/create":
+			w.Write([]byte(`{"run": {"info": {"run_id": "run1", "status": "RUNNING"}}}`))
+		case "/api/2.0/mlflow/runs/set-tag":
+			tags[request["key"].(string)] = request["value"].(string)
+			w.Write([]byte(`{}`))
+		case "/api/2.0/mlflow/runs/update":
+			status = request["status"].(string)
+			w.Write([]byte(`{"run_info": {"run_id": "run1"}}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	// As t.FailNow does in fn.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.WithRun(context.Background(), "1", func(r *ActiveRun) error {
+			runtime.Goexit()
+			return nil
+		})
+	}()
+	<-done
+	if status != "FAILED" || tags[ErrorTag] != "runtime.Goexit called" {
+		t.Errorf("unexpected status %s and error tag %q", status, tags[ErrorTag])
+	}
+}
@@ -0,0 +1,552 @@
+package mlflow
+
+import (
+	"context"
+	"image"
+	"io"
+	"net/http"
+	"time"
+)
+
+// API is the part of Client that talks to the tracking server, so that code
+// using it can be tested against a fake such as mlflowfake.Store or
+// another backend. Connection helpers such as Iterator, AsyncLogger and
+// ActiveRun stay on Client.
+type API interface {
+	// Experiments
+	GetExperiment(ctx context.Context, experimentId string) (*Experiment, error)
+	GetExperimentsByName(ctx context.Context, name string) (*Experiment, error)
+	GetOrCreateExperiment(ctx context.Context, name string) (*Experiment, error)
+	SearchExperiments(ctx context.Context, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchExperiments, error)
+	CreateExperiment(ctx context.Context, name string) (*string, error)
+	SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error
+	SetExperimentNote(ctx context.Context, experimentId string, note string) error
+	DefaultExperimentId(ctx context.Context) (string, error)
+
+	// Runs
+	CreateRun(ctx context.Context, experimentId string, opts ...RunOption) (*Run, error)
+	CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...RunOption) (*Run, error)
+	UpdateRun(ctx context.Context, runId string, status RunStatus) (*RunInfo, error)
+	UpdateRunAt(ctx context.Context, runId string, status RunStatus, endTime time.Time) (*RunInfo, error)
+	UpdateRunWithEndTime(ctx context.Context, runId string, status RunStatus, endTime int64) (*RunInfo, error)
+	UpdateRunWithName(ctx context.Context, runId string, status RunStatus, runName string) (*RunInfo, error)
+	DeleteRun(ctx context.Context, runId string) error
+	GetRun(ctx context.Context, runId string) (*Run, error)
+	SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRuns, error)
+	ListChildRuns(ctx context.Context, parentRunId string) ([]Run, error)
+	LogInputs(ctx context.Context, runId string, datasets []DatasetInput) error
+	SetTag(ctx context.Context, runId string, key string, value string) error
+	SetTags(ctx context.Context, runId string, tags map[string]string) error
+	SetRunNote(ctx context.Context, runId string, note string) error
+	SetRunSource(ctx context.Context, runId string, name string, sourceType SourceType) error
+	SetRunUser(ctx context.Context, runId string, user string) error
+
+	// Metrics and params
+	LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error
+	LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error
+	LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error
+	LogParam(ctx context.Context, runId string, key string, value string) error
+	LogParams(ctx context.Context, runId string, params map[string]string) error
+	LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error
+	LogBatch(ctx context.Context, runId string, metrics []Metric, params []Param, tags []RunTag) error
+	GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*ResponseGetMetricHistory, error)
+
+	// Artifacts
+	ListArtifacts(ctx context.Context, runId string, path string) ([]FileInfo, error)
+	DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...DownloadOption) error
+	OpenArtifact(ctx context.Context, runId string, path string) (io.ReadCloser, error)
+	LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error
+	LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error
+	LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error
+	LogText(ctx context.Context, runId string, text string, artifactFile string) error
+	LogImage(ctx context.Context, runId string, img image.Image, artifactFile string) error
+	LogTable(ctx context.Context, runId string, columns []string, rows [][]interface{}, artifactFile string) error
+
+	// Model registry
+	CreateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error)
+	GetRegisteredModel(ctx context.Context, name string) (*RegisteredModel, error)
+	UpdateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error)
+	RenameRegisteredModel(ctx context.Context, name string, newName string) (*RegisteredModel, error)
+	DeleteRegisteredModel(ctx context.Context, name string) error
+	SearchRegisteredModels(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRegisteredModels, error)
+	SetRegisteredModelTag(ctx context.Context, name string, key string, value string) error
+	DeleteRegisteredModelTag(ctx context.Context, name string, key string) error
+	SetRegisteredModelAlias(ctx context.Context, name string, alias string, version string) error
+	DeleteRegisteredModelAlias(ctx context.Context, name string, alias string) error
+	CreateModelVersion(ctx context.Context, name string, source string, runId string, tags ...ModelVersionTag) (*ModelVersion, error)
+	GetModelVersion(ctx context.Context, name string, version string) (*ModelVersion, error)
+	UpdateModelVersion(ctx context.Context, name string, version string, description string) (*ModelVersion, error)
+	DeleteModelVersion(ctx context.Context, name string, version string) error
+	SearchModelVersions(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchModelVersions, error)
+	GetLatestVersions(ctx context.Context, name string, stages []string) ([]ModelVersion, error)
+	GetModelVersionByAlias(ctx context.Context, name string, alias string) (*ModelVersion, error)
+	ResolveModelUri(ctx context.Context, uri string) (*ModelVersion, error)
+	TransitionModelVersionStage(ctx context.Context, name string, version string, stage string, archiveExisting bool) (*ModelVersion, error)
+	WaitForModelVersion(ctx context.Context, name string, version string, interval time.Duration) (*ModelVersion, error)
+	SetModelVersionTag(ctx context.Context, name string, version string, key string, value string) error
+	DeleteModelVersionTag(ctx context.Context, name string, version string, key string) error
+	GetModelVersionDownloadUri(ctx context.Context, name string, version string) (string, error)
+	DownloadModelVersion(ctx context.Context, name string, version string, localDir string, opts ...DownloadOption) error
+	CopyModelVersion(ctx context.Context, srcName string, srcVersion string, dstName string) (*ModelVersion, error)
+
+	// Traces
+	StartTrace(ctx context.Context, experimentId string, tags map[string]string) (*ActiveTrace, error)
+	EndTrace(ctx context.Context, trace *ActiveTrace, status TraceStatus) (*TraceInfo, error)
+	SearchTraces(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchTraces, error)
+	GetTraceInfo(ctx context.Context, requestId string) (*TraceInfo, error)
+	GetTrace(ctx context.Context, requestId string) (*Trace, error)
+	DeleteTraces(ctx context.Context, experimentId string, maxTimestampMillis int64, maxTraces int, requestIds []string) (int, error)
+	SetTraceTag(ctx context.Context, requestId string, key string, value string) error
+	DeleteTraceTag(ctx context.Context, requestId string, key string) error
+	LogAssessment(ctx context.Context, traceId string, assessment Assessment) (*Assessment, error)
+	LogFeedback(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource, rationale string) (*Assessment, error)
+	LogExpectation(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource) (*Assessment, error)
+	GetAssessment(ctx context.Context, traceId string, assessmentId string) (*Assessment, error)
+	DeleteAssessment(ctx context.Context, traceId string, assessmentId string) error
+
+	// Registry webhooks
+	CreateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error)
+	ListRegistryWebhooks(ctx context.Context, modelName string, events []RegistryWebhookEvent, pageToken string) (*ResponseListRegistryWebhooks, error)
+	TestRegistryWebhook(ctx context.Context, id string, event RegistryWebhookEvent) (*WebhookTestResult, error)
+	UpdateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error)
+	DeleteRegistryWebhook(ctx context.Context, id string) error
+
+	// Logged models
+	CreateLoggedModel(ctx context.Context, experimentId string, name string, modelType string, sourceRunId string, params map[string]string, tags map[string]string) (*LoggedModel, error)
+	GetLoggedModel(ctx context.Context, modelId string) (*LoggedModel, error)
+	DeleteLoggedModel(ctx context.Context, modelId string) error
+	FinalizeLoggedModel(ctx context.Context, modelId string, status LoggedModelStatus) (*LoggedModel, error)
+	SearchLoggedModels(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []LoggedModelOrderBy, pageToken string) (*ResponseSearchLoggedModels, error)
+	SetLoggedModelTags(ctx context.Context, modelId string, tags map[string]string) error
+	DeleteLoggedModelTag(ctx context.Context, modelId string, key string) error
+	LogLoggedModelParams(ctx context.Context, modelId string, params map[string]string) error
+	LogLoggedModelMetric(ctx context.Context, modelId string, runId string, key string, value float64, timestamp int64, step int64) error
+
+	// Prompts
+	RegisterPrompt(ctx context.Context, name string, template string, commitMessage string, tags map[string]string) (*PromptVersion, error)
+	GetPrompt(ctx context.Context, name string) (*Prompt, error)
+	GetPromptVersion(ctx context.Context, name string, version string) (*PromptVersion, error)
+	LoadPrompt(ctx context.Context, uri string) (*PromptVersion, error)
+	SearchPrompts(ctx context.Context, filter string, maxResults int, pageToken string) ([]Prompt, string, error)
+	SetPromptAlias(ctx context.Context, name string, alias string, version string) error
+	DeletePromptAlias(ctx context.Context, name string, alias string) error
+	DeletePromptVersion(ctx context.Context, name string, version string) error
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = UnimplementedAPI{}
+)
+
+// UnimplementedAPI implements API by returning an IsNotImplemented error
+// from every method. Embedding it lets partial implementations of API keep
+// compiling when methods are added to it.
+type UnimplementedAPI struct{}
+
+func notImplemented(method string) error {
+	return &Error{StatusCode: http.StatusNotImplemented, ErrorCode: ErrorCodeNotImplemented, Message: method + " is not implemented"}
+}
+
+func (UnimplementedAPI) GetExperiment(ctx context.Context, experimentId string) (*Experiment, error) {
+	return nil, notImplemented("GetExperiment")
+}
+
+func (UnimplementedAPI) GetExperimentsByName(ctx context.Context, name string) (*Experiment, error) {
+	return nil, notImplemented("GetExperimentsByName")
+}
+
+func (UnimplementedAPI) GetOrCreateExperiment(ctx context.Context, name string) (*Experiment, error) {
+	return nil, notImplemented("GetOrCreateExperiment")
+}
+
+func (UnimplementedAPI) SearchExperiments(ctx context.Context, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchExperiments, error) {
+	return nil, notImplemented("SearchExperiments")
+}
+
+func (UnimplementedAPI) CreateExperiment(ctx context.Context, name string) (*string, error) {
+	return nil, notImplemented("CreateExperiment")
+}
+
+func (UnimplementedAPI) SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error {
+	return notImplemented("SetExperimentTag")
+}
+
+func (UnimplementedAPI) SetExperimentNote(ctx context.Context, experimentId string, note string) error {
+	return notImplemented("SetExperimentNote")
+}
+
+func (UnimplementedAPI) DefaultExperimentId(ctx context.Context) (string, error) {
+	return "", notImplemented("DefaultExperimentId")
+}
+
+func (UnimplementedAPI) CreateRun(ctx context.Context, experimentId string, opts ...RunOption) (*Run, error) {
+	return nil, notImplemented("CreateRun")
+}
+
+func (UnimplementedAPI) CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...RunOption) (*Run, error) {
+	return nil, notImplemented("CreateRunAt")
+}
+
+func (UnimplementedAPI) UpdateRun(ctx context.Context, runId string, status RunStatus) (*RunInfo, error) {
+	return nil, notImplemented("UpdateRun")
+}
+
+func (UnimplementedAPI) UpdateRunAt(ctx context.Context, runId string, status RunStatus, endTime time.Time) (*RunInfo, error) {
+	return nil, notImplemented("UpdateRunAt")
+}
+
+func (UnimplementedAPI) UpdateRunWithEndTime(ctx context.Context, runId string, status RunStatus, endTime int64) (*RunInfo, error) {
+	return nil, notImplemented("UpdateRunWithEndTime")
+}
+
+func (UnimplementedAPI) UpdateRunWithName(ctx context.Context, runId string, status RunStatus, runName string) (*RunInfo, error) {
+	return nil, notImplemented("UpdateRunWithName")
+}
+
+func (UnimplementedAPI) DeleteRun(ctx context.Context, runId string) error {
+	return notImplemented("DeleteRun")
+}
+
+func (UnimplementedAPI) GetRun(ctx context.Context, runId string) (*Run, error) {
+	return nil, notImplemented("GetRun")
+}
+
+func (UnimplementedAPI) SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRuns, error) {
+	return nil, notImplemented("SearchRuns")
+}
+
+func (UnimplementedAPI) ListChildRuns(ctx context.Context, parentRunId string) ([]Run, error) {
+	return nil, notImplemented("ListChildRuns")
+}
+
+func (UnimplementedAPI) LogInputs(ctx context.Context, runId string, datasets []DatasetInput) error {
+	return notImplemented("LogInputs")
+}
+
+func (UnimplementedAPI) SetTag(ctx context.Context, runId string, key string, value string) error {
+	return notImplemented("SetTag")
+}
+
+func (UnimplementedAPI) SetTags(ctx context.Context, runId string, tags map[string]string) error {
+	return notImplemented("SetTags")
+}
+
+func (UnimplementedAPI) SetRunNote(ctx context.Context, runId string, note string) error {
+	return notImplemented("SetRunNote")
+}
+
+func (UnimplementedAPI) SetRunSource(ctx context.Context, runId string, name string, sourceType SourceType) error {
+	return notImplemented("SetRunSource")
+}
+
+func (UnimplementedAPI) SetRunUser(ctx context.Context, runId string, user string) error {
+	return notImplemented("SetRunUser")
+}
+
+func (UnimplementedAPI) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	return notImplemented("LogMetric")
+}
+
+func (UnimplementedAPI) LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error {
+	return notImplemented("LogMetricAt")
+}
+
+func (UnimplementedAPI) LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error {
+	return notImplemented("LogMetrics")
+}
+
+func (UnimplementedAPI) LogParam(ctx context.Context, runId string, key string, value string) error {
+	return notImplemented("LogParam")
+}
+
+func (UnimplementedAPI) LogParams(ctx context.Context, runId string, params map[string]string) error {
+	return notImplemented("LogParams")
+}
+
+func (UnimplementedAPI) LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error {
+	return notImplemented("LogParamsFromStruct")
+}
+
+func (UnimplementedAPI) LogBatch(ctx context.Context, runId string, metrics []Metric, params []Param, tags []RunTag) error {
+	return notImplemented("LogBatch")
+}
+
+func (UnimplementedAPI) GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*ResponseGetMetricHistory, error) {
+	return nil, notImplemented("GetMetricHistory")
+}
+
+func (UnimplementedAPI) ListArtifacts(ctx context.Context, runId string, path string) ([]FileInfo, error) {
+	return nil, notImplemented("ListArtifacts")
+}
+
+func (UnimplementedAPI) DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...DownloadOption) error {
+	return notImplemented("DownloadArtifacts")
+}
+
+func (UnimplementedAPI) OpenArtifact(ctx context.Context, runId string, path string) (io.ReadCloser, error) {
+	return nil, notImplemented("OpenArtifact")
+}
+
+func (UnimplementedAPI) LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error {
+	return notImplemented("LogArtifact")
+}
+
+func (UnimplementedAPI) LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error {
+	return notImplemented("LogArtifacts")
+}
+
+func (UnimplementedAPI) LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error {
+	return notImplemented("LogDict")
+}
+
+func (UnimplementedAPI) LogText(ctx context.Context, runId string, text string, artifactFile string) error {
+	return notImplemented("LogText")
+}
+
+func (UnimplementedAPI) LogImage(ctx context.Context, runId string, img image.Image, artifactFile string) error {
+	return notImplemented("LogImage")
+}
+
+func (UnimplementedAPI) LogTable(ctx context.Context, runId string, columns []string, rows [][]interface{}, artifactFile string) error {
+	return notImplemented("LogTable")
+}
+
+func (UnimplementedAPI) CreateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error) {
+	return nil, notImplemented("CreateRegisteredModel")
+}
+
+func (UnimplementedAPI) GetRegisteredModel(ctx context.Context, name string) (*RegisteredModel, error) {
+	return nil, notImplemented("GetRegisteredModel")
+}
+
+func (UnimplementedAPI) UpdateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error) {
+	return nil, notImplemented("UpdateRegisteredModel")
+}
+
+func (UnimplementedAPI) RenameRegisteredModel(ctx context.Context, name string, newName string) (*RegisteredModel, error) {
+	return nil, notImplemented("RenameRegisteredModel")
+}
+
+func (UnimplementedAPI) DeleteRegisteredModel(ctx context.Context, name string) error {
+	return notImplemented("DeleteRegisteredModel")
+}
+
+func (UnimplementedAPI) SearchRegisteredModels(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRegisteredModels, error) {
+	return nil, notImplemented("SearchRegisteredModels")
+}
+
+func (UnimplementedAPI) SetRegisteredModelTag(ctx context.Context, name string, key string, value string) error {
+	return notImplemented("SetRegisteredModelTag")
+}
+
+func (UnimplementedAPI) DeleteRegisteredModelTag(ctx context.Context, name string, key string) error {
+	return notImplemented("DeleteRegisteredModelTag")
+}
+
+func (UnimplementedAPI) SetRegisteredModelAlias(ctx context.Context, name string, alias string, version string) error {
+	return notImplemented("SetRegisteredModelAlias")
+}
+
+func (UnimplementedAPI) DeleteRegisteredModelAlias(ctx context.Context, name string, alias string) error {
+	return notImplemented("DeleteRegisteredModelAlias")
+}
+
+func (UnimplementedAPI) CreateModelVersion(ctx context.Context, name string, source string, runId string, tags ...ModelVersionTag) (*ModelVersion, error) {
+	return nil, notImplemented("CreateModelVersion")
+}
+
+func (UnimplementedAPI) GetModelVersion(ctx context.Context, name string, version string) (*ModelVersion, error) {
+	return nil, notImplemented("GetModelVersion")
+}
+
+func (UnimplementedAPI) UpdateModelVersion(ctx context.Context, name string, version string, description string) (*ModelVersion, error) {
+	return nil, notImplemented("UpdateModelVersion")
+}
+
+func (UnimplementedAPI) DeleteModelVersion(ctx context.Context, name string, version string) error {
+	return notImplemented("DeleteModelVersion")
+}
+
+func (UnimplementedAPI) SearchModelVersions(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchModelVersions, error) {
+	return nil, notImplemented("SearchModelVersions")
+}
+
+func (UnimplementedAPI) GetLatestVersions(ctx context.Context, name string, stages []string) ([]ModelVersion, error) {
+	return nil, notImplemented("GetLatestVersions")
+}
+
+func (UnimplementedAPI) GetModelVersionByAlias(ctx context.Context, name string, alias string) (*ModelVersion, error) {
+	return nil, notImplemented("GetModelVersionByAlias")
+}
+
+func (UnimplementedAPI) ResolveModelUri(ctx context.Context, uri string) (*ModelVersion, error) {
+	return nil, notImplemented("ResolveModelUri")
+}
+
+func (UnimplementedAPI) TransitionModelVersionStage(ctx context.Context, name string, version string, stage string, archiveExisting bool) (*ModelVersion, error) {
+	return nil, notImplemented("TransitionModelVersionStage")
+}
+
+func (UnimplementedAPI) WaitForModelVersion(ctx context.Context, name string, version string, interval time.Duration) (*ModelVersion, error) {
+	return nil, notImplemented("WaitForModelVersion")
+}
+
+func (UnimplementedAPI) SetModelVersionTag(ctx context.Context, name string, version string, key string, value string) error {
+	return notImplemented("SetModelVersionTag")
+}
+
+func (UnimplementedAPI) DeleteModelVersionTag(ctx context.Context, name string, version string, key string) error {
+	return notImplemented("DeleteModelVersionTag")
+}
+
+func (UnimplementedAPI) GetModelVersionDownloadUri(ctx context.Context, name string, version string) (string, error) {
+	return "", notImplemented("GetModelVersionDownloadUri")
+}
+
+func (UnimplementedAPI) DownloadModelVersion(ctx context.Context, name string, version string, localDir string, opts ...DownloadOption) error {
+	return notImplemented("DownloadModelVersion")
+}
+
+func (UnimplementedAPI) CopyModelVersion(ctx context.Context, srcName string, srcVersion string, dstName string) (*ModelVersion, error) {
+	return nil, notImplemented("CopyModelVersion")
+}
+
+func (UnimplementedAPI) StartTrace(ctx context.Context, experimentId string, tags map[string]string) (*ActiveTrace, error) {
+	return nil, notImplemented("StartTrace")
+}
+
+func (UnimplementedAPI) EndTrace(ctx context.Context, trace *ActiveTrace, status TraceStatus) (*TraceInfo, error) {
+	return nil, notImplemented("EndTrace")
+}
+
+func (UnimplementedAPI) SearchTraces(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchTraces, error) {
+	return nil, notImplemented("SearchTraces")
+}
+
+func (UnimplementedAPI) GetTraceInfo(ctx context.Context, requestId string) (*TraceInfo, error) {
+	return nil, notImplemented("GetTraceInfo")
+}
+
+func (UnimplementedAPI) GetTrace(ctx context.Context, requestId string) (*Trace, error) {
+	return nil, notImplemented("GetTrace")
+}
+
+func (UnimplementedAPI) DeleteTraces(ctx context.Context, experimentId string, maxTimestampMillis int64, maxTraces int, requestIds []string) (int, error) {
+	return 0, notImplemented("DeleteTraces")
+}
+
+func (UnimplementedAPI) SetTraceTag(ctx context.Context, requestId string, key string, value string) error {
+	return notImplemented("SetTraceTag")
+}
+
+func (UnimplementedAPI) DeleteTraceTag(ctx context.Context, requestId string, key string) error {
+	return notImplemented("DeleteTraceTag")
+}
+
+func (UnimplementedAPI) LogAssessment(ctx context.Context, traceId string, assessment Assessment) (*Assessment, error) {
+	return nil, notImplemented("LogAssessment")
+}
+
+func (UnimplementedAPI) LogFeedback(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource, rationale string) (*Assessment, error) {
+	return nil, notImplemented("LogFeedback")
+}
+
+func (UnimplementedAPI) LogExpectation(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource) (*Assessment, error) {
+	return nil, notImplemented("LogExpectation")
+}
+
+func (UnimplementedAPI) GetAssessment(ctx context.Context, traceId string, assessmentId string) (*Assessment, error) {
+	return nil, notImplemented("GetAssessment")
+}
+
+func (UnimplementedAPI) DeleteAssessment(ctx context.Context, traceId string, assessmentId string) error {
+	return notImplemented("DeleteAssessment")
+}
+
+func (UnimplementedAPI) CreateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error) {
+	return nil, notImplemented("CreateRegistryWebhook")
+}
+
+func (UnimplementedAPI) ListRegistryWebhooks(ctx context.Context, modelName string, events []RegistryWebhookEvent, pageToken string) (*ResponseListRegistryWebhooks, error) {
+	return nil, notImplemented("ListRegistryWebhooks")
+}
+
+func (UnimplementedAPI) TestRegistryWebhook(ctx context.Context, id string, event RegistryWebhookEvent) (*WebhookTestResult, error) {
+	return nil, notImplemented("TestRegistryWebhook")
+}
+
+func (UnimplementedAPI) UpdateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error) {
+	return nil, notImplemented("UpdateRegistryWebhook")
+}
+
+func (UnimplementedAPI) DeleteRegistryWebhook(ctx context.Context, id string) error {
+	return notImplemented("DeleteRegistryWebhook")
+}
+
+func (UnimplementedAPI) CreateLoggedModel(ctx context.Context, experimentId string, name string, modelType string, sourceRunId string, params map[string]string, tags map[string]string) (*LoggedModel, error) {
+	return nil, notImplemented("CreateLoggedModel")
+}
+
+func (UnimplementedAPI) GetLoggedModel(ctx context.Context, modelId string) (*LoggedModel, error) {
+	return nil, notImplemented("GetLoggedModel")
+}
+
+func (UnimplementedAPI) DeleteLoggedModel(ctx context.Context, modelId string) error {
+	return notImplemented("DeleteLoggedModel")
+}
+
+func (UnimplementedAPI) FinalizeLoggedModel(ctx context.Context, modelId string, status LoggedModelStatus) (*LoggedModel, error) {
+	return nil, notImplemented("FinalizeLoggedModel")
+}
+
+func (UnimplementedAPI) SearchLoggedModels(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []LoggedModelOrderBy, pageToken string) (*ResponseSearchLoggedModels, error) {
+	return nil, notImplemented("SearchLoggedModels")
+}
+
+func (UnimplementedAPI) SetLoggedModelTags(ctx context.Context, modelId string, tags map[string]string) error {
+	return notImplemented("SetLoggedModelTags")
+}
+
+func (UnimplementedAPI) DeleteLoggedModelTag(ctx context.Context, modelId string, key string) error {
+	return notImplemented("DeleteLoggedModelTag")
+}
+
+func (UnimplementedAPI) LogLoggedModelParams(ctx context.Context, modelId string, params map[string]string) error {
+	return notImplemented("LogLoggedModelParams")
+}
+
+func (UnimplementedAPI) LogLoggedModelMetric(ctx context.Context, modelId string, runId string, key string, value float64, timestamp int64, step int64) error {
+	return notImplemented("LogLoggedModelMetric")
+}
+
+func (UnimplementedAPI) RegisterPrompt(ctx context.Context, name string, template string, commitMessage string, tags map[string]string) (*PromptVersion, error) {
+	return nil, notImplemented("RegisterPrompt")
+}
+
+func (UnimplementedAPI) GetPrompt(ctx context.Context, name string) (*Prompt, error) {
+	return nil, notImplemented("GetPrompt")
+}
+
+func (UnimplementedAPI) GetPromptVersion(ctx context.Context, name string, version string) (*PromptVersion, error) {
+	return nil, notImplemented("GetPromptVersion")
+}
+
+func (UnimplementedAPI) LoadPrompt(ctx context.Context, uri string) (*PromptVersion, error) {
+	return nil, notImplemented("LoadPrompt")
+}
+
+func (UnimplementedAPI) SearchPrompts(ctx context.Context, filter string, maxResults int, pageToken string) ([]Prompt, string, error) {
+	return nil, "", notImplemented("SearchPrompts")
+}
+
+func (UnimplementedAPI) SetPromptAlias(ctx context.Context, name string, alias string, version string) error {
+	return notImplemented("SetPromptAlias")
+}
+
+func (UnimplementedAPI) DeletePromptAlias(ctx context.Context, name string, alias string) error {
+	return notImplemented("DeletePromptAlias")
+}
+
+func (UnimplementedAPI) DeletePromptVersion(ctx context.Context, name string, version string) error {
+	return notImplemented("DeletePromptVersion")
+}
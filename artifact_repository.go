@@ -0,0 +1,367 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ArtifactRepository reads and writes the artifacts stored under one
+// artifact root, such as a run's artifact_uri. Artifact paths are
+// slash-separated and relative to that root.
+type ArtifactRepository interface {
+	Upload(ctx context.Context, localPath string, artifactPath string) error
+	Download(ctx context.Context, artifactPath string, localPath string) error
+	List(ctx context.Context, artifactPath string) ([]FileInfo, error)
+	Delete(ctx context.Context, artifactPath string) error
+}
+
+type ArtifactRepositoryFactory func(client *Client, artifactUri string) (ArtifactRepository, error)
+
+var (
+	artifactRepositoriesMu sync.RWMutex
+	artifactRepositories   = map[string]ArtifactRepositoryFactory{
+		"":                 newLocalArtifactRepository,
+		"file":             newLocalArtifactRepository,
+		"mlflow-artifacts": newProxyArtifactRepository,
+		"s3":               newS3ArtifactRepository,
+		"gs":               newGcsArtifactRepository,
+		"wasbs":            newAzureArtifactRepository,
+		"wasb":             newAzureArtifactRepository,
+	}
+)
+
+// RegisterArtifactRepository makes factory responsible for artifact uris
+// with the given scheme, replacing any built-in implementation.
+func RegisterArtifactRepository(scheme string, factory ArtifactRepositoryFactory) {
+	artifactRepositoriesMu.Lock()
+	defer artifactRepositoriesMu.Unlock()
+	artifactRepositories[scheme] = factory
+}
+
+func (p *Client) ArtifactRepository(artifactUri string) (ArtifactRepository, error) {
+	scheme := ""
+	if u, err := url.Parse(artifactUri); err == nil && len(u.Scheme) > 1 {
+		// Single letter schemes are Windows drive letters.
+		scheme = u.Scheme
+	}
+	artifactRepositoriesMu.RLock()
+	factory, ok := artifactRepositories[scheme]
+	artifactRepositoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("mlflow: no artifact repository for scheme %q", scheme)
+	}
+	return factory(p, artifactUri)
+}
+
+func (p *Client) RunArtifactRepository(ctx context.Context, runId string) (ArtifactRepository, error) {
+	run, err := p.GetRun(ctx, runId)
+	if err != nil {
+		return nil, err
+	}
+	return p.ArtifactRepository(run.Info.ArtifactUri)
+}
+
+// joinArtifactPath joins an artifact path under a storage prefix, rejecting
+// paths that would escape it.
+func joinArtifactPath(prefix string, artifactPath string) (string, error) {
+	for _, segment := range strings.Split(artifactPath, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("mlflow: invalid artifact path %s", artifactPath)
+		}
+	}
+	return strings.Trim(path.Join(prefix, artifactPath), "/"), nil
+}
+
+// localArtifactPath returns where artifactPath, a path that came from the
+// server, is downloaded under localDir. Absolute paths and paths escaping
+// localDir are rejected, so that a listing cannot write anywhere else.
+func localArtifactPath(localDir string, artifactPath string) (string, error) {
+	if path.IsAbs(artifactPath) || filepath.IsAbs(filepath.FromSlash(artifactPath)) || filepath.VolumeName(filepath.FromSlash(artifactPath)) != "" {
+		return "", fmt.Errorf("mlflow: invalid artifact path %s", artifactPath)
+	}
+	if _, err := joinArtifactPath("", artifactPath); err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(localDir, filepath.FromSlash(artifactPath))
+	rel, err := filepath.Rel(localDir, localPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("mlflow: artifact path %s is outside of %s", artifactPath, localDir)
+	}
+	return localPath, nil
+}
+
+func writeLocalFile(localPath string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	out, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), localPath)
+}
+
+func openLocalFile(localPath string) (*os.File, int64, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// storageRequest sends a request to an object store and fails on any
+// non-2xx status. The caller owns the returned body.
+func storageRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("mlflow: %s %s returned status %d: %s", req.Method, req.URL.Redacted(), resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return resp, nil
+}
+
+type localArtifactRepository struct {
+	root string
+}
+
+func newLocalArtifactRepository(client *Client, artifactUri string) (ArtifactRepository, error) {
+	root := artifactUri
+	if strings.HasPrefix(artifactUri, "file:") {
+		u, err := url.Parse(artifactUri)
+		if err != nil {
+			return nil, err
+		}
+		root = u.Path
+	}
+	if root == "" {
+		// An empty root would read and write the current directory.
+		return nil, fmt.Errorf("mlflow: artifact uri %q has no path", artifactUri)
+	}
+	return &localArtifactRepository{root: filepath.FromSlash(root)}, nil
+}
+
+func (r *localArtifactRepository) path(artifactPath string) (string, error) {
+	rel, err := joinArtifactPath("", artifactPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(r.root, filepath.FromSlash(rel)), nil
+}
+
+func (r *localArtifactRepository) Upload(ctx context.Context, localPath string, artifactPath string) error {
+	dst, err := r.path(artifactPath)
+	if err != nil {
+		return err
+	}
+	file, _, err := openLocalFile(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeLocalFile(dst, file)
+}
+
+func (r *localArtifactRepository) Download(ctx context.Context, artifactPath string, localPath string) error {
+	src, err := r.path(artifactPath)
+	if err != nil {
+		return err
+	}
+	file, _, err := openLocalFile(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeLocalFile(localPath, file)
+}
+
+func (r *localArtifactRepository) List(ctx context.Context, artifactPath string) ([]FileInfo, error) {
+	dir, err := r.path(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		file := FileInfo{Path: path.Join(artifactPath, entry.Name()), IsDir: entry.IsDir()}
+		if !entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			file.FileSize = info.Size()
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (r *localArtifactRepository) Delete(ctx context.Context, artifactPath string) error {
+	target, err := r.path(artifactPath)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(target)
+}
+
+// proxyArtifactRepository talks to the tracking server's mlflow-artifacts
+// proxy, available when the server runs with --serve-artifacts.
+type proxyArtifactRepository struct {
+	client *Client
+	root   string
+}
+
+func newProxyArtifactRepository(client *Client, artifactUri string) (ArtifactRepository, error) {
+	if err := client.requireArtifactProxy(); err != nil {
+		return nil, err
+	}
+	root, err := artifactProxyPath(artifactUri, "")
+	if err != nil {
+		return nil, err
+	}
+	return &proxyArtifactRepository{client: client, root: root}, nil
+}
+
+func (r *proxyArtifactRepository) url(artifactPath string) (string, error) {
+	proxyPath, err := joinArtifactPath(r.root, artifactPath)
+	if err != nil {
+		return "", err
+	}
+	return r.client.artifactProxyUrl("mlflow-artifacts:/"+proxyPath, "")
+}
+
+func (r *proxyArtifactRepository) Upload(ctx context.Context, localPath string, artifactPath string) error {
+	url, err := r.url(artifactPath)
+	if err != nil {
+		return err
+	}
+	file, size, err := openLocalFile(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return r.client.putStream(ctx, url, file, size)
+}
+
+func (r *proxyArtifactRepository) Download(ctx context.Context, artifactPath string, localPath string) error {
+	url, err := r.url(artifactPath)
+	if err != nil {
+		return err
+	}
+	body, err := r.client.getStream(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return writeLocalFile(localPath, body)
+}
+
+func (r *proxyArtifactRepository) List(ctx context.Context, artifactPath string) ([]FileInfo, error) {
+	proxyPath, err := joinArtifactPath(r.root, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	body, err := r.client.getStream(ctx, r.client.BaseUrl+"/api/2.0/mlflow-artifacts/artifacts", map[string]interface{}{"path": proxyPath})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var response struct {
+		Files []FileInfo `json:"files"`
+	}
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	// The proxy reports names relative to the listed directory.
+	for i := range response.Files {
+		response.Files[i].Path = path.Join(artifactPath, response.Files[i].Path)
+	}
+	return response.Files, nil
+}
+
+func (r *proxyArtifactRepository) Delete(ctx context.Context, artifactPath string) error {
+	url, err := r.url(artifactPath)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return readError(resp)
+	}
+	return nil
+}
+
+// uploadBytes stores data as artifactPath through a temporary file, since
+// repositories upload from disk.
+func uploadBytes(ctx context.Context, repo ArtifactRepository, data []byte, artifactPath string) error {
+	dir, err := os.MkdirTemp("", "mlflow-artifact-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	localPath := filepath.Join(dir, path.Base(artifactPath))
+	if err := os.WriteFile(localPath, data, 0644); err != nil {
+		return err
+	}
+	return repo.Upload(ctx, localPath, artifactPath)
+}
+
+// walkRepository lists the files under artifactPath recursively.
+func walkRepository(ctx context.Context, repo ArtifactRepository, artifactPath string) ([]string, error) {
+	files, err := repo.List(ctx, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, file := range files {
+		if !file.IsDir {
+			paths = append(paths, file.Path)
+			continue
+		}
+		children, err := walkRepository(ctx, repo, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, children...)
+	}
+	return paths, nil
+}
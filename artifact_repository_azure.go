@@ -0,0 +1,291 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const azureStorageVersion = "2020-10-02"
+
+// azureArtifactRepository stores artifacts in Azure Blob Storage for
+// wasbs://<container>@<account>.blob.core.windows.net/<path> uris. It
+// authenticates with AZURE_STORAGE_CONNECTION_STRING (account key or SAS) or
+// AZURE_STORAGE_ACCESS_KEY, like the Python client.
+type azureArtifactRepository struct {
+	client     *http.Client
+	endpoint   string
+	account    string
+	container  string
+	prefix     string
+	accountKey []byte
+	sasToken   string
+}
+
+func newAzureArtifactRepository(client *Client, artifactUri string) (ArtifactRepository, error) {
+	u, err := url.Parse(artifactUri)
+	if err != nil {
+		return nil, err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, errors.New("mlflow: azure artifact uri must look like wasbs://<container>@<account>.blob.core.windows.net/<path>")
+	}
+	r := &azureArtifactRepository{
+		client:    client.Client,
+		endpoint:  "https://" + u.Host,
+		account:   strings.SplitN(u.Host, ".", 2)[0],
+		container: u.User.Username(),
+		prefix:    strings.Trim(u.Path, "/"),
+	}
+	key := os.Getenv("AZURE_STORAGE_ACCESS_KEY")
+	if connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connectionString != "" {
+		for _, part := range strings.Split(connectionString, ";") {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "AccountKey":
+				key = kv[1]
+			case "SharedAccessSignature":
+				r.sasToken = strings.TrimPrefix(kv[1], "?")
+			case "BlobEndpoint":
+				r.endpoint = strings.TrimSuffix(kv[1], "/")
+			}
+		}
+	}
+	if key != "" {
+		r.accountKey, err = base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.New("mlflow: azure storage account key is not valid base64")
+		}
+	}
+	return r, nil
+}
+
+func (r *azureArtifactRepository) blobUrl(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return r.endpoint + "/" + url.PathEscape(r.container) + "/" + strings.Join(segments, "/")
+}
+
+func (r *azureArtifactRepository) do(ctx context.Context, method string, rawUrl string, query url.Values, body io.Reader, size int64, headers map[string]string) (*http.Response, error) {
+	if r.sasToken != "" {
+		sas, err := url.ParseQuery(r.sasToken)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range sas {
+			query[key] = values
+		}
+	}
+	if len(query) > 0 {
+		rawUrl += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if r.accountKey != nil && r.sasToken == "" {
+		r.sign(req)
+	}
+	return storageRequest(r.client, req)
+}
+
+// sign applies Shared Key authorization as described in the Azure Storage
+// REST documentation.
+func (r *azureArtifactRepository) sign(req *http.Request) {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	var msHeaders []string
+	for key := range req.Header {
+		if lower := strings.ToLower(key); strings.HasPrefix(lower, "x-ms-") {
+			msHeaders = append(msHeaders, lower)
+		}
+	}
+	sort.Strings(msHeaders)
+	var canonicalHeaders strings.Builder
+	for _, key := range msHeaders {
+		canonicalHeaders.WriteString(key + ":" + strings.TrimSpace(req.Header.Get(key)) + "\n")
+	}
+	canonicalResource := "/" + r.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		canonicalResource += "\n" + strings.ToLower(key) + ":" + strings.Join(values, ",")
+	}
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"",
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalHeaders.String() + canonicalResource,
+	}, "\n")
+	mac := hmac.New(sha256.New, r.accountKey)
+	mac.Write([]byte(stringToSign))
+	req.Header.Set("Authorization", "SharedKey "+r.account+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+func (r *azureArtifactRepository) Upload(ctx context.Context, localPath string, artifactPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	file, size, err := openLocalFile(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var body io.Reader = file
+	if size == 0 {
+		body = http.NoBody
+	}
+	resp, err := r.do(ctx, "PUT", r.blobUrl(name), url.Values{}, body, size, map[string]string{"x-ms-blob-type": "BlockBlob", "Content-Type": "application/octet-stream"})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (r *azureArtifactRepository) Download(ctx context.Context, artifactPath string, localPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(ctx, "GET", r.blobUrl(name), url.Values{}, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return writeLocalFile(localPath, resp.Body)
+}
+
+type azureListResult struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				ContentLength int64 `xml:"Content-Length"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+		BlobPrefix []struct {
+			Name string `xml:"Name"`
+		} `xml:"BlobPrefix"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (r *azureArtifactRepository) listBlobs(ctx context.Context, prefix string, delimiter string, fn func(result *azureListResult)) error {
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := r.do(ctx, "GET", r.endpoint+"/"+url.PathEscape(r.container), query, nil, 0, nil)
+		if err != nil {
+			return err
+		}
+		var result azureListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		fn(&result)
+		if result.NextMarker == "" {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+func (r *azureArtifactRepository) List(ctx context.Context, artifactPath string) ([]FileInfo, error) {
+	dir, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		dir += "/"
+	}
+	var files []FileInfo
+	err = r.listBlobs(ctx, dir, "/", func(result *azureListResult) {
+		for _, prefix := range result.Blobs.BlobPrefix {
+			name := path.Base(strings.TrimSuffix(prefix.Name, "/"))
+			files = append(files, FileInfo{Path: path.Join(artifactPath, name), IsDir: true})
+		}
+		for _, blob := range result.Blobs.Blob {
+			if blob.Name == dir {
+				continue
+			}
+			files = append(files, FileInfo{Path: path.Join(artifactPath, path.Base(blob.Name)), FileSize: blob.Properties.ContentLength})
+		}
+	})
+	return files, err
+}
+
+func (r *azureArtifactRepository) Delete(ctx context.Context, artifactPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	var names []string
+	err = r.listBlobs(ctx, name, "", func(result *azureListResult) {
+		for _, blob := range result.Blobs.Blob {
+			if blob.Name == name || strings.HasPrefix(blob.Name, name+"/") {
+				names = append(names, blob.Name)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		resp, err := r.do(ctx, "DELETE", r.blobUrl(name), url.Values{}, nil, 0, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const gcsMetadataTokenUrl = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// gcsArtifactRepository stores artifacts in Google Cloud Storage through the
+// JSON API. The access token comes from GOOGLE_OAUTH_ACCESS_TOKEN or, when
+// unset, from the GCE/GKE metadata server.
+type gcsArtifactRepository struct {
+	client *http.Client
+	bucket string
+	prefix string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newGcsArtifactRepository(client *Client, artifactUri string) (ArtifactRepository, error) {
+	u, err := url.Parse(artifactUri)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsArtifactRepository{
+		client: client.Client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (r *gcsArtifactRepository) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != "" && time.Now().Before(r.expires) {
+		return r.token, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", gcsMetadataTokenUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := storageRequest(r.client, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("mlflow: metadata server returned no access token")
+	}
+	r.token = token.AccessToken
+	r.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return r.token, nil
+}
+
+func (r *gcsArtifactRepository) do(ctx context.Context, method string, rawUrl string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	token, err := r.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return storageRequest(r.client, req)
+}
+
+func (r *gcsArtifactRepository) objectUrl(name string) string {
+	return "https://storage.googleapis.com/storage/v1/b/" + url.PathEscape(r.bucket) + "/o/" + url.PathEscape(name)
+}
+
+func (r *gcsArtifactRepository) Upload(ctx context.Context, localPath string, artifactPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	file, size, err := openLocalFile(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var body io.Reader = file
+	if size == 0 {
+		body = http.NoBody
+	}
+	query := url.Values{"uploadType": {"media"}, "name": {name}}
+	resp, err := r.do(ctx, "POST", "https://storage.googleapis.com/upload/storage/v1/b/"+url.PathEscape(r.bucket)+"/o?"+query.Encode(), body, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (r *gcsArtifactRepository) Download(ctx context.Context, artifactPath string, localPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(ctx, "GET", r.objectUrl(name)+"?alt=media", nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return writeLocalFile(localPath, resp.Body)
+}
+
+type gcsListResult struct {
+	Items []struct {
+		Name string `json:"name"`
+		Size int64  `json:"size,string"`
+	} `json:"items"`
+	Prefixes      []string `json:"prefixes"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+func (r *gcsArtifactRepository) listObjects(ctx context.Context, prefix string, delimiter string, fn func(result *gcsListResult)) error {
+	pageToken := ""
+	for {
+		query := url.Values{"prefix": {prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		resp, err := r.do(ctx, "GET", "https://storage.googleapis.com/storage/v1/b/"+url.PathEscape(r.bucket)+"/o?"+query.Encode(), nil, 0)
+		if err != nil {
+			return err
+		}
+		var result gcsListResult
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		fn(&result)
+		if result.NextPageToken == "" {
+			return nil
+		}
+		pageToken = result.NextPageToken
+	}
+}
+
+func (r *gcsArtifactRepository) List(ctx context.Context, artifactPath string) ([]FileInfo, error) {
+	dir, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		dir += "/"
+	}
+	var files []FileInfo
+	err = r.listObjects(ctx, dir, "/", func(result *gcsListResult) {
+		for _, prefix := range result.Prefixes {
+			name := path.Base(strings.TrimSuffix(prefix, "/"))
+			files = append(files, FileInfo{Path: path.Join(artifactPath, name), IsDir: true})
+		}
+		for _, item := range result.Items {
+			if item.Name == dir {
+				continue
+			}
+			files = append(files, FileInfo{Path: path.Join(artifactPath, path.Base(item.Name)), FileSize: item.Size})
+		}
+	})
+	return files, err
+}
+
+func (r *gcsArtifactRepository) Delete(ctx context.Context, artifactPath string) error {
+	name, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	var names []string
+	err = r.listObjects(ctx, name, "", func(result *gcsListResult) {
+		for _, item := range result.Items {
+			if item.Name == name || strings.HasPrefix(item.Name, name+"/") {
+				names = append(names, item.Name)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		resp, err := r.do(ctx, "DELETE", r.objectUrl(name), nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// s3ArtifactRepository stores artifacts in S3 or an S3 compatible store
+// such as MinIO, configured the same way as the Python client: credentials
+// and region from the standard AWS variables and MLFLOW_S3_ENDPOINT_URL for
+// custom endpoints.
+type s3ArtifactRepository struct {
+	client   *http.Client
+	bucket   string
+	prefix   string
+	endpoint string
+	region   string
+	creds    awsCredentials
+}
+
+func newS3ArtifactRepository(client *Client, artifactUri string) (ArtifactRepository, error) {
+	u, err := url.Parse(artifactUri)
+	if err != nil {
+		return nil, err
+	}
+	return &s3ArtifactRepository{
+		client:   client.Client,
+		bucket:   u.Host,
+		prefix:   strings.Trim(u.Path, "/"),
+		endpoint: strings.TrimSuffix(os.Getenv("MLFLOW_S3_ENDPOINT_URL"), "/"),
+		region:   awsRegionFromEnv(),
+		creds:    awsCredentialsFromEnv(),
+	}, nil
+}
+
+func (r *s3ArtifactRepository) objectUrl(key string) string {
+	escaped := awsEscape(key, false)
+	if r.endpoint != "" {
+		return r.endpoint + "/" + r.bucket + "/" + escaped
+	}
+	return "https://" + r.bucket + ".s3." + r.region + ".amazonaws.com/" + escaped
+}
+
+func (r *s3ArtifactRepository) do(ctx context.Context, method string, rawUrl string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := emptyPayloadHash
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = unsignedPayloadHash
+	}
+	if r.creds.AccessKeyId != "" {
+		signV4(req, payloadHash, r.creds, r.region, "s3", time.Now())
+	}
+	return storageRequest(r.client, req)
+}
+
+func (r *s3ArtifactRepository) Upload(ctx context.Context, localPath string, artifactPath string) error {
+	key, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	file, size, err := openLocalFile(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var body io.Reader = file
+	if size == 0 {
+		body = http.NoBody
+	}
+	resp, err := r.do(ctx, "PUT", r.objectUrl(key), body, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (r *s3ArtifactRepository) Download(ctx context.Context, artifactPath string, localPath string) error {
+	key, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(ctx, "GET", r.objectUrl(key), nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return writeLocalFile(localPath, resp.Body)
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (r *s3ArtifactRepository) listObjects(ctx context.Context, prefix string, delimiter string, fn func(result *s3ListResult) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := r.do(ctx, "GET", r.objectUrl("")+"?"+query.Encode(), nil, 0)
+		if err != nil {
+			return err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if err := fn(&result); err != nil {
+			return err
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (r *s3ArtifactRepository) List(ctx context.Context, artifactPath string) ([]FileInfo, error) {
+	dir, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		dir += "/"
+	}
+	var files []FileInfo
+	err = r.listObjects(ctx, dir, "/", func(result *s3ListResult) error {
+		for _, prefix := range result.CommonPrefixes {
+			name := path.Base(strings.TrimSuffix(prefix.Prefix, "/"))
+			files = append(files, FileInfo{Path: path.Join(artifactPath, name), IsDir: true})
+		}
+		for _, object := range result.Contents {
+			if object.Key == dir {
+				continue
+			}
+			files = append(files, FileInfo{Path: path.Join(artifactPath, path.Base(object.Key)), FileSize: object.Size})
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (r *s3ArtifactRepository) Delete(ctx context.Context, artifactPath string) error {
+	key, err := joinArtifactPath(r.prefix, artifactPath)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	err = r.listObjects(ctx, key, "", func(result *s3ListResult) error {
+		for _, object := range result.Contents {
+			if object.Key == key || strings.HasPrefix(object.Key, key+"/") {
+				keys = append(keys, object.Key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		resp, err := r.do(ctx, "DELETE", r.objectUrl(key), nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalArtifactRepository(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	repo, err := New("http://localhost:5000").ArtifactRepository("file://" + filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "MLmodel")
+	os.WriteFile(src, []byte("flavors"), 0644)
+	if err := repo.Upload(ctx, src, "model/MLmodel"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := repo.List(ctx, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "model/MLmodel" || files[0].FileSize != 7 {
+		t.Errorf("unexpected files %+v", files)
+	}
+	dst := filepath.Join(t.TempDir(), "copy")
+	if err := repo.Download(ctx, "model/MLmodel", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "flavors" {
+		t.Errorf("Expected downloaded content flavors, got %q", b)
+	}
+	if err := repo.Upload(ctx, src, "../escape"); err == nil {
+		t.Error("Expected paths escaping the root to be rejected")
+	}
+	if err := repo.Delete(ctx, "model"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "model")); !os.IsNotExist(err) {
+		t.Errorf("Expected model directory to be deleted, got %v", err)
+	}
+	for _, uri := range []string{"", "file://"} {
+		if _, err := New("http://localhost:5000").ArtifactRepository(uri); err == nil {
+			t.Errorf("Expected artifact uri %q to be rejected", uri)
+		}
+	}
+}
+
+func TestS3ArtifactRepository(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("unsigned request %s %s", r.Method, r.URL)
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch r.Method {
+		case "PUT":
+			b, _ := io.ReadAll(r.Body)
+			objects[key] = string(b)
+		case "GET":
+			if r.URL.Query().Get("list-type") == "2" {
+				w.Write([]byte(`<ListBucketResult><Contents><Key>runs/1/artifacts/model/MLmodel</Key><Size>7</Size></Contents><CommonPrefixes><Prefix>runs/1/artifacts/model/data/</Prefix></CommonPrefixes></ListBucketResult>`))
+				return
+			}
+			w.Write([]byte(objects[key]))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("MLFLOW_S3_ENDPOINT_URL", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	ctx := context.Background()
+	repo, err := New("http://localhost:5000").ArtifactRepository("s3://bucket/runs/1/artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "MLmodel")
+	os.WriteFile(src, []byte("flavors"), 0644)
+	if err := repo.Upload(ctx, src, "model/MLmodel"); err != nil {
+		t.Fatal(err)
+	}
+	if objects["runs/1/artifacts/model/MLmodel"] != "flavors" {
+		t.Errorf("unexpected objects %v", objects)
+	}
+	files, err := repo.List(ctx, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "model/data" || !files[0].IsDir || files[1].Path != "model/MLmodel" || files[1].FileSize != 7 {
+		t.Errorf("unexpected files %+v", files)
+	}
+}
+
+// rewriteTransport sends every request to server, keeping its path and
+// query, so that repositories with fixed endpoints can be tested.
+type rewriteTransport struct {
+	server *httptest.Server
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(t.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return t.server.Client().Transport.RoundTrip(req)
+}
+
+func TestGcsArtifactRepository(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unauthorized request %s %s", r.Method, r.URL)
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/upload/storage/v1/b/bucket/o":
+			b, _ := io.ReadAll(r.Body)
+			objects[r.URL.Query().Get("name")] = string(b)
+		case r.Method == "GET" && r.URL.Path == "/storage/v1/b/bucket/o":
+			if r.URL.Query().Get("pageToken") == "" {
+				w.Write([]byte(`{"prefixes": ["runs/1/artifacts/model/data/"], "nextPageToken": "next"}`))
+				return
+			}
+			w.Write([]byte(`{"items": [{"name": "runs/1/artifacts/model/MLmodel", "size": "7"}]}`))
+		case r.Method == "GET":
+			w.Write([]byte(objects[strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/")]))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+
+	ctx := context.Background()
+	client := New("http://localhost:5000")
+	client.Client = &http.Client{Transport: rewriteTransport{server}}
+	repo, err := client.ArtifactRepository("gs://bucket/runs/1/artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "MLmodel")
+	os.WriteFile(src, []byte("flavors"), 0644)
+	if err := repo.Upload(ctx, src, "model/MLmodel"); err != nil {
+		t.Fatal(err)
+	}
+	if objects["runs/1/artifacts/model/MLmodel"] != "flavors" {
+		t.Errorf("unexpected objects %v", objects)
+	}
+	files, err := repo.List(ctx, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "model/data" || !files[0].IsDir || files[1].Path != "model/MLmodel" || files[1].FileSize != 7 {
+		t.Errorf("unexpected files %+v", files)
+	}
+	dst := filepath.Join(t.TempDir(), "MLmodel")
+	if err := repo.Download(ctx, "model/MLmodel", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "flavors" {
+		t.Errorf("Expected downloaded content flavors, got %q", b)
+	}
+}
+
+func TestAzureArtifactRepository(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey account:") || r.Header.Get("x-ms-version") == "" {
+			t.Errorf("unsigned request %s %s", r.Method, r.URL)
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/container/")
+		switch r.Method {
+		case "PUT":
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				t.Errorf("unexpected blob type %q", r.Header.Get("x-ms-blob-type"))
+			}
+			b, _ := io.ReadAll(r.Body)
+			objects[key] = string(b)
+		case "GET":
+			if r.URL.Query().Get("comp") == "list" {
+				w.Write([]byte(`<EnumerationResults><Blobs><BlobPrefix><Name>runs/1/artifacts/model/data/</Name></BlobPrefix><Blob><Name>runs/1/artifacts/model/MLmodel</Name><Properties><Content-Length>7</Content-Length></Properties></Blob></Blobs><NextMarker/></EnumerationResults>`))
+				return
+			}
+			w.Write([]byte(objects[key]))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AZURE_STORAGE_CONNECTION_STRING", "AccountName=account;AccountKey="+base64.StdEncoding.EncodeToString([]byte("key"))+";BlobEndpoint="+server.URL)
+
+	ctx := context.Background()
+	repo, err := New("http://localhost:5000").ArtifactRepository("wasbs://container@account.blob.core.windows.net/runs/1/artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "MLmodel")
+	os.WriteFile(src, []byte("flavors"), 0644)
+	if err := repo.Upload(ctx, src, "model/MLmodel"); err != nil {
+		t.Fatal(err)
+	}
+	if objects["runs/1/artifacts/model/MLmodel"] != "flavors" {
+		t.Errorf("unexpected objects %v", objects)
+	}
+	files, err := repo.List(ctx, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "model/data" || !files[0].IsDir || files[1].Path != "model/MLmodel" || files[1].FileSize != 7 {
+		t.Errorf("unexpected files %+v", files)
+	}
+	if _, err := New("http://localhost:5000").ArtifactRepository("wasbs://account.blob.core.windows.net/path"); err == nil {
+		t.Error("Expected an azure uri without a container to be rejected")
+	}
+}
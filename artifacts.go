@@ -0,0 +1,599 @@
+package mlflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type FileInfo struct {
+	Path     string `json:"path"`
+	IsDir    bool   `json:"is_dir"`
+	FileSize int64  `json:"file_size,omitempty"`
+}
+
+type ResponseListArtifacts struct {
+	RootUri       string     `json:"root_uri"`
+	Files         []FileInfo `json:"files"`
+	NextPageToken string     `json:"next_page_token,omitempty"`
+}
+
+func (p *Client) ListArtifacts(ctx context.Context, runId string, path string) ([]FileInfo, error) {
+	return p.IterArtifacts(ctx, runId, path).All()
+}
+
+func (p *Client) listArtifacts(ctx context.Context, runId string, path string, pageToken string) (*ResponseListArtifacts, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/artifacts/list"
+	params := map[string]interface{}{"run_id": runId}
+	if path != "" {
+		params["path"] = path
+	}
+	if pageToken != "" {
+		params["page_token"] = pageToken
+	}
+	body, err := p.HandleGet(ctx, url, params)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseListArtifacts
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+type downloadOptions struct {
+	concurrency int
+	attempts    int
+}
+
+type DownloadOption func(*downloadOptions)
+
+// WithConcurrency downloads up to n files at the same time.
+func WithConcurrency(n int) DownloadOption {
+	return func(o *downloadOptions) {
+		if n > 0 {
+			o.concurrency = n
+		}
+	}
+}
+
+// WithFileAttempts tries each file up to n times before giving up on it.
+func WithFileAttempts(n int) DownloadOption {
+	return func(o *downloadOptions) {
+		if n > 0 {
+			o.attempts = n
+		}
+	}
+}
+
+// MultiError collects the failures of an operation that carries on past
+// individual errors.
+type MultiError []error
+
+func (e MultiError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// DownloadArtifacts downloads the artifact at remotePath, recursing into
+// directories, to the same relative path under localDir. Files that fail
+// after all attempts don't stop the others; their errors are returned
+// together as a MultiError.
+func (p *Client) DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...DownloadOption) error {
+	options := downloadOptions{concurrency: 1, attempts: 3}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	paths, err := p.walkArtifacts(ctx, runId, remotePath)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := localArtifactPath(localDir, path); err != nil {
+			return err
+		}
+	}
+
+	return downloadAll(ctx, paths, options, func(path string) error {
+		return p.downloadArtifact(ctx, runId, path, localDir)
+	})
+}
+
+// downloadAll runs fetch for every path on a pool of options.concurrency
+// workers, retrying each path up to options.attempts times.
+func downloadAll(ctx context.Context, paths []string, options downloadOptions, fetch func(path string) error) error {
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var errs MultiError
+	var wg sync.WaitGroup
+	for i := 0; i < options.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				var err error
+				for attempt := 0; attempt < options.attempts; attempt++ {
+					if attempt > 0 {
+						select {
+						case <-ctx.Done():
+						case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+						}
+					}
+					if err = ctx.Err(); err != nil {
+						break
+					}
+					if err = fetch(path); err == nil {
+						break
+					}
+				}
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("mlflow: downloading %s: %w", path, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// walkArtifacts lists the files under remotePath recursively.
+func (p *Client) walkArtifacts(ctx context.Context, runId string, remotePath string) ([]string, error) {
+	files, err := p.ListArtifacts(ctx, runId, remotePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 && remotePath != "" {
+		// Listing a file path yields no entries, so fetch it directly.
+		return []string{remotePath}, nil
+	}
+	var paths []string
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !file.IsDir {
+			paths = append(paths, file.Path)
+			continue
+		}
+		children, err := p.walkArtifacts(ctx, runId, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, children...)
+	}
+	return paths, nil
+}
+
+// OpenArtifact streams the content of a run's artifact file. The caller must
+// close the returned reader.
+func (p *Client) OpenArtifact(ctx context.Context, runId string, path string) (io.ReadCloser, error) {
+	url := p.BaseUrl + "/get-artifact"
+	return p.getStream(ctx, url, map[string]interface{}{"run_id": runId, "path": path})
+}
+
+func (p *Client) downloadArtifact(ctx context.Context, runId string, path string, localDir string) error {
+	localPath, err := localArtifactPath(localDir, path)
+	if err != nil {
+		return err
+	}
+	body, err := p.OpenArtifact(ctx, runId, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return writeLocalFile(localPath, body)
+}
+
+// artifactProxyPath resolves a path under a run's mlflow-artifacts:/ root to
+// the path understood by the tracking server's artifact proxy.
+func artifactProxyPath(artifactUri string, artifactPath string) (string, error) {
+	u, err := url.Parse(artifactUri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "mlflow-artifacts" {
+		return "", fmt.Errorf("mlflow: artifact uri %s is not served by the tracking server artifact proxy", artifactUri)
+	}
+	return strings.Trim(path.Join(u.Path, artifactPath), "/"), nil
+}
+
+func (p *Client) artifactProxyUrl(artifactUri string, artifactPath string) (string, error) {
+	proxyPath, err := artifactProxyPath(artifactUri, artifactPath)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(proxyPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return p.BaseUrl + "/api/2.0/mlflow-artifacts/artifacts/" + strings.Join(segments, "/"), nil
+}
+
+func (p *Client) LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error {
+	repo, err := p.RunArtifactRepository(ctx, runId)
+	if err != nil {
+		return err
+	}
+	return repo.Upload(ctx, localPath, path.Join(artifactPath, filepath.Base(localPath)))
+}
+
+func (p *Client) LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error {
+	repo, err := p.RunArtifactRepository(ctx, runId)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(localDir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		return repo.Upload(ctx, localPath, path.Join(artifactPath, filepath.ToSlash(rel)))
+	})
+}
+
+func (p *Client) uploadArtifact(ctx context.Context, artifactUri string, localPath string, artifactPath string) error {
+	url, err := p.artifactProxyUrl(artifactUri, artifactPath)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return p.putStream(ctx, url, file, info.Size())
+}
+
+type UploadOptions struct {
+	// PartSize is the number of bytes sent per part. Defaults to 10 MiB.
+	PartSize int64
+	// MaxAttempts bounds how many times a single part is sent before the
+	// upload is aborted. Defaults to 3.
+	MaxAttempts int
+}
+
+type multipartCredential struct {
+	PartNumber int               `json:"part_number"`
+	Url        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+type multipartPart struct {
+	PartNumber int    `json:"part_number"`
+	Etag       string `json:"etag"`
+	Url        string `json:"url,omitempty"`
+}
+
+type ResponseCreateMultipartUpload struct {
+	UploadId    string                `json:"upload_id"`
+	Credentials []multipartCredential `json:"credentials"`
+}
+
+const defaultPartSize = 10 * 1024 * 1024
+
+// LogArtifactMultipart uploads a large file in parts through the artifact
+// proxy's multipart API, reading each part straight from disk. A part that
+// fails is retried on its own instead of restarting the whole file. When the
+// server's artifact store has no multipart support the file is sent as a
+// single streamed PUT.
+func (p *Client) LogArtifactMultipart(ctx context.Context, runId string, localPath string, artifactPath string, opts UploadOptions) error {
+	if opts.PartSize <= 0 {
+		opts.PartSize = defaultPartSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	run, err := p.getRun(ctx, runId, false)
+	if err != nil {
+		return err
+	}
+	artifactPath = path.Join(artifactPath, filepath.Base(localPath))
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	numParts := int((info.Size() + opts.PartSize - 1) / opts.PartSize)
+	if numParts < 1 {
+		numParts = 1
+	}
+
+	mpuUrl := func(action string) (string, error) {
+		u, err := p.artifactProxyUrl(run.Info.ArtifactUri, artifactPath)
+		if err != nil {
+			return "", err
+		}
+		return strings.Replace(u, "/mlflow-artifacts/artifacts/", "/mlflow-artifacts/mpu/"+action+"/", 1), nil
+	}
+	relPath, err := artifactProxyPath(run.Info.ArtifactUri, artifactPath)
+	if err != nil {
+		return err
+	}
+	createUrl, err := mpuUrl("create")
+	if err != nil {
+		return err
+	}
+	body, err := p.HandlePost(ctx, createUrl, map[string]interface{}{"path": relPath, "num_parts": numParts})
+	if IsNotImplemented(err) || IsNotFound(err) {
+		// The server or its artifact store doesn't support multipart uploads.
+		return p.uploadArtifact(ctx, run.Info.ArtifactUri, localPath, artifactPath)
+	}
+	if err != nil {
+		return err
+	}
+	var upload ResponseCreateMultipartUpload
+	if err := json.Unmarshal(body, &upload); err != nil {
+		return err
+	}
+
+	parts := make([]multipartPart, 0, len(upload.Credentials))
+	for _, credential := range upload.Credentials {
+		offset := int64(credential.PartNumber-1) * opts.PartSize
+		size := opts.PartSize
+		if offset+size > info.Size() {
+			size = info.Size() - offset
+		}
+		var etag string
+		for attempt := 0; attempt < opts.MaxAttempts; attempt++ {
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			etag, err = p.putPart(ctx, credential, io.NewSectionReader(file, offset, size), size)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			abortUrl, _ := mpuUrl("abort")
+			p.HandlePost(ctx, abortUrl, map[string]interface{}{"path": relPath, "upload_id": upload.UploadId})
+			return err
+		}
+		parts = append(parts, multipartPart{PartNumber: credential.PartNumber, Etag: etag, Url: credential.Url})
+	}
+	completeUrl, err := mpuUrl("complete")
+	if err != nil {
+		return err
+	}
+	_, err = p.HandlePost(ctx, completeUrl, map[string]interface{}{"path": relPath, "upload_id": upload.UploadId, "parts": parts})
+	return err
+}
+
+// putPart sends one part to the presigned url handed out by the server.
+func (p *Client) putPart(ctx context.Context, credential multipartCredential, body io.Reader, size int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", credential.Url, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	for key, value := range credential.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("mlflow: uploading part %d returned status %d", credential.PartNumber, resp.StatusCode)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// logArtifactBytes uploads data as the artifact file artifactFile of a run.
+func (p *Client) logArtifactBytes(ctx context.Context, runId string, data []byte, artifactFile string) error {
+	repo, err := p.RunArtifactRepository(ctx, runId)
+	if err != nil {
+		return err
+	}
+	return uploadBytes(ctx, repo, data, artifactFile)
+}
+
+// LogDict serializes obj as YAML when artifactFile ends in .yaml or .yml and
+// as indented JSON otherwise. Struct fields are named by their json tags in
+// both formats.
+func (p *Client) LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path.Ext(artifactFile)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+	}
+	return p.logArtifactBytes(ctx, runId, data, artifactFile)
+}
+
+func (p *Client) LogText(ctx context.Context, runId string, text string, artifactFile string) error {
+	return p.logArtifactBytes(ctx, runId, []byte(text), artifactFile)
+}
+
+// LogImage encodes img as JPEG when artifactFile ends in .jpg or .jpeg, as
+// PNG when it ends in .png and as GIF when it ends in .gif.
+func (p *Client) LogImage(ctx context.Context, runId string, img image.Image, artifactFile string) error {
+	var buf bytes.Buffer
+	switch ext := strings.ToLower(path.Ext(artifactFile)); ext {
+	case ".png":
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+	case ".jpg", ".jpeg":
+		if err := jpeg.Encode(&buf, img, nil); err != nil {
+			return err
+		}
+	case ".gif":
+		if err := gif.Encode(&buf, img, nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("mlflow: unsupported image extension %q", ext)
+	}
+	return p.logArtifactBytes(ctx, runId, buf.Bytes(), artifactFile)
+}
+
+type loggedArtifact struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type table struct {
+	Columns []string        `json:"columns"`
+	Data    [][]interface{} `json:"data"`
+}
+
+// LogTable writes rows in the split-orient table.json format rendered by the
+// MLflow UI. Logging to a table that already exists appends the rows, adding
+// any new columns, and the file is recorded in the mlflow.loggedArtifacts
+// run tag. The run is read past WithCache, so that the tag is up to date.
+func (p *Client) LogTable(ctx context.Context, runId string, columns []string, rows [][]interface{}, artifactFile string) error {
+	if path.Ext(artifactFile) != ".json" {
+		return fmt.Errorf("mlflow: table artifact %s must be a .json file", artifactFile)
+	}
+	run, err := p.getRun(ctx, runId, false)
+	if err != nil {
+		return err
+	}
+	var logged []loggedArtifact
+	for _, tag := range run.Data.Tags {
+		if tag.Key == TagLoggedArtifacts {
+			if err := json.Unmarshal([]byte(tag.Value), &logged); err != nil {
+				return err
+			}
+		}
+	}
+	existing := false
+	for _, artifact := range logged {
+		if artifact.Path == artifactFile && artifact.Type == "table" {
+			existing = true
+		}
+	}
+
+	result := table{Columns: columns, Data: rows}
+	if existing {
+		previous, err := p.downloadTable(ctx, run.Info.ArtifactUri, artifactFile)
+		if err != nil {
+			return err
+		}
+		result = mergeTables(previous, result)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := p.logArtifactBytes(ctx, runId, data, artifactFile); err != nil {
+		return err
+	}
+	if existing {
+		return nil
+	}
+	value, err := json.Marshal(append(logged, loggedArtifact{Path: artifactFile, Type: "table"}))
+	if err != nil {
+		return err
+	}
+	return p.SetTag(ctx, runId, TagLoggedArtifacts, string(value))
+}
+
+func (p *Client) downloadTable(ctx context.Context, artifactUri string, artifactFile string) (table, error) {
+	var result table
+	repo, err := p.ArtifactRepository(artifactUri)
+	if err != nil {
+		return result, err
+	}
+	dir, err := os.MkdirTemp("", "mlflow-table-")
+	if err != nil {
+		return result, err
+	}
+	defer os.RemoveAll(dir)
+	localPath := filepath.Join(dir, path.Base(artifactFile))
+	if err := repo.Download(ctx, artifactFile, localPath); err != nil {
+		return result, err
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// mergeTables appends the rows of b to a over the union of both column sets,
+// leaving cells of missing columns null.
+func mergeTables(a table, b table) table {
+	merged := table{Columns: append([]string{}, a.Columns...)}
+	index := map[string]int{}
+	for i, column := range merged.Columns {
+		index[column] = i
+	}
+	for _, column := range b.Columns {
+		if _, ok := index[column]; !ok {
+			index[column] = len(merged.Columns)
+			merged.Columns = append(merged.Columns, column)
+		}
+	}
+	for _, t := range []table{a, b} {
+		for _, row := range t.Data {
+			mergedRow := make([]interface{}, len(merged.Columns))
+			for i, value := range row {
+				if i < len(t.Columns) {
+					mergedRow[index[t.Columns[i]]] = value
+				}
+			}
+			merged.Data = append(merged.Data, mergedRow)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,399 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadArtifacts(t *testing.T) {
+	tree := map[string][]FileInfo{
+		"model":         {{Path: "model/MLmodel", FileSize: 5}, {Path: "model/data", IsDir: true}},
+		"model/data":    {{Path: "model/data/weights.bin", FileSize: 7}},
+		"model/MLmodel": nil,
+	}
+	contents := map[string]string{
+		"model/MLmodel":          "flavo",
+		"model/data/weights.bin": "weights",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("run_id") != "run1" {
+			t.Errorf("unexpected run id %s", r.URL.Query().Get("run_id"))
+		}
+		path := r.URL.Query().Get("path")
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/artifacts/list":
+			json.NewEncoder(w).Encode(ResponseListArtifacts{Files: tree[path]})
+		case "/get-artifact":
+			content, ok := contents[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	t.Run("Directory", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := client.DownloadArtifacts(context.Background(), "run1", "model", dir); err != nil {
+			t.Fatal(err)
+		}
+		for path, content := range contents {
+			b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != content {
+				t.Errorf("Expected %s to contain %q, got %q", path, content, b)
+			}
+		}
+	})
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := client.DownloadArtifacts(context.Background(), "run1", "model/MLmodel", dir); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "model", "MLmodel")); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// TestDownloadArtifactsTraversal checks that a listing naming files outside
+// of the download directory is rejected before anything is written, serially
+// or with workers.
+func TestDownloadArtifactsTraversal(t *testing.T) {
+	for _, test := range []struct {
+		malicious   string
+		concurrency int
+	}{{"../escaped", 1}, {"model/../../escaped", 1}, {"/tmp/escaped", 1}, {"../escaped", 4}} {
+		malicious := test.malicious
+		t.Run(fmt.Sprintf("%s/%d", malicious, test.concurrency), func(t *testing.T) {
+			fetched := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/2.0/mlflow/artifacts/list":
+					json.NewEncoder(w).Encode(ResponseListArtifacts{Files: []FileInfo{{Path: "model/ok"}, {Path: malicious}}})
+				case "/get-artifact":
+					fetched = true
+					w.Write([]byte("pwned"))
+				}
+			}))
+			defer server.Close()
+			parent := t.TempDir()
+			dir := filepath.Join(parent, "download")
+			err := New(server.URL).DownloadArtifacts(context.Background(), "run1", "", dir, WithConcurrency(test.concurrency))
+			if err == nil || !strings.Contains(err.Error(), "artifact path") {
+				t.Errorf("expected the listing to be rejected, got %v", err)
+			}
+			if fetched {
+				t.Error("expected no artifact to be fetched")
+			}
+			if _, err := os.Stat(filepath.Join(parent, "escaped")); !os.IsNotExist(err) {
+				t.Errorf("expected nothing outside of the download directory, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLogArtifacts(t *testing.T) {
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/2.0/mlflow/runs/get":
+			json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: "mlflow-artifacts:/1/run1/artifacts"}}})
+		case r.Method == "PUT":
+			b, _ := io.ReadAll(r.Body)
+			uploaded[r.URL.Path] = string(b)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "data"), 0755)
+	os.WriteFile(filepath.Join(dir, "MLmodel"), []byte("flavors"), 0644)
+	os.WriteFile(filepath.Join(dir, "data", "weights.bin"), []byte("weights"), 0644)
+	if err := client.LogArtifacts(context.Background(), "run1", dir, "model"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogArtifact(context.Background(), "run1", filepath.Join(dir, "MLmodel"), ""); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/model/MLmodel":          "flavors",
+		"/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/model/data/weights.bin": "weights",
+		"/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/MLmodel":                "flavors",
+	}
+	for path, content := range expected {
+		if uploaded[path] != content {
+			t.Errorf("Expected %s to be uploaded with %q, got %q", path, content, uploaded[path])
+		}
+	}
+}
+
+func TestLogArtifactMultipart(t *testing.T) {
+	var server *httptest.Server
+	parts := map[string]string{}
+	failures := 1
+	var completed []multipartPart
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/get":
+			json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: "mlflow-artifacts:/1/run1/artifacts"}}})
+		case "/api/2.0/mlflow-artifacts/mpu/create/1/run1/artifacts/model/weights.bin":
+			var request struct {
+				Path     string `json:"path"`
+				NumParts int    `json:"num_parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Path != "1/run1/artifacts/model/weights.bin" || request.NumParts != 3 {
+				t.Errorf("unexpected create request %+v", request)
+			}
+			var response ResponseCreateMultipartUpload
+			response.UploadId = "upload1"
+			for i := 1; i <= request.NumParts; i++ {
+				response.Credentials = append(response.Credentials, multipartCredential{PartNumber: i, Url: server.URL + "/part/" + string(rune('0'+i))})
+			}
+			json.NewEncoder(w).Encode(response)
+		case "/part/1", "/part/2", "/part/3":
+			if r.URL.Path == "/part/2" && failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			parts[r.URL.Path] = string(b)
+			w.Header().Set("ETag", "etag"+r.URL.Path[len(r.URL.Path)-1:])
+		case "/api/2.0/mlflow-artifacts/mpu/complete/1/run1/artifacts/model/weights.bin":
+			var request struct {
+				Parts []multipartPart `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			completed = request.Parts
+			w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "weights.bin")
+	os.WriteFile(localPath, []byte("aaaabbbbcc"), 0644)
+	if err := client.LogArtifactMultipart(context.Background(), "run1", localPath, "model", UploadOptions{PartSize: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if parts["/part/1"] != "aaaa" || parts["/part/2"] != "bbbb" || parts["/part/3"] != "cc" {
+		t.Errorf("unexpected parts %v", parts)
+	}
+	if len(completed) != 3 || completed[1].Etag != "etag2" {
+		t.Errorf("unexpected completed parts %+v", completed)
+	}
+}
+
+func TestLogDict(t *testing.T) {
+	root := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: root}}})
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	config := struct {
+		LearningRate float64  `json:"learning_rate"`
+		Layers       []int    `json:"layers"`
+		Optimizer    string   `json:"optimizer"`
+		Tags         []string `json:"tags,omitempty"`
+	}{LearningRate: 0.01, Layers: []int{64, 32}, Optimizer: "adam"}
+
+	if err := client.LogDict(context.Background(), "run1", config, "config/train.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(filepath.Join(root, "config", "train.yaml"))
+	expected := "layers:\n    - 64\n    - 32\nlearning_rate: 0.01\noptimizer: adam\n"
+	if string(b) != expected {
+		t.Errorf("Expected YAML %q, got %q", expected, b)
+	}
+	if err := client.LogDict(context.Background(), "run1", config, "config.json"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(filepath.Join(root, "config.json"))
+	if !strings.Contains(string(b), `"learning_rate": 0.01`) {
+		t.Errorf("unexpected JSON %s", b)
+	}
+}
+
+func TestLogText(t *testing.T) {
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/2.0/mlflow/runs/get":
+			json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: "mlflow-artifacts:/1/run1/artifacts"}}})
+		case r.Method == "PUT":
+			b, _ := io.ReadAll(r.Body)
+			uploaded[r.URL.Path] = string(b)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	if err := client.LogText(context.Background(), "run1", "epoch 1: loss 0.5\n", "logs/train.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogText(context.Background(), "run1", "", "empty.txt"); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/logs/train.txt": "epoch 1: loss 0.5\n",
+		"/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/empty.txt":      "",
+	}
+	if len(uploaded) != len(expected) {
+		t.Errorf("unexpected uploads %v", uploaded)
+	}
+	for path, content := range expected {
+		if got, ok := uploaded[path]; !ok || got != content {
+			t.Errorf("Expected %s to be uploaded with %q, got %q", path, content, got)
+		}
+	}
+	if err := client.LogText(context.Background(), "run1", "text", "../escape.txt"); err == nil {
+		t.Error("Expected artifact paths escaping the run to be rejected")
+	}
+}
+
+func TestLogTable(t *testing.T) {
+	root := t.TempDir()
+	tags := []RunTag{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/get":
+			json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: root}, Data: RunData{Tags: tags}}})
+		case "/api/2.0/mlflow/runs/set-tag":
+			var tag RunTag
+			json.NewDecoder(r.Body).Decode(&tag)
+			tags = append(tags, tag)
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+	// The cache must not hide the tag set by the first call from the second.
+	client := New(server.URL, WithCache(time.Minute))
+
+	if err := client.LogTable(context.Background(), "run1", []string{"question", "answer"}, [][]interface{}{{"q1", "a1"}}, "eval.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogTable(context.Background(), "run1", []string{"question", "score"}, [][]interface{}{{"q2", 0.5}}, "eval.json"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(filepath.Join(root, "eval.json"))
+	expected := `{"columns":["question","answer","score"],"data":[["q1","a1",null],["q2",null,0.5]]}`
+	if string(b) != expected {
+		t.Errorf("Expected table %s, got %s", expected, b)
+	}
+	if len(tags) != 1 || tags[0].Key != "mlflow.loggedArtifacts" || tags[0].Value != `[{"path":"eval.json","type":"table"}]` {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+}
+
+func TestDownloadArtifactsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Query().Get("path")
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/artifacts/list":
+			var files []FileInfo
+			for i := 0; i < 20; i++ {
+				files = append(files, FileInfo{Path: fmt.Sprintf("shards/%02d.bin", i)})
+			}
+			files = append(files, FileInfo{Path: "shards/missing.bin"})
+			json.NewEncoder(w).Encode(ResponseListArtifacts{Files: files})
+		case "/get-artifact":
+			mu.Lock()
+			attempts[path]++
+			n := attempts[path]
+			mu.Unlock()
+			if path == "shards/missing.bin" || (path == "shards/03.bin" && n == 1) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(path))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	dir := t.TempDir()
+	err := client.DownloadArtifacts(context.Background(), "run1", "shards", dir, WithConcurrency(8), WithFileAttempts(2))
+	errs, ok := err.(MultiError)
+	if !ok || len(errs) != 1 || !strings.Contains(errs[0].Error(), "shards/missing.bin") {
+		t.Fatalf("Expected a single error for the missing file, got %v", err)
+	}
+	if attempts["shards/missing.bin"] != 2 || attempts["shards/03.bin"] != 2 {
+		t.Errorf("unexpected attempts %v", attempts)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := os.Stat(filepath.Join(dir, "shards", fmt.Sprintf("%02d.bin", i))); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestLogImage(t *testing.T) {
+	root := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ResponseRun{Run: Run{Info: RunInfo{RunId: "run1", ArtifactUri: root}}})
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	for file, format := range map[string]string{"plot.png": "png", "plots/plot.JPG": "jpeg", "plot.jpeg": "jpeg", "plot.gif": "gif"} {
+		if err := client.LogImage(context.Background(), "run1", img, file); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, decoded, err := image.DecodeConfig(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if decoded != format || config.Width != 4 || config.Height != 3 {
+			t.Errorf("%s: expected a 4x3 %s image, got a %dx%d %s image", file, format, config.Width, config.Height, decoded)
+		}
+	}
+	err := client.LogImage(context.Background(), "run1", img, "plot.bmp")
+	if err == nil || !strings.Contains(err.Error(), `unsupported image extension ".bmp"`) {
+		t.Errorf("expected an unsupported extension error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "plot.bmp")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be uploaded, got %v", err)
+	}
+}
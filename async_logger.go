@@ -0,0 +1,220 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// AsyncLoggerOptions configures an AsyncLogger. Zero values select the
+// defaults.
+type AsyncLoggerOptions struct {
+	// FlushInterval is how often buffered entries are sent. Defaults to 5s.
+	FlushInterval time.Duration
+	// BatchSize is the number of buffered entries that triggers a flush
+	// before the interval elapses. Defaults to 1000.
+	BatchSize int
+}
+
+// AsyncLogger buffers metrics, params and tags of a run in memory and sends
+// them with LogBatch from a background goroutine, so logging calls never
+// wait for the tracking server. Errors of background flushes are returned by
+// the next Flush or Close, and entries that failed with a retryable error
+// are sent again by the next flush.
+type AsyncLogger struct {
+	client *Client
+	runId  string
+	ctx    context.Context
+	opts   AsyncLoggerOptions
+
+	mu      sync.Mutex
+	metrics []Metric
+	params  []Param
+	tags    []RunTag
+	err     error
+	closed  bool
+
+	// flushMu keeps batches in the order they were logged.
+	flushMu sync.Mutex
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+var ErrAsyncLoggerClosed = errors.New("mlflow: async logger is closed")
+
+// NewAsyncLogger starts an AsyncLogger for runId. Background flushes use ctx.
+func (p *Client) NewAsyncLogger(ctx context.Context, runId string, opts AsyncLoggerOptions) *AsyncLogger {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = maxBatchEntries
+	}
+	l := &AsyncLogger{
+		client:  p,
+		runId:   runId,
+		ctx:     ctx,
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+func (l *AsyncLogger) run() {
+	defer close(l.stopped)
+	ticker := time.NewTicker(l.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.wake:
+		case <-l.done:
+			return
+		}
+		l.flush(l.ctx)
+	}
+}
+
+// add buffers an entry with fn and wakes the flusher once a batch is full.
+func (l *AsyncLogger) add(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		if l.err == nil {
+			l.err = ErrAsyncLoggerClosed
+		}
+		return
+	}
+	fn()
+	if len(l.metrics)+len(l.params)+len(l.tags) >= l.opts.BatchSize {
+		select {
+		case l.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// LogMetric buffers a metric value at step, timestamped now.
+func (l *AsyncLogger) LogMetric(key string, value float64, step int64) {
+	timestamp := Millis(time.Now())
+	l.add(func() {
+		l.metrics = append(l.metrics, Metric{Key: key, Value: value, Timestamp: timestamp, Step: step})
+	})
+}
+
+func (l *AsyncLogger) LogParam(key string, value string) {
+	l.add(func() {
+		l.params = append(l.params, Param{Key: key, Value: value})
+	})
+}
+
+func (l *AsyncLogger) SetTag(key string, value string) {
+	l.add(func() {
+		l.tags = append(l.tags, RunTag{Key: key, Value: value})
+	})
+}
+
+// Pending returns the number of buffered entries not yet sent.
+func (l *AsyncLogger) Pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.metrics) + len(l.params) + len(l.tags)
+}
+
+// flush sends everything buffered so far and returns the first error seen
+// since the last flush that reported one. Entries that failed with a
+// retryable error stay buffered for the next flush; others are dropped.
+func (l *AsyncLogger) flush(ctx context.Context) error {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+	l.mu.Lock()
+	metrics, params, tags := l.metrics, dedupParams(l.params), dedupTags(l.tags)
+	l.metrics, l.params, l.tags = nil, nil, nil
+	l.mu.Unlock()
+
+	if len(metrics) > 0 || len(params) > 0 || len(tags) > 0 {
+		var sentMetrics, sentParams, sentTags int
+		err := splitBatch(metrics, params, tags, func(metrics []Metric, params []Param, tags []RunTag) error {
+			if err := l.client.LogBatch(ctx, l.runId, metrics, params, tags); err != nil {
+				return err
+			}
+			sentMetrics, sentParams, sentTags = sentMetrics+len(metrics), sentParams+len(params), sentTags+len(tags)
+			return nil
+		})
+		if err != nil {
+			l.mu.Lock()
+			if l.err == nil {
+				l.err = err
+			}
+			if IsRetryable(err) {
+				// Put the unsent entries back in front of those logged
+				// since, so that the next flush sends them in order.
+				l.metrics = append(append([]Metric{}, metrics[sentMetrics:]...), l.metrics...)
+				l.params = append(append([]Param{}, params[sentParams:]...), l.params...)
+				l.tags = append(append([]RunTag{}, tags[sentTags:]...), l.tags...)
+			}
+			l.mu.Unlock()
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err := l.err
+	l.err = nil
+	return err
+}
+
+// Flush sends everything buffered so far and waits for it to be logged.
+func (l *AsyncLogger) Flush(ctx context.Context) error {
+	return l.flush(ctx)
+}
+
+// Close stops the background goroutine and flushes the remaining entries.
+// Entries logged after Close are dropped, while those that failed to send
+// stay buffered and can still be sent with Flush.
+func (l *AsyncLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrAsyncLoggerClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+	close(l.done)
+	<-l.stopped
+	return l.flush(ctx)
+}
+
+// dedupParams keeps the last value of each param, since a batch must not
+// contain a key twice.
+func dedupParams(params []Param) []Param {
+	index := map[string]int{}
+	var result []Param
+	for _, param := range params {
+		if i, ok := index[param.Key]; ok {
+			result[i] = param
+			continue
+		}
+		index[param.Key] = len(result)
+		result = append(result, param)
+	}
+	return result
+}
+
+func dedupTags(tags []RunTag) []RunTag {
+	index := map[string]int{}
+	var result []RunTag
+	for _, tag := range tags {
+		if i, ok := index[tag.Key]; ok {
+			result[i] = tag
+			continue
+		}
+		index[tag.Key] = len(result)
+		result = append(result, tag)
+	}
+	return result
+}
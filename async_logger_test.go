@@ -0,0 +1,148 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncLogger(t *testing.T) {
+	var mu sync.Mutex
+	var metrics []Metric
+	var params []Param
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/mlflow/runs/log-batch" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var batch struct {
+			RunId   string   `json:"run_id"`
+			Metrics []Metric `json:"metrics"`
+			Params  []Param  `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+		mu.Lock()
+		requests++
+		metrics = append(metrics, batch.Metrics...)
+		params = append(params, batch.Params...)
+		mu.Unlock()
+		if len(batch.Metrics) > maxBatchMetrics {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	logger := client.NewAsyncLogger(ctx, "run1", AsyncLoggerOptions{FlushInterval: time.Hour})
+	logger.LogParam("lr", "0.1")
+	logger.LogParam("lr", "0.01")
+	for step := int64(0); step < 2500; step++ {
+		logger.LogMetric("loss", float64(step), step)
+	}
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(metrics) != 2500 || len(params) != 1 || params[0].Value != "0.01" {
+		t.Errorf("got %d metrics and params %v", len(metrics), params)
+	}
+	for i, metric := range metrics {
+		if metric.Step != int64(i) {
+			t.Fatalf("metric %d logged out of order at step %d", i, metric.Step)
+		}
+	}
+	mu.Unlock()
+
+	logger.LogMetric("accuracy", 0.9, 0)
+	if err := logger.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(metrics) != 2501 || metrics[2500].Key != "accuracy" {
+		t.Errorf("close did not flush the last metric")
+	}
+	mu.Unlock()
+	logger.LogMetric("accuracy", 0.95, 1)
+	if err := logger.Flush(ctx); err != ErrAsyncLoggerClosed {
+		t.Errorf("expected ErrAsyncLoggerClosed, got %v", err)
+	}
+}
+
+func TestAsyncLoggerInterval(t *testing.T) {
+	flushed := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+		select {
+		case flushed <- struct{}{}:
+		default:
+		}
+	}))
+	defer server.Close()
+	logger := New(server.URL).NewAsyncLogger(context.Background(), "run1", AsyncLoggerOptions{FlushInterval: 10 * time.Millisecond})
+	defer logger.Close(context.Background())
+	logger.SetTag("phase", "train")
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Error("buffered tag was not flushed")
+	}
+}
+
+func TestAsyncLoggerRequeue(t *testing.T) {
+	var mu sync.Mutex
+	var metrics []Metric
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			Metrics []Metric `json:"metrics"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+		mu.Lock()
+		defer mu.Unlock()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		metrics = append(metrics, batch.Metrics...)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	logger := New(server.URL).NewAsyncLogger(ctx, "run1", AsyncLoggerOptions{FlushInterval: time.Hour})
+	defer logger.Close(ctx)
+
+	logger.LogMetric("loss", 1, 0)
+	if err := logger.Flush(ctx); err == nil {
+		t.Fatal("expected the failed flush to be reported")
+	}
+	if n := logger.Pending(); n != 1 {
+		t.Errorf("expected the failed metric to stay buffered, got %d entries", n)
+	}
+	logger.LogMetric("loss", 2, 1)
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(metrics) != 2 || metrics[0].Step != 0 || metrics[1].Step != 1 {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+	status = http.StatusBadRequest
+	mu.Unlock()
+	logger.LogMetric("loss", 3, 2)
+	if err := logger.Flush(ctx); err == nil {
+		t.Fatal("expected the failed flush to be reported")
+	}
+	if n := logger.Pending(); n != 0 {
+		t.Errorf("expected the rejected metric to be dropped, got %d entries", n)
+	}
+}
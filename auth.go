@@ -0,0 +1,174 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// The methods of this file call the endpoints of MLflow's basic-auth app
+// (`mlflow server --app-name basic-auth`). They manage the server rather
+// than tracking data, so they are not part of API.
+
+type Permission string
+
+const (
+	PermissionRead   Permission = "READ"
+	PermissionEdit   Permission = "EDIT"
+	PermissionManage Permission = "MANAGE"
+	PermissionNone   Permission = "NO_PERMISSIONS"
+)
+
+type User struct {
+	Id                         int64                       `json:"id"`
+	Username                   string                      `json:"username"`
+	IsAdmin                    bool                        `json:"is_admin"`
+	ExperimentPermissions      []ExperimentPermission      `json:"experiment_permissions,omitempty"`
+	RegisteredModelPermissions []RegisteredModelPermission `json:"registered_model_permissions,omitempty"`
+}
+
+type ResponseUser struct {
+	User User `json:"user"`
+}
+
+type ExperimentPermission struct {
+	ExperimentId string     `json:"experiment_id"`
+	UserId       int64      `json:"user_id"`
+	Permission   Permission `json:"permission"`
+}
+
+type ResponseExperimentPermission struct {
+	ExperimentPermission ExperimentPermission `json:"experiment_permission"`
+}
+
+type RegisteredModelPermission struct {
+	Name       string     `json:"name"`
+	UserId     int64      `json:"user_id"`
+	Permission Permission `json:"permission"`
+}
+
+type ResponseRegisteredModelPermission struct {
+	RegisteredModelPermission RegisteredModelPermission `json:"registered_model_permission"`
+}
+
+func decodeUser(body []byte, err error) (*User, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseUser
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.User, nil
+}
+
+func decodeExperimentPermission(body []byte, err error) (*ExperimentPermission, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseExperimentPermission
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.ExperimentPermission, nil
+}
+
+// CreateExperimentPermission grants username permission on an experiment.
+// It fails with IsAlreadyExists when the user already has a permission
+// there; use UpdateExperimentPermission to change it.
+func (p *Client) CreateExperimentPermission(ctx context.Context, experimentId string, username string, permission Permission) (*ExperimentPermission, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/permissions/create"
+	request := map[string]interface{}{"experiment_id": experimentId, "username": username, "permission": permission}
+	return decodeExperimentPermission(p.HandlePost(ctx, url, request))
+}
+
+func (p *Client) GetExperimentPermission(ctx context.Context, experimentId string, username string) (*ExperimentPermission, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/permissions/get"
+	params := map[string]interface{}{"experiment_id": experimentId, "username": username}
+	return decodeExperimentPermission(p.HandleGet(ctx, url, params))
+}
+
+func (p *Client) UpdateExperimentPermission(ctx context.Context, experimentId string, username string, permission Permission) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/permissions/update"
+	request := map[string]interface{}{"experiment_id": experimentId, "username": username, "permission": permission}
+	_, err := p.HandlePatch(ctx, url, request)
+	return err
+}
+
+// DeleteExperimentPermission revokes the permission of username on an
+// experiment, leaving it with the server's default permission.
+func (p *Client) DeleteExperimentPermission(ctx context.Context, experimentId string, username string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/permissions/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"experiment_id": experimentId, "username": username})
+	return err
+}
+
+func decodeRegisteredModelPermission(body []byte, err error) (*RegisteredModelPermission, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseRegisteredModelPermission
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.RegisteredModelPermission, nil
+}
+
+// CreateRegisteredModelPermission grants username permission on a
+// registered model and its versions. It fails with IsAlreadyExists when the
+// user already has a permission there.
+func (p *Client) CreateRegisteredModelPermission(ctx context.Context, name string, username string, permission Permission) (*RegisteredModelPermission, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/permissions/create"
+	request := map[string]interface{}{"name": name, "username": username, "permission": permission}
+	return decodeRegisteredModelPermission(p.HandlePost(ctx, url, request))
+}
+
+func (p *Client) GetRegisteredModelPermission(ctx context.Context, name string, username string) (*RegisteredModelPermission, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/permissions/get"
+	params := map[string]interface{}{"name": name, "username": username}
+	return decodeRegisteredModelPermission(p.HandleGet(ctx, url, params))
+}
+
+func (p *Client) UpdateRegisteredModelPermission(ctx context.Context, name string, username string, permission Permission) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/permissions/update"
+	request := map[string]interface{}{"name": name, "username": username, "permission": permission}
+	_, err := p.HandlePatch(ctx, url, request)
+	return err
+}
+
+func (p *Client) DeleteRegisteredModelPermission(ctx context.Context, name string, username string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/permissions/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name, "username": username})
+	return err
+}
+
+func (p *Client) CreateUser(ctx context.Context, username string, password string) (*User, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/users/create"
+	return decodeUser(p.HandlePost(ctx, url, map[string]interface{}{"username": username, "password": password}))
+}
+
+// GetUser returns a user with the permissions granted to it.
+func (p *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/users/get"
+	return decodeUser(p.HandleGet(ctx, url, map[string]interface{}{"username": username}))
+}
+
+func (p *Client) UpdateUserPassword(ctx context.Context, username string, password string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/users/update-password"
+	_, err := p.HandlePatch(ctx, url, map[string]interface{}{"username": username, "password": password})
+	return err
+}
+
+func (p *Client) UpdateUserAdmin(ctx context.Context, username string, isAdmin bool) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/users/update-admin"
+	_, err := p.HandlePatch(ctx, url, map[string]interface{}{"username": username, "is_admin": isAdmin})
+	return err
+}
+
+func (p *Client) DeleteUser(ctx context.Context, username string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/users/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"username": username})
+	return err
+}
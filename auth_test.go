@@ -0,0 +1,236 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// authServer fakes the endpoints of MLflow's basic-auth app.
+type authServer struct {
+	users       map[string]*User
+	passwords   map[string]string
+	experiments map[[2]string]Permission
+	models      map[[2]string]Permission
+}
+
+func newAuthServer() (*authServer, *httptest.Server) {
+	s := &authServer{users: map[string]*User{}, passwords: map[string]string{}, experiments: map[[2]string]Permission{}, models: map[[2]string]Permission{}}
+	return s, httptest.NewServer(s)
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := map[string]interface{}{}
+	for key := range r.URL.Query() {
+		request[key] = r.URL.Query().Get(key)
+	}
+	if r.Method != "GET" {
+		json.NewDecoder(r.Body).Decode(&request)
+	}
+	param := func(key string) string {
+		value, _ := request[key].(string)
+		return value
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fail := func(status int, code string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error_code": code, "message": code})
+	}
+	var response interface{} = map[string]interface{}{}
+	user := s.users[param("username")]
+	switch r.Method + " " + r.URL.Path {
+	case "POST /api/2.0/mlflow/users/create":
+		if user != nil {
+			fail(http.StatusBadRequest, ErrorCodeResourceAlreadyExists)
+			return
+		}
+		user = &User{Id: int64(len(s.users) + 1), Username: param("username")}
+		s.users[user.Username], s.passwords[user.Username] = user, param("password")
+		response = ResponseUser{*user}
+	case "GET /api/2.0/mlflow/users/get":
+		if user == nil {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		withPermissions := *user
+		for key, permission := range s.experiments {
+			if key[1] == user.Username {
+				withPermissions.ExperimentPermissions = append(withPermissions.ExperimentPermissions, ExperimentPermission{ExperimentId: key[0], UserId: user.Id, Permission: permission})
+			}
+		}
+		for key, permission := range s.models {
+			if key[1] == user.Username {
+				withPermissions.RegisteredModelPermissions = append(withPermissions.RegisteredModelPermissions, RegisteredModelPermission{Name: key[0], UserId: user.Id, Permission: permission})
+			}
+		}
+		response = ResponseUser{withPermissions}
+	case "PATCH /api/2.0/mlflow/users/update-password":
+		s.passwords[param("username")] = param("password")
+	case "PATCH /api/2.0/mlflow/users/update-admin":
+		if user == nil {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		user.IsAdmin, _ = request["is_admin"].(bool)
+	case "DELETE /api/2.0/mlflow/users/delete":
+		delete(s.users, param("username"))
+	case "POST /api/2.0/mlflow/experiments/permissions/create":
+		key := [2]string{param("experiment_id"), param("username")}
+		if _, ok := s.experiments[key]; ok {
+			fail(http.StatusBadRequest, ErrorCodeResourceAlreadyExists)
+			return
+		}
+		s.experiments[key] = Permission(param("permission"))
+		response = ResponseExperimentPermission{ExperimentPermission{ExperimentId: key[0], UserId: 1, Permission: s.experiments[key]}}
+	case "GET /api/2.0/mlflow/experiments/permissions/get":
+		key := [2]string{param("experiment_id"), param("username")}
+		permission, ok := s.experiments[key]
+		if !ok {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		response = ResponseExperimentPermission{ExperimentPermission{ExperimentId: key[0], UserId: 1, Permission: permission}}
+	case "PATCH /api/2.0/mlflow/experiments/permissions/update":
+		key := [2]string{param("experiment_id"), param("username")}
+		if _, ok := s.experiments[key]; !ok {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		s.experiments[key] = Permission(param("permission"))
+	case "DELETE /api/2.0/mlflow/experiments/permissions/delete":
+		delete(s.experiments, [2]string{param("experiment_id"), param("username")})
+	case "POST /api/2.0/mlflow/registered-models/permissions/create":
+		key := [2]string{param("name"), param("username")}
+		if _, ok := s.models[key]; ok {
+			fail(http.StatusBadRequest, ErrorCodeResourceAlreadyExists)
+			return
+		}
+		s.models[key] = Permission(param("permission"))
+		response = ResponseRegisteredModelPermission{RegisteredModelPermission{Name: key[0], UserId: 1, Permission: s.models[key]}}
+	case "GET /api/2.0/mlflow/registered-models/permissions/get":
+		key := [2]string{param("name"), param("username")}
+		permission, ok := s.models[key]
+		if !ok {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		response = ResponseRegisteredModelPermission{RegisteredModelPermission{Name: key[0], UserId: 1, Permission: permission}}
+	case "PATCH /api/2.0/mlflow/registered-models/permissions/update":
+		key := [2]string{param("name"), param("username")}
+		if _, ok := s.models[key]; !ok {
+			fail(http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+			return
+		}
+		s.models[key] = Permission(param("permission"))
+	case "DELETE /api/2.0/mlflow/registered-models/permissions/delete":
+		delete(s.models, [2]string{param("name"), param("username")})
+	default:
+		fail(http.StatusNotFound, "ENDPOINT_NOT_FOUND")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func TestExperimentPermissions(t *testing.T) {
+	_, server := newAuthServer()
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+	permission, err := client.CreateExperimentPermission(ctx, "1", "alice", PermissionRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permission.ExperimentId != "1" || permission.Permission != PermissionRead {
+		t.Errorf("unexpected permission %+v", permission)
+	}
+	if _, err := client.CreateExperimentPermission(ctx, "1", "alice", PermissionEdit); !IsAlreadyExists(err) {
+		t.Errorf("expected a second permission to fail, got %v", err)
+	}
+	if err := client.UpdateExperimentPermission(ctx, "1", "alice", PermissionManage); err != nil {
+		t.Fatal(err)
+	}
+	permission, err = client.GetExperimentPermission(ctx, "1", "alice")
+	if err != nil || permission.Permission != PermissionManage {
+		t.Errorf("unexpected permission %+v, %v", permission, err)
+	}
+	if err := client.DeleteExperimentPermission(ctx, "1", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetExperimentPermission(ctx, "1", "alice"); !IsNotFound(err) {
+		t.Errorf("expected the permission to be deleted, got %v", err)
+	}
+}
+
+func TestRegisteredModelPermissions(t *testing.T) {
+	_, server := newAuthServer()
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+	permission, err := client.CreateRegisteredModelPermission(ctx, "classifier", "alice", PermissionRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permission.Name != "classifier" || permission.Permission != PermissionRead {
+		t.Errorf("unexpected permission %+v", permission)
+	}
+	if _, err := client.CreateRegisteredModelPermission(ctx, "classifier", "alice", PermissionEdit); !IsAlreadyExists(err) {
+		t.Errorf("expected a second permission to fail, got %v", err)
+	}
+	if err := client.UpdateRegisteredModelPermission(ctx, "classifier", "alice", PermissionNone); err != nil {
+		t.Fatal(err)
+	}
+	permission, err = client.GetRegisteredModelPermission(ctx, "classifier", "alice")
+	if err != nil || permission.Permission != PermissionNone {
+		t.Errorf("unexpected permission %+v, %v", permission, err)
+	}
+	if err := client.DeleteRegisteredModelPermission(ctx, "classifier", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRegisteredModelPermission(ctx, "classifier", "alice"); !IsNotFound(err) {
+		t.Errorf("expected the permission to be deleted, got %v", err)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	auth, server := newAuthServer()
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+	user, err := client.CreateUser(ctx, "team-a", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "team-a" || user.IsAdmin {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if _, err := client.CreateUser(ctx, "team-a", "other"); !IsAlreadyExists(err) {
+		t.Errorf("expected a duplicate user to fail, got %v", err)
+	}
+	if err := client.UpdateUserPassword(ctx, "team-a", "n3w"); err != nil || auth.passwords["team-a"] != "n3w" {
+		t.Errorf("unexpected password %q, %v", auth.passwords["team-a"], err)
+	}
+	if err := client.UpdateUserAdmin(ctx, "team-a", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateExperimentPermission(ctx, "1", "team-a", PermissionEdit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateRegisteredModelPermission(ctx, "classifier", "team-a", PermissionManage); err != nil {
+		t.Fatal(err)
+	}
+	user, err = client.GetUser(ctx, "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.IsAdmin || len(user.ExperimentPermissions) != 1 || user.ExperimentPermissions[0].Permission != PermissionEdit || len(user.RegisteredModelPermissions) != 1 {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if err := client.DeleteUser(ctx, "team-a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetUser(ctx, "team-a"); !IsNotFound(err) {
+		t.Errorf("expected the user to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package mlflow
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// WithCache caches the responses of GetExperiment, GetRun,
+// GetRegisteredModel and GetLatestVersions for ttl. Cached results may be up
+// to ttl old, including after changes made through the same client; call
+// InvalidateCache after a write that must be seen right away.
+func WithCache(ttl time.Duration) Option {
+	return func(p *Client) {
+		p.cache = &responseCache{ttl: ttl, entries: map[string]cacheEntry{}}
+	}
+}
+
+// InvalidateCache drops every cached response.
+func (p *Client) InvalidateCache() {
+	if p.cache == nil {
+		return
+	}
+	p.cache.mu.Lock()
+	defer p.cache.mu.Unlock()
+	p.cache.entries = map[string]cacheEntry{}
+}
+
+const maxCacheEntries = 4096
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// cached returns the cached body for the request to url with params, or
+// calls fetch and caches its result. Errors are never cached.
+func (p *Client) cached(url string, params map[string]interface{}, fetch func() ([]byte, error)) ([]byte, error) {
+	if p.cache == nil {
+		return fetch()
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	key := url + "?" + string(b)
+	c := p.cache
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.body, nil
+	}
+
+	body, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = cacheEntry{body: body, expires: now.Add(c.ttl)}
+	return body, nil
+}
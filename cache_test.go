@@ -0,0 +1,56 @@
+package mlflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path+"?"+r.URL.RawQuery]++
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/get":
+			w.Write([]byte(`{"run": {"info": {"run_id": "` + r.URL.Query().Get("run_id") + `"}}}`))
+		case "/api/2.0/mlflow/registered-models/get-latest-versions":
+			w.Write([]byte(`{"model_versions": [{"name": "model", "version": "3"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL, WithCache(50*time.Millisecond))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		run, err := client.GetRun(ctx, "run1")
+		if err != nil || run.Info.RunId != "run1" {
+			t.Fatalf("got %+v, %v", run, err)
+		}
+		versions, err := client.GetLatestVersions(ctx, "model", []string{StageProduction})
+		if err != nil || len(versions) != 1 {
+			t.Fatalf("got %+v, %v", versions, err)
+		}
+	}
+	client.GetRun(ctx, "run2")
+	if requests["/api/2.0/mlflow/runs/get?run_id=run1"] != 1 || requests["/api/2.0/mlflow/runs/get?run_id=run2"] != 1 || requests["/api/2.0/mlflow/registered-models/get-latest-versions?"] != 1 {
+		t.Errorf("unexpected requests %v", requests)
+	}
+	// Errors are not cached.
+	client.GetExperiment(ctx, "1")
+	client.GetExperiment(ctx, "1")
+	if requests["/api/2.0/mlflow/experiments/get?experiment_id=1"] != 2 {
+		t.Errorf("unexpected requests %v", requests)
+	}
+
+	client.InvalidateCache()
+	client.GetRun(ctx, "run1")
+	time.Sleep(60 * time.Millisecond)
+	client.GetRun(ctx, "run1")
+	if requests["/api/2.0/mlflow/runs/get?run_id=run1"] != 3 {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
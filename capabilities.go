@@ -0,0 +1,133 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Capabilities records which optional APIs a tracking server supports.
+type Capabilities struct {
+	// Version is the MLflow version the server reports, empty when it has
+	// no /version endpoint.
+	Version string
+	// Aliases is set by MLflow 2.3 and later.
+	Aliases bool
+	// Traces is set by MLflow 2.14 and later.
+	Traces bool
+	// LoggedModels is set by MLflow 3.0 and later.
+	LoggedModels bool
+	// ArtifactProxy is set when the server runs with --serve-artifacts.
+	ArtifactProxy bool
+}
+
+// Ping checks that the tracking server is up.
+func (p *Client) Ping(ctx context.Context) error {
+	url := p.BaseUrl + "/health"
+	_, err := p.HandleGet(ctx, url, nil)
+	return err
+}
+
+func (p *Client) ServerVersion(ctx context.Context) (string, error) {
+	url := p.BaseUrl + "/version"
+	body, err := p.HandleGet(ctx, url, nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// probeId is looked up by the probes of ProbeCapabilities, which expect it
+// not to exist.
+const probeId = "go-mlflow-capability-probe"
+
+// endpointMissing reports whether err means that the server has no handler
+// for the endpoint, as opposed to rejecting the request: servers answer
+// unknown paths with a 404 page without an error code.
+func endpointMissing(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return e.ErrorCode == "" || e.ErrorCode == "ENDPOINT_NOT_FOUND"
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// ProbeCapabilities finds out which optional APIs the tracking server
+// supports by requesting a resource that does not exist from each, and
+// records the result: afterwards, methods of the client that need an
+// unsupported API fail right away with an IsNotImplemented error instead
+// of an obscure 404.
+func (p *Client) ProbeCapabilities(ctx context.Context) (*Capabilities, error) {
+	c := &Capabilities{}
+	version, err := p.ServerVersion(ctx)
+	if err != nil && !endpointMissing(err) {
+		return nil, err
+	}
+	c.Version = version
+	probes := []struct {
+		supported *bool
+		url       string
+		params    map[string]interface{}
+	}{
+		{&c.Aliases, p.BaseUrl + "/api/2.0/mlflow/registered-models/alias", map[string]interface{}{"name": probeId, "alias": probeId}},
+		{&c.Traces, p.BaseUrl + "/api/2.0/mlflow/traces/" + probeId + "/info", nil},
+		{&c.LoggedModels, p.BaseUrl + "/api/2.0/mlflow/logged-models/" + probeId, nil},
+		{&c.ArtifactProxy, p.BaseUrl + "/api/2.0/mlflow-artifacts/artifacts", map[string]interface{}{"path": probeId}},
+	}
+	for _, probe := range probes {
+		_, err := p.HandleGet(ctx, probe.url, probe.params)
+		var e *Error
+		if err != nil && (!errors.As(err, &e) || IsUnauthenticated(err) || IsPermissionDenied(err)) {
+			return nil, err
+		}
+		// Servers without --serve-artifacts disable the proxy with 503.
+		*probe.supported = err == nil || !endpointMissing(err) && e.StatusCode != http.StatusServiceUnavailable
+	}
+	p.capabilities.Store(c)
+	return c, nil
+}
+
+// Capabilities returns what the last ProbeCapabilities found, or nil
+// before the server was probed.
+func (p *Client) Capabilities() *Capabilities {
+	c, _ := p.capabilities.Load().(*Capabilities)
+	return c
+}
+
+// require returns an IsNotImplemented error when the server was probed and
+// lacks feature.
+func (p *Client) require(feature string, supported func(c *Capabilities) bool) error {
+	c := p.Capabilities()
+	if c == nil || supported(c) {
+		return nil
+	}
+	server := p.BaseUrl
+	if u, err := url.Parse(p.BaseUrl); err == nil {
+		server = u.Host
+	}
+	if c.Version != "" {
+		server += " (MLflow " + c.Version + ")"
+	}
+	return &Error{StatusCode: http.StatusNotImplemented, ErrorCode: ErrorCodeNotImplemented, Message: fmt.Sprintf("the tracking server %s does not support %s", server, feature)}
+}
+
+func (p *Client) requireAliases() error {
+	return p.require("registered model aliases", func(c *Capabilities) bool { return c.Aliases })
+}
+
+func (p *Client) requireTraces() error {
+	return p.require("traces", func(c *Capabilities) bool { return c.Traces })
+}
+
+func (p *Client) requireArtifactProxy() error {
+	return p.require("the mlflow-artifacts proxy", func(c *Capabilities) bool { return c.ArtifactProxy })
+}
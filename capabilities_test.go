@@ -0,0 +1,97 @@
+package mlflow_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	if err := client.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+	version, err := client.ServerVersion(ctx)
+	if err != nil || version != mlflowtest.Version {
+		t.Errorf("unexpected version %q, %v", version, err)
+	}
+	if client.Capabilities() != nil {
+		t.Error("expected no capabilities before probing")
+	}
+	c, err := client.ProbeCapabilities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mlflow.Capabilities{Version: mlflowtest.Version, Aliases: true, ArtifactProxy: true}
+	if *c != expected || client.Capabilities() != c {
+		t.Errorf("unexpected capabilities %+v", c)
+	}
+
+	server.ClearRequests()
+	_, err = client.StartTrace(ctx, "0", nil)
+	if !mlflow.IsNotImplemented(err) || !strings.Contains(err.Error(), "does not support traces") {
+		t.Errorf("expected traces to be unsupported, got %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("expected no request, got %+v", server.Requests())
+	}
+	if _, err := client.GetModelVersionByAlias(ctx, "model", "champion"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected aliases to be requested, got %v", err)
+	}
+}
+
+// TestProbeOldServer probes a server predating every optional API, which
+// answers unknown paths with an HTML 404 page.
+func TestProbeOldServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/health":
+			w.Write([]byte("OK"))
+		case strings.HasPrefix(r.URL.Path, "/api/2.0/mlflow-artifacts/"):
+			http.Error(w, `{"error_code": "TEMPORARILY_UNAVAILABLE", "message": "disabled due to --no-serve-artifacts"}`, http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "<html>404 Not Found</html>", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	if err := client.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.ProbeCapabilities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c != (mlflow.Capabilities{}) {
+		t.Errorf("expected no capabilities, got %+v", c)
+	}
+	if err := client.SetRegisteredModelAlias(ctx, "model", "champion", "1"); !mlflow.IsNotImplemented(err) || !strings.Contains(err.Error(), "aliases") {
+		t.Errorf("expected aliases to be unsupported, got %v", err)
+	}
+	if _, err := client.ArtifactRepository("mlflow-artifacts:/0/abc/artifacts"); !mlflow.IsNotImplemented(err) {
+		t.Errorf("expected the artifact proxy to be unsupported, got %v", err)
+	}
+}
+
+func TestProbeUnauthenticated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code": "UNAUTHENTICATED", "message": "missing credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	if _, err := client.ProbeCapabilities(context.Background()); !mlflow.IsUnauthenticated(err) {
+		t.Errorf("expected the probe to fail, got %v", err)
+	}
+	if client.Capabilities() != nil {
+		t.Error("expected a failed probe to record nothing")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"flag"
+)
+
+func downloadArtifacts(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("artifacts download", flag.ContinueOnError)
+	runId := flags.String("run-id", "", "run whose artifacts are downloaded")
+	path := flags.String("path", "", "artifact path, all artifacts by default")
+	dst := flags.String("dst", ".", "local directory")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *runId == "" {
+		return errUsage
+	}
+	if err := c.client.DownloadArtifacts(ctx, *runId, *path, *dst); err != nil {
+		return err
+	}
+	return c.print(map[string]string{"run_id": *runId, "path": *path, "dst": *dst}, []string{"RUN_ID", "PATH", "DST"}, [][]string{{*runId, *path, *dst}})
+}
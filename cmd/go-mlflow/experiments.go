@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func listExperiments(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("experiments list", flag.ContinueOnError)
+	filter := flags.String("filter", "", "search filter")
+	view := flags.String("view", string(mlflow.ActiveOnly), "ACTIVE_ONLY, DELETED_ONLY or ALL")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	experiments, err := c.client.IterExperiments(ctx, *filter, mlflow.ViewType(*view), nil).All()
+	if err != nil {
+		return err
+	}
+	if experiments == nil {
+		experiments = []mlflow.Experiment{}
+	}
+	var rows [][]string
+	for _, experiment := range experiments {
+		rows = append(rows, []string{experiment.ExperimentId, experiment.Name, experiment.LifecycleStage, experiment.ArtifactLocation})
+	}
+	return c.print(experiments, []string{"ID", "NAME", "STAGE", "ARTIFACT_LOCATION"}, rows)
+}
+
+func createExperiment(ctx context.Context, c *cli, args []string) error {
+	args, err := c.parse(flag.NewFlagSet("experiments create", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	experimentId, err := c.client.CreateExperiment(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]string{"experiment_id": *experimentId}, []string{"ID"}, [][]string{{*experimentId}})
+}
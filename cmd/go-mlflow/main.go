@@ -0,0 +1,159 @@
+// Command go-mlflow manages the experiments, runs, artifacts and registered
+// models of an MLflow tracking server:
+//
+//	go-mlflow [-tracking-uri uri] [-o table|json] <group> <command> [flags] [args]
+//
+//	experiments list    [-filter f] [-view ACTIVE_ONLY|DELETED_ONLY|ALL]
+//	experiments create  name
+//	runs search         -experiment-ids ids [-filter f] [-order-by o] [-max n]
+//	runs get            run-id
+//	runs delete         run-id...
+//	artifacts download  -run-id id [-path p] [-dst dir]
+//	models promote      name version [-stage s] [-alias a] [-archive-existing]
+//
+// The client is configured from the MLFLOW_* environment variables, see
+// mlflow.NewFromEnv; -tracking-uri overrides MLFLOW_TRACKING_URI.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"experiments": {
+		"list":   {"[-filter f] [-view ACTIVE_ONLY|DELETED_ONLY|ALL]", listExperiments},
+		"create": {"name", createExperiment},
+	},
+	"runs": {
+		"search": {"-experiment-ids ids [-filter f] [-order-by o] [-max n]", searchRuns},
+		"get":    {"run-id", getRun},
+		"delete": {"run-id...", deleteRuns},
+	},
+	"artifacts": {
+		"download": {"-run-id id [-path p] [-dst dir]", downloadArtifacts},
+	},
+	"models": {
+		"promote": {"name version [-stage s] [-alias a] [-archive-existing]", promoteModel},
+	},
+}
+
+// errUsage is returned by commands called with invalid arguments, after
+// printing their usage.
+var errUsage = errors.New("usage")
+
+// cli is the state shared by commands.
+type cli struct {
+	client *mlflow.Client
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("go-mlflow", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	trackingUri := flags.String("tracking-uri", "", "tracking server, instead of MLFLOW_TRACKING_URI")
+	output := flags.String("o", "table", "output format, table or json")
+	flags.Usage = func() { usage(flags) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "go-mlflow: unknown output format %q\n", *output)
+		return 2
+	}
+	args = flags.Args()
+	if len(args) < 2 {
+		usage(flags)
+		return 2
+	}
+	cmd, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "go-mlflow: unknown command %s %s\n", args[0], args[1])
+		usage(flags)
+		return 2
+	}
+	if *trackingUri != "" {
+		os.Setenv("MLFLOW_TRACKING_URI", *trackingUri)
+	}
+	client, err := mlflow.NewFromEnv()
+	if err != nil {
+		fmt.Fprintf(stderr, "go-mlflow: %v\n", err)
+		return 1
+	}
+	c := &cli{client: client, stdout: stdout, stderr: stderr, output: *output}
+	if err := cmd.run(ctx, c, args[2:]); err != nil {
+		if err == errUsage {
+			fmt.Fprintf(stderr, "usage: go-mlflow %s %s %s\n", args[0], args[1], cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "go-mlflow: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func usage(flags *flag.FlagSet) {
+	w := flags.Output()
+	fmt.Fprintln(w, "usage: go-mlflow [-tracking-uri uri] [-o table|json] <group> <command> [flags] [args]")
+	var groups []string
+	for group := range commands {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		var names []string
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s %s %s\n", group, name, commands[group][name].usage)
+		}
+	}
+	fmt.Fprintln(w, "flags:")
+	flags.PrintDefaults()
+}
+
+// parse parses the flags of a command and checks that it got between min
+// and max args, max < 0 meaning any number.
+func (c *cli) parse(flags *flag.FlagSet, args []string, min int, max int) ([]string, error) {
+	flags.SetOutput(c.stderr)
+	if err := flags.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	args = flags.Args()
+	if len(args) < min || max >= 0 && len(args) > max {
+		return nil, errUsage
+	}
+	return args, nil
+}
+
+func splitList(s string) []string {
+	var values []string
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
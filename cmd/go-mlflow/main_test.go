@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func runCli(t *testing.T, server *mlflowtest.Server, args ...string) (string, int) {
+	t.Helper()
+	t.Setenv("MLFLOW_TRACKING_URI", server.URL)
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	if code != 0 {
+		t.Logf("go-mlflow %s: %s", strings.Join(args, " "), stderr.String())
+	}
+	return stdout.String(), code
+}
+
+func TestExperiments(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	if _, code := runCli(t, server, "experiments", "create", "exp"); code != 0 {
+		t.Fatalf("create exited with %d", code)
+	}
+	out, code := runCli(t, server, "experiments", "list")
+	if code != 0 {
+		t.Fatalf("list exited with %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(out, " exp ") {
+		t.Errorf("unexpected table\n%s", out)
+	}
+	out, _ = runCli(t, server, "-o", "json", "experiments", "list", "-filter", "name = 'exp'")
+	var experiments []mlflow.Experiment
+	if err := json.Unmarshal([]byte(out), &experiments); err != nil || len(experiments) != 1 || experiments[0].Name != "exp" {
+		t.Errorf("unexpected json %s, %v", out, err)
+	}
+}
+
+func TestRuns(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	run, err := client.CreateRun(ctx, "0", mlflow.WithRunName("train"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := client.LogParam(ctx, runId, "lr", "0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogText(ctx, runId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := runCli(t, server, "runs", "search", "-experiment-ids", "0", "-filter", "params.lr = '0.1'")
+	if code != 0 || !strings.Contains(out, runId+"  train") {
+		t.Errorf("unexpected search output (%d)\n%s", code, out)
+	}
+	out, _ = runCli(t, server, "-o", "json", "runs", "get", runId)
+	var got mlflow.Run
+	if err := json.Unmarshal([]byte(out), &got); err != nil || got.Info.RunName != "train" {
+		t.Errorf("unexpected run %s, %v", out, err)
+	}
+
+	dir := t.TempDir()
+	if _, code := runCli(t, server, "artifacts", "download", "-run-id", runId, "-dst", dir); code != 0 {
+		t.Errorf("download exited with %d", code)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+
+	if _, code := runCli(t, server, "runs", "delete", runId); code != 0 {
+		t.Errorf("delete exited with %d", code)
+	}
+	if run, err := client.GetRun(ctx, runId); err != nil || run.Info.LifecycleStage != "deleted" {
+		t.Errorf("expected the run to be deleted, got %+v, %v", run, err)
+	}
+	if _, code := runCli(t, server, "runs", "get", "missing"); code != 1 {
+		t.Errorf("expected a missing run to exit with 1, got %d", code)
+	}
+	if _, code := runCli(t, server, "runs", "search"); code != 2 {
+		t.Errorf("expected a usage error, got %d", code)
+	}
+}
+
+func TestPromoteModel(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	if _, err := client.CreateRegisteredModel(ctx, "model", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateModelVersion(ctx, "model", "runs:/a/model", ""); err != nil {
+		t.Fatal(err)
+	}
+	out, code := runCli(t, server, "models", "promote", "model", "1", "-stage", mlflow.StageProduction, "-alias", "champion")
+	if code != 0 || !strings.Contains(out, "Production  champion") {
+		t.Errorf("unexpected promote output (%d)\n%s", code, out)
+	}
+	version, err := client.GetModelVersionByAlias(ctx, "model", "champion")
+	if err != nil || version.CurrentStage != mlflow.StageProduction {
+		t.Errorf("unexpected version %+v, %v", version, err)
+	}
+	if _, code := runCli(t, server, "models", "promote", "model", "1"); code != 2 {
+		t.Errorf("expected promoting without a stage or alias to fail, got %d", code)
+	}
+}
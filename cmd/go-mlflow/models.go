@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+)
+
+func promoteModel(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("models promote", flag.ContinueOnError)
+	stage := flags.String("stage", "", "stage the version is transitioned to, such as Production")
+	alias := flags.String("alias", "", "alias set to the version, such as champion")
+	archiveExisting := flags.Bool("archive-existing", false, "archive the other versions in -stage")
+	// Flags may follow the name and version.
+	var positional []string
+	for len(args) > 0 {
+		rest, err := c.parse(flags, args, 0, -1)
+		if err != nil {
+			return err
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional, args = append(positional, rest[0]), rest[1:]
+	}
+	if len(positional) != 2 || *stage == "" && *alias == "" {
+		return errUsage
+	}
+	name, version := positional[0], positional[1]
+	if *stage != "" {
+		if _, err := c.client.TransitionModelVersionStage(ctx, name, version, *stage, *archiveExisting); err != nil {
+			return err
+		}
+	}
+	if *alias != "" {
+		if err := c.client.SetRegisteredModelAlias(ctx, name, *alias, version); err != nil {
+			return err
+		}
+	}
+	modelVersion, err := c.client.GetModelVersion(ctx, name, version)
+	if err != nil {
+		return err
+	}
+	return c.print(modelVersion, []string{"NAME", "VERSION", "STAGE", "ALIASES"}, [][]string{{modelVersion.Name, modelVersion.Version, modelVersion.CurrentStage, strings.Join(modelVersion.Aliases, ",")}})
+}
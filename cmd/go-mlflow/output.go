@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+// print writes v as indented JSON with -o json, or else the table of
+// header and rows.
+func (c *cli) print(v interface{}, header []string, rows [][]string) error {
+	if c.output == "json" {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func formatTime(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return mlflow.FromMillis(millis).Format("2006-01-02 15:04:05")
+}
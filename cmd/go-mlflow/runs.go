@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func searchRuns(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("runs search", flag.ContinueOnError)
+	experimentIds := flags.String("experiment-ids", "", "comma separated experiment ids")
+	filter := flags.String("filter", "", "search filter")
+	orderBy := flags.String("order-by", "", "comma separated order by clauses")
+	view := flags.String("view", string(mlflow.ActiveOnly), "ACTIVE_ONLY, DELETED_ONLY or ALL")
+	max := flags.Int("max", 0, "maximum number of runs, 0 for all")
+	if _, err := c.parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *experimentIds == "" {
+		return errUsage
+	}
+	it := c.client.IterRuns(ctx, splitList(*experimentIds), *filter, mlflow.ViewType(*view), splitList(*orderBy))
+	runs := []mlflow.Run{}
+	for (*max <= 0 || len(runs) < *max) && it.Next() {
+		runs = append(runs, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, run := range runs {
+		info := run.Info
+		rows = append(rows, []string{info.RunId, info.RunName, info.ExperimentId, info.Status, formatTime(info.StartTime), formatTime(info.EndTime)})
+	}
+	return c.print(runs, []string{"RUN_ID", "NAME", "EXPERIMENT_ID", "STATUS", "START_TIME", "END_TIME"}, rows)
+}
+
+func getRun(ctx context.Context, c *cli, args []string) error {
+	args, err := c.parse(flag.NewFlagSet("runs get", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	run, err := c.client.GetRun(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	info := run.Info
+	rows := [][]string{
+		{"info", "run_id", info.RunId},
+		{"info", "run_name", info.RunName},
+		{"info", "experiment_id", info.ExperimentId},
+		{"info", "status", info.Status},
+		{"info", "start_time", formatTime(info.StartTime)},
+		{"info", "end_time", formatTime(info.EndTime)},
+		{"info", "artifact_uri", info.ArtifactUri},
+	}
+	for _, param := range run.Data.Params {
+		rows = append(rows, []string{"param", param.Key, param.Value})
+	}
+	for _, metric := range run.Data.Metrics {
+		rows = append(rows, []string{"metric", metric.Key, strconv.FormatFloat(metric.Value, 'g', -1, 64)})
+	}
+	for _, tag := range run.Data.Tags {
+		rows = append(rows, []string{"tag", tag.Key, tag.Value})
+	}
+	return c.print(run, []string{"TYPE", "KEY", "VALUE"}, rows)
+}
+
+func deleteRuns(ctx context.Context, c *cli, args []string) error {
+	args, err := c.parse(flag.NewFlagSet("runs delete", flag.ContinueOnError), args, 1, -1)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, runId := range args {
+		if err := c.client.DeleteRun(ctx, runId); err != nil {
+			return err
+		}
+		rows = append(rows, []string{runId})
+	}
+	return c.print(map[string][]string{"deleted": args}, []string{"DELETED"}, rows)
+}
@@ -0,0 +1,198 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// DiffChange is how a value differs across compared runs, relative to the
+// first run.
+type DiffChange string
+
+const (
+	Unchanged DiffChange = "unchanged"
+	Changed   DiffChange = "changed"
+	// Added values are missing from the first run.
+	Added DiffChange = "added"
+	// Removed values are set on the first run but missing from another.
+	Removed DiffChange = "removed"
+)
+
+// ValueDiff is a param, tag or metric of compared runs. Values and Present
+// hold its value on each run, in the order of RunComparison.RunIds.
+// Metrics are their latest value, formatted.
+type ValueDiff struct {
+	Key     string
+	Values  []string
+	Present []bool
+	Change  DiffChange
+}
+
+// RunComparison is a diff of the params, tags and latest metrics of runs,
+// sorted by key. System tags, prefixed "mlflow.", are left out.
+type RunComparison struct {
+	RunIds   []string
+	RunNames []string
+	Params   []ValueDiff
+	Tags     []ValueDiff
+	Metrics  []ValueDiff
+}
+
+// CompareRuns fetches runIds and compares them to the first one.
+func (p *Client) CompareRuns(ctx context.Context, runIds ...string) (*RunComparison, error) {
+	if len(runIds) < 2 {
+		return nil, errors.New("mlflow: comparing runs needs at least two runs")
+	}
+	var runs []*Run
+	for _, runId := range runIds {
+		run, err := p.GetRun(ctx, runId)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return NewRunComparison(runs...), nil
+}
+
+// NewRunComparison compares runs to the first one.
+func NewRunComparison(runs ...*Run) *RunComparison {
+	c := &RunComparison{}
+	params := make([]map[string]string, len(runs))
+	tags := make([]map[string]string, len(runs))
+	metrics := make([]map[string]string, len(runs))
+	for i, run := range runs {
+		c.RunIds = append(c.RunIds, run.Info.RunId)
+		c.RunNames = append(c.RunNames, run.Info.RunName)
+		params[i], tags[i], metrics[i] = map[string]string{}, map[string]string{}, map[string]string{}
+		for _, param := range run.Data.Params {
+			params[i][param.Key] = param.Value
+		}
+		for _, tag := range run.Data.Tags {
+			if !strings.HasPrefix(tag.Key, "mlflow.") {
+				tags[i][tag.Key] = tag.Value
+			}
+		}
+		for _, metric := range run.Data.Metrics {
+			metrics[i][metric.Key] = strconv.FormatFloat(metric.Value, 'g', -1, 64)
+		}
+	}
+	c.Params = diffValues(params)
+	c.Tags = diffValues(tags)
+	c.Metrics = diffValues(metrics)
+	return c
+}
+
+func diffValues(values []map[string]string) []ValueDiff {
+	keys := map[string]bool{}
+	for _, v := range values {
+		for key := range v {
+			keys[key] = true
+		}
+	}
+	var diffs []ValueDiff
+	for _, key := range sortedKeys(keys) {
+		diff := ValueDiff{Key: key, Change: Unchanged}
+		for i, v := range values {
+			value, ok := v[key]
+			diff.Values = append(diff.Values, value)
+			diff.Present = append(diff.Present, ok)
+			switch {
+			case i == 0 || diff.Change == Removed:
+			case !diff.Present[0] && ok:
+				diff.Change = Added
+			case diff.Present[0] && !ok:
+				diff.Change = Removed
+			case ok && value != diff.Values[0] && diff.Change == Unchanged:
+				diff.Change = Changed
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// Differences reports whether any param, tag or metric differs.
+func (c *RunComparison) Differences() bool {
+	for _, diffs := range [][]ValueDiff{c.Params, c.Tags, c.Metrics} {
+		for _, diff := range diffs {
+			if diff.Change != Unchanged {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rows returns the header and the rows of the values that differ, or of
+// every value when all is set.
+func (c *RunComparison) rows(all bool) ([]string, [][]string) {
+	header := []string{"type", "key"}
+	for i, runId := range c.RunIds {
+		if c.RunNames[i] != "" {
+			header = append(header, c.RunNames[i])
+		} else {
+			header = append(header, runId)
+		}
+	}
+	header = append(header, "change")
+	var rows [][]string
+	for _, section := range []struct {
+		kind  string
+		diffs []ValueDiff
+	}{{"param", c.Params}, {"metric", c.Metrics}, {"tag", c.Tags}} {
+		for _, diff := range section.diffs {
+			if !all && diff.Change == Unchanged {
+				continue
+			}
+			row := []string{section.kind, diff.Key}
+			for i, value := range diff.Values {
+				if !diff.Present[i] {
+					value = "-"
+				}
+				row = append(row, value)
+			}
+			rows = append(rows, append(row, string(diff.Change)))
+		}
+	}
+	return header, rows
+}
+
+// Text renders the values that differ as an aligned plain text table, or
+// every value when all is set.
+func (c *RunComparison) Text(all bool) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	header, rows := c.rows(all)
+	for _, row := range append([][]string{header}, rows...) {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return b.String()
+}
+
+// Markdown renders the values that differ as a Markdown table, as pasted
+// into pull requests, or every value when all is set.
+func (c *RunComparison) Markdown(all bool) string {
+	var b strings.Builder
+	header, rows := c.rows(all)
+	writeRow := func(row []string) {
+		for i, cell := range row {
+			row[i] = strings.NewReplacer("|", `\|`, "\n", " ").Replace(cell)
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+	}
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package mlflow_test
+
+import (
+	"context"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestCompareRuns(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	var runIds []string
+	for _, run := range []struct {
+		name    string
+		params  map[string]string
+		metrics map[string]float64
+		tags    map[string]string
+	}{
+		{"baseline", map[string]string{"lr": "0.1", "epochs": "10", "dropout": "0.5"}, map[string]float64{"loss": 0.5}, map[string]string{"team": "ml"}},
+		{"candidate", map[string]string{"lr": "0.01", "epochs": "10", "layers": "4"}, map[string]float64{"loss": 0.25}, map[string]string{"team": "ml"}},
+	} {
+		r, err := client.CreateRun(ctx, "0", mlflow.WithRunName(run.name), mlflow.WithTags(run.tags))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.LogParams(ctx, r.Info.RunId, run.params); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.LogMetrics(ctx, r.Info.RunId, run.metrics, 0); err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, r.Info.RunId)
+	}
+
+	comparison, err := client.CompareRuns(ctx, runIds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := map[string]mlflow.DiffChange{}
+	for _, diff := range comparison.Params {
+		changes[diff.Key] = diff.Change
+	}
+	expected := map[string]mlflow.DiffChange{"dropout": mlflow.Removed, "epochs": mlflow.Unchanged, "layers": mlflow.Added, "lr": mlflow.Changed}
+	for key, change := range expected {
+		if changes[key] != change {
+			t.Errorf("%s: expected %s, got %s", key, change, changes[key])
+		}
+	}
+	if len(comparison.Tags) != 1 || comparison.Tags[0].Change != mlflow.Unchanged {
+		t.Errorf("expected only the user tag, got %+v", comparison.Tags)
+	}
+	if !comparison.Differences() {
+		t.Error("expected differences")
+	}
+
+	markdown := "| type | key | baseline | candidate | change |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| param | dropout | 0.5 | - | removed |\n" +
+		"| param | layers | - | 4 | added |\n" +
+		"| param | lr | 0.1 | 0.01 | changed |\n" +
+		"| metric | loss | 0.5 | 0.25 | changed |\n"
+	if s := comparison.Markdown(false); s != markdown {
+		t.Errorf("unexpected markdown\n%s", s)
+	}
+	text := "type    key      baseline  candidate  change\n" +
+		"param   dropout  0.5       -          removed\n" +
+		"param   epochs   10        10         unchanged\n" +
+		"param   layers   -         4          added\n" +
+		"param   lr       0.1       0.01       changed\n" +
+		"metric  loss     0.5       0.25       changed\n" +
+		"tag     team     ml        ml         unchanged\n"
+	if s := comparison.Text(true); s != text {
+		t.Errorf("unexpected text\n%s", s)
+	}
+
+	if _, err := client.CompareRuns(ctx, runIds[0]); err == nil {
+		t.Error("expected comparing a single run to fail")
+	}
+}
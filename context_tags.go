@@ -0,0 +1,85 @@
+package mlflow
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// Context tags recorded by ContextTags besides MLflow's system tags.
+const (
+	TagHostname = "hostname"
+	TagGitDirty = "git.dirty"
+)
+
+// ContextTags detects the context a run is created in, as the run context
+// providers of the Python client do: the OS user, the hostname, the
+// executable and, when dir is inside a git work tree, the commit, branch,
+// remote and whether there are uncommitted changes. An empty dir is the
+// working directory. Whatever can't be detected is left out.
+func ContextTags(dir string) map[string]string {
+	tags := map[string]string{TagSourceType: string(SourceTypeLocal)}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		tags[TagUser] = u.Username
+	} else if name := os.Getenv("USER"); name != "" {
+		tags[TagUser] = name
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		tags[TagHostname] = hostname
+	}
+	if executable, err := os.Executable(); err == nil {
+		tags[TagSourceName] = filepath.Base(executable)
+	}
+	git := func(args ...string) (string, bool) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err == nil
+	}
+	commit, ok := git("rev-parse", "HEAD")
+	if !ok {
+		return tags
+	}
+	tags[TagSourceGitCommit] = commit
+	if branch, ok := git("rev-parse", "--abbrev-ref", "HEAD"); ok && branch != "HEAD" {
+		tags[TagSourceGitBranch] = branch
+	}
+	if remote, ok := git("config", "--get", "remote.origin.url"); ok && remote != "" {
+		tags[TagSourceGitRepoUrl] = remote
+	}
+	if status, ok := git("status", "--porcelain", "--untracked-files=no"); ok {
+		if status != "" {
+			tags[TagGitDirty] = "true"
+		} else {
+			tags[TagGitDirty] = "false"
+		}
+	}
+	return tags
+}
+
+// WithContextTags adds the ContextTags of the working directory to the run.
+// Tags given with WithTags take precedence.
+func WithContextTags() RunOption {
+	return func(o *runOptions) {
+		o.contextTags = true
+	}
+}
+
+// mergeContextTags adds the context tags missing from o.tags.
+func (o *runOptions) mergeContextTags() {
+	if !o.contextTags {
+		return
+	}
+	set := map[string]bool{}
+	for _, tag := range o.tags {
+		set[tag.Key] = true
+	}
+	tags := ContextTags("")
+	for _, key := range sortedKeys(tags) {
+		if !set[key] {
+			o.tags = append(o.tags, RunTag{Key: key, Value: tags[key]})
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestContextTags(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "dev")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+	path := filepath.Join(dir, "train.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	tags := ContextTags(dir)
+	if len(tags[TagSourceGitCommit]) != 40 || tags[TagSourceGitBranch] != "main" || tags[TagSourceGitRepoUrl] != "https://example.com/repo.git" || tags[TagGitDirty] != "false" {
+		t.Errorf("unexpected git tags %v", tags)
+	}
+	if tags[TagSourceType] != "LOCAL" || tags[TagSourceName] == "" || tags[TagHostname] == "" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if tags := ContextTags(dir); tags[TagGitDirty] != "true" {
+		t.Errorf("expected a dirty work tree, got %v", tags)
+	}
+	if tags := ContextTags(t.TempDir()); tags[TagSourceGitCommit] != "" {
+		t.Errorf("expected no git tags outside a work tree, got %v", tags)
+	}
+}
+
+func TestWithContextTags(t *testing.T) {
+	var request struct {
+		Tags []RunTag `json:"tags"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"run": {"info": {"run_id": "run1"}}}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	if _, err := client.StartRun(context.Background(), "1", WithContextTags(), WithTags(map[string]string{TagUser: "ci"})); err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string][]string{}
+	for _, tag := range request.Tags {
+		tags[tag.Key] = append(tags[tag.Key], tag.Value)
+	}
+	if len(tags[TagUser]) != 1 || tags[TagUser][0] != "ci" {
+		t.Errorf("expected the explicit user tag to win, got %v", tags[TagUser])
+	}
+	if len(tags[TagHostname]) != 1 || len(tags[TagSourceType]) != 1 {
+		t.Errorf("expected context tags, got %v", tags)
+	}
+}
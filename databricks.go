@@ -0,0 +1,104 @@
+package mlflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewDatabricks returns a client for the MLflow tracking server of a
+// Databricks workspace, authenticated with a personal access token.
+func NewDatabricks(host string, token string, opts ...Option) *Client {
+	host = strings.TrimSuffix(host, "/")
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	p := New(host, append([]Option{WithToken(token)}, opts...)...)
+	p.databricks = true
+	return p
+}
+
+// NewDatabricksFromUri resolves a tracking uri of the form "databricks" or
+// "databricks://<profile>". The DEFAULT profile may come from DATABRICKS_HOST
+// and DATABRICKS_TOKEN; other profiles are read from ~/.databrickscfg, or the
+// file named by DATABRICKS_CONFIG_FILE.
+func NewDatabricksFromUri(uri string, opts ...Option) (*Client, error) {
+	if uri != "databricks" && !strings.HasPrefix(uri, "databricks://") {
+		return nil, fmt.Errorf("mlflow: %q is not a databricks tracking uri", uri)
+	}
+	profile := strings.TrimPrefix(uri, "databricks")
+	profile = strings.TrimPrefix(profile, "://")
+	if profile == "" {
+		profile = "DEFAULT"
+	}
+	if profile == "DEFAULT" && os.Getenv("DATABRICKS_HOST") != "" {
+		return NewDatabricks(os.Getenv("DATABRICKS_HOST"), os.Getenv("DATABRICKS_TOKEN"), opts...), nil
+	}
+	config, err := readDatabricksConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	if config["host"] == "" {
+		return nil, fmt.Errorf("mlflow: databricks profile %s has no host", profile)
+	}
+	return NewDatabricks(config["host"], config["token"], opts...), nil
+}
+
+// readDatabricksConfig returns the keys of profile in the databricks CLI
+// config file.
+func readDatabricksConfig(profile string) (map[string]string, error) {
+	path := os.Getenv("DATABRICKS_CONFIG_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".databrickscfg")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var config map[string]string
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile {
+				config = map[string]string{}
+			}
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		if i := strings.IndexAny(line, "=:"); i >= 0 {
+			config[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("mlflow: databricks profile %s not found in %s", profile, path)
+	}
+	return config, nil
+}
+
+// ajaxApiUrl returns the url of an endpoint that open source servers only
+// serve under /ajax-api. Databricks serves them with the rest of the API.
+func (p *Client) ajaxApiUrl(path string) string {
+	if p.databricks {
+		return p.BaseUrl + "/api/2.0/mlflow/" + path
+	}
+	return p.BaseUrl + "/ajax-api/2.0/mlflow/" + path
+}
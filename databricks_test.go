@@ -0,0 +1,52 @@
+package mlflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDatabricksFromUri(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".databrickscfg")
+	config := "[DEFAULT]\nhost = https://default.cloud.databricks.com/\ntoken = dapi-default\n\n# staging workspace\n[staging]\nhost = staging.cloud.databricks.com\ntoken = dapi-staging\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DATABRICKS_CONFIG_FILE", path)
+	t.Setenv("DATABRICKS_HOST", "")
+	t.Setenv("MLFLOW_TRACKING_TOKEN", "")
+
+	cases := []struct {
+		uri   string
+		host  string
+		token string
+	}{
+		{"databricks", "https://default.cloud.databricks.com", "dapi-default"},
+		{"databricks://staging", "https://staging.cloud.databricks.com", "dapi-staging"},
+	}
+	for _, c := range cases {
+		client, err := NewDatabricksFromUri(c.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.BaseUrl != c.host || client.token != c.token {
+			t.Errorf("%s: got %s %s", c.uri, client.BaseUrl, client.token)
+		}
+		if client.ajaxApiUrl("get-trace-artifact") != c.host+"/api/2.0/mlflow/get-trace-artifact" {
+			t.Errorf("%s: unexpected ajax url %s", c.uri, client.ajaxApiUrl("get-trace-artifact"))
+		}
+	}
+
+	t.Setenv("DATABRICKS_HOST", "env.cloud.databricks.com")
+	t.Setenv("DATABRICKS_TOKEN", "dapi-env")
+	client, err := NewDatabricksFromUri("databricks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.BaseUrl != "https://env.cloud.databricks.com" || client.token != "dapi-env" {
+		t.Errorf("got %s %s", client.BaseUrl, client.token)
+	}
+	if _, err := NewDatabricksFromUri("databricks://missing"); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
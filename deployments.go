@@ -0,0 +1,222 @@
+package mlflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DeploymentsClient talks to an MLflow Deployments Server (AI Gateway).
+type DeploymentsClient struct {
+	// HTTP client used to communicate with the deployments server.
+	Client  *http.Client
+	BaseUrl string
+}
+
+func NewDeploymentsClient(url string) *DeploymentsClient {
+	return &DeploymentsClient{
+		Client:  http.DefaultClient,
+		BaseUrl: strings.TrimSuffix(url, "/"),
+	}
+}
+
+const (
+	EndpointTypeChat        = "llm/v1/chat"
+	EndpointTypeCompletions = "llm/v1/completions"
+	EndpointTypeEmbeddings  = "llm/v1/embeddings"
+)
+
+type Endpoint struct {
+	Name         string         `json:"name"`
+	EndpointType string         `json:"endpoint_type"`
+	Model        EndpointModel  `json:"model"`
+	EndpointUrl  string         `json:"endpoint_url"`
+	Limit        *EndpointLimit `json:"limit,omitempty"`
+}
+
+type EndpointModel struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+}
+
+type EndpointLimit struct {
+	Calls         int    `json:"calls"`
+	RenewalPeriod string `json:"renewal_period"`
+}
+
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ChatRequest struct {
+	Messages    []ChatMessage `json:"messages"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+	N           int           `json:"n,omitempty"`
+}
+
+type ChatResponse struct {
+	Id      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   Usage        `json:"usage"`
+}
+
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type CompletionsRequest struct {
+	Prompt      string   `json:"prompt"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	N           int      `json:"n,omitempty"`
+}
+
+type CompletionsResponse struct {
+	Id      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []CompletionChoice `json:"choices"`
+	Usage   Usage              `json:"usage"`
+}
+
+type CompletionChoice struct {
+	Index        int    `json:"index"`
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+}
+
+type EmbeddingsRequest struct {
+	Input []string `json:"input"`
+}
+
+type EmbeddingsResponse struct {
+	Object string      `json:"object"`
+	Data   []Embedding `json:"data"`
+	Model  string      `json:"model"`
+	Usage  Usage       `json:"usage"`
+}
+
+type Embedding struct {
+	Object    string    `json:"object"`
+	Embedding []float64 `json:"embedding"`
+	Index     int       `json:"index"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens,omitempty"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (d *DeploymentsClient) do(ctx context.Context, method string, url string, request interface{}, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		e := newError(resp, b)
+		// The deployments server reports errors as FastAPI does.
+		var failure struct {
+			Detail string `json:"detail"`
+		}
+		if e.Message == "" && json.Unmarshal(b, &failure) == nil {
+			e.Message = failure.Detail
+		}
+		return e
+	}
+	return json.Unmarshal(b, response)
+}
+
+func (d *DeploymentsClient) ListEndpoints(ctx context.Context) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	pageToken := ""
+	for {
+		endpoint := d.BaseUrl + "/api/2.0/endpoints/"
+		if pageToken != "" {
+			endpoint += "?page_token=" + url.QueryEscape(pageToken)
+		}
+		var response struct {
+			Endpoints     []Endpoint `json:"endpoints"`
+			NextPageToken string     `json:"next_page_token"`
+		}
+		if err := d.do(ctx, "GET", endpoint, nil, &response); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, response.Endpoints...)
+		if response.NextPageToken == "" {
+			return endpoints, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
+
+func (d *DeploymentsClient) GetEndpoint(ctx context.Context, name string) (*Endpoint, error) {
+	var endpoint Endpoint
+	if err := d.do(ctx, "GET", d.BaseUrl+"/api/2.0/endpoints/"+url.PathEscape(name), nil, &endpoint); err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (d *DeploymentsClient) invocationsUrl(endpoint string) string {
+	return d.BaseUrl + "/endpoints/" + url.PathEscape(endpoint) + "/invocations"
+}
+
+func (d *DeploymentsClient) Chat(ctx context.Context, endpoint string, request ChatRequest) (*ChatResponse, error) {
+	var response ChatResponse
+	if err := d.do(ctx, "POST", d.invocationsUrl(endpoint), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (d *DeploymentsClient) Completions(ctx context.Context, endpoint string, request CompletionsRequest) (*CompletionsResponse, error) {
+	var response CompletionsResponse
+	if err := d.do(ctx, "POST", d.invocationsUrl(endpoint), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (d *DeploymentsClient) Embeddings(ctx context.Context, endpoint string, request EmbeddingsRequest) (*EmbeddingsResponse, error) {
+	var response EmbeddingsResponse
+	if err := d.do(ctx, "POST", d.invocationsUrl(endpoint), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
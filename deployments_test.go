@@ -0,0 +1,72 @@
+package mlflow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeploymentsClient(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/endpoints/":
+			if r.URL.Query().Get("page_token") == "" {
+				w.Write([]byte(`{"endpoints": [{"name": "chat", "endpoint_type": "llm/v1/chat", "model": {"name": "gpt-4o", "provider": "openai"}}], "next_page_token": "2"}`))
+			} else {
+				w.Write([]byte(`{"endpoints": [{"name": "embeddings", "endpoint_type": "llm/v1/embeddings"}]}`))
+			}
+		case "/api/2.0/endpoints/chat":
+			w.Write([]byte(`{"name": "chat", "endpoint_type": "llm/v1/chat", "model": {"name": "gpt-4o", "provider": "openai"}, "endpoint_url": "/endpoints/chat/invocations"}`))
+		case "/endpoints/chat/invocations":
+			b, _ := io.ReadAll(r.Body)
+			received = string(b)
+			w.Write([]byte(`{"id": "1", "object": "chat.completion", "model": "gpt-4o", "choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 3, "completion_tokens": 1, "total_tokens": 4}}`))
+		case "/endpoints/embeddings/invocations":
+			w.Write([]byte(`{"object": "list", "data": [{"object": "embedding", "embedding": [0.1, 0.2], "index": 0}], "usage": {"prompt_tokens": 1, "total_tokens": 1}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "The endpoint 'missing' is not present in the configuration."}`))
+		}
+	}))
+	defer server.Close()
+	client := NewDeploymentsClient(server.URL)
+
+	endpoints, err := client.ListEndpoints(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 || endpoints[0].Model.Provider != "openai" || endpoints[1].EndpointType != EndpointTypeEmbeddings {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+	endpoint, err := client.GetEndpoint(context.Background(), "chat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.EndpointUrl != "/endpoints/chat/invocations" {
+		t.Errorf("unexpected endpoint %+v", endpoint)
+	}
+
+	chat, err := client.Chat(context.Background(), "chat", ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hello"}}, MaxTokens: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received != `{"messages":[{"role":"user","content":"hello"}],"max_tokens":10}` {
+		t.Errorf("unexpected payload %s", received)
+	}
+	if len(chat.Choices) != 1 || chat.Choices[0].Message.Content != "hi" || chat.Usage.TotalTokens != 4 {
+		t.Errorf("unexpected response %+v", chat)
+	}
+	embeddings, err := client.Embeddings(context.Background(), "embeddings", EmbeddingsRequest{Input: []string{"hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embeddings.Data) != 1 || embeddings.Data[0].Embedding[1] != 0.2 {
+		t.Errorf("unexpected response %+v", embeddings)
+	}
+	if _, err := client.GetEndpoint(context.Background(), "missing"); err == nil {
+		t.Error("expected an error for a missing endpoint")
+	}
+}
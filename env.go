@@ -0,0 +1,125 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewFromEnv returns a client configured from the environment variables the
+// Python client reads:
+//
+//	MLFLOW_TRACKING_URI                 tracking server, or "databricks[://profile]"
+//	MLFLOW_TRACKING_USERNAME/PASSWORD   basic auth
+//	MLFLOW_TRACKING_TOKEN               bearer token
+//	MLFLOW_TRACKING_INSECURE_TLS        "true" skips server certificate checks
+//	MLFLOW_TRACKING_SERVER_CERT_PATH    CA bundle verifying the server
+//	MLFLOW_TRACKING_CLIENT_CERT_PATH    PEM file with a client certificate and key
+//	MLFLOW_TRACKING_AWS_SIGV4           "true" signs requests with AWS SigV4
+//	MLFLOW_HTTP_REQUEST_TIMEOUT         request timeout in seconds
+//	MLFLOW_HTTP_REQUEST_MAX_RETRIES     retries of failed requests
+//
+// opts are applied after the environment, so they take precedence. See
+// DefaultExperimentId for MLFLOW_EXPERIMENT_NAME and MLFLOW_EXPERIMENT_ID.
+func NewFromEnv(opts ...Option) (*Client, error) {
+	uri := os.Getenv("MLFLOW_TRACKING_URI")
+	if uri == "" {
+		return nil, errors.New("mlflow: MLFLOW_TRACKING_URI is not set")
+	}
+	envOpts, err := optionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(envOpts, opts...)
+	if uri == "databricks" || strings.HasPrefix(uri, "databricks://") {
+		return NewDatabricksFromUri(uri, opts...)
+	}
+	return New(strings.TrimSuffix(uri, "/"), opts...), nil
+}
+
+func optionsFromEnv() ([]Option, error) {
+	var opts []Option
+	config := &tls.Config{}
+	configured := false
+	if envBool("MLFLOW_TRACKING_INSECURE_TLS") {
+		config.InsecureSkipVerify = true
+		configured = true
+	}
+	if path := os.Getenv("MLFLOW_TRACKING_SERVER_CERT_PATH"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("mlflow: no certificates in %s", path)
+		}
+		config.RootCAs = pool
+		configured = true
+	}
+	if path := os.Getenv("MLFLOW_TRACKING_CLIENT_CERT_PATH"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(pem, pem)
+		if err != nil {
+			return nil, fmt.Errorf("mlflow: client certificate %s: %w", path, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+		configured = true
+	}
+	if configured {
+		opts = append(opts, WithTlsConfig(config))
+	}
+	if envBool("MLFLOW_TRACKING_AWS_SIGV4") {
+		opts = append(opts, WithRequestSigner(NewSigV4Signer("", "")))
+	}
+	if s := os.Getenv("MLFLOW_HTTP_REQUEST_TIMEOUT"); s != "" {
+		seconds, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mlflow: invalid MLFLOW_HTTP_REQUEST_TIMEOUT %q", s)
+		}
+		opts = append(opts, WithTimeout(time.Duration(seconds*float64(time.Second))))
+	}
+	if s := os.Getenv("MLFLOW_HTTP_REQUEST_MAX_RETRIES"); s != "" {
+		retries, err := strconv.Atoi(s)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("mlflow: invalid MLFLOW_HTTP_REQUEST_MAX_RETRIES %q", s)
+		}
+		if retries > 0 {
+			policy := DefaultRetryPolicy
+			policy.MaxAttempts = retries + 1
+			opts = append(opts, WithRetry(policy))
+		}
+	}
+	return opts, nil
+}
+
+func envBool(key string) bool {
+	b, _ := strconv.ParseBool(os.Getenv(key))
+	return b
+}
+
+// DefaultExperimentId returns the experiment named by MLFLOW_EXPERIMENT_NAME,
+// creating it if needed, else MLFLOW_EXPERIMENT_ID, else the default
+// experiment "0", as the Python client does.
+func (p *Client) DefaultExperimentId(ctx context.Context) (string, error) {
+	if name := os.Getenv("MLFLOW_EXPERIMENT_NAME"); name != "" {
+		experiment, err := p.GetOrCreateExperiment(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return experiment.ExperimentId, nil
+	}
+	if experimentId := os.Getenv("MLFLOW_EXPERIMENT_ID"); experimentId != "" {
+		return experimentId, nil
+	}
+	return "0", nil
+}
@@ -0,0 +1,119 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"experiment": {"experiment_id": "7", "name": "nlp"}}`))
+	}))
+	defer server.Close()
+	certPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(certPath, caPem, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MLFLOW_TRACKING_URI", "")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected an error without MLFLOW_TRACKING_URI")
+	}
+	t.Setenv("MLFLOW_TRACKING_URI", server.URL+"/")
+	t.Setenv("MLFLOW_TRACKING_TOKEN", "secret")
+	t.Setenv("MLFLOW_TRACKING_SERVER_CERT_PATH", certPath)
+	t.Setenv("MLFLOW_HTTP_REQUEST_TIMEOUT", "2.5")
+	t.Setenv("MLFLOW_HTTP_REQUEST_MAX_RETRIES", "3")
+	client, err := NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.BaseUrl != server.URL || client.timeout != 2500*time.Millisecond || len(client.middleware) != 1 {
+		t.Errorf("unexpected client %s %v %d", client.BaseUrl, client.timeout, len(client.middleware))
+	}
+	if _, err := client.GetExperiment(context.Background(), "7"); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("unexpected authorization %q", authorization)
+	}
+
+	t.Setenv("MLFLOW_TRACKING_CLIENT_CERT_PATH", certPath)
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected an error for a client certificate without a key")
+	}
+	t.Setenv("MLFLOW_TRACKING_CLIENT_CERT_PATH", "")
+	t.Setenv("MLFLOW_HTTP_REQUEST_MAX_RETRIES", "many")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected an error for invalid retries")
+	}
+	t.Setenv("MLFLOW_HTTP_REQUEST_MAX_RETRIES", "")
+
+	t.Setenv("MLFLOW_TRACKING_URI", "databricks")
+	t.Setenv("DATABRICKS_HOST", "example.cloud.databricks.com")
+	t.Setenv("DATABRICKS_TOKEN", "dapi")
+	client, err = NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.BaseUrl != "https://example.cloud.databricks.com" || !client.databricks {
+		t.Errorf("unexpected databricks client %s", client.BaseUrl)
+	}
+}
+
+func TestDefaultExperimentId(t *testing.T) {
+	created := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/experiments/get-by-name":
+			if r.URL.Query().Get("experiment_name") == "nlp" {
+				w.Write([]byte(`{"experiment": {"experiment_id": "7", "name": "nlp"}}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no experiment"}`))
+		case "/api/2.0/mlflow/experiments/create":
+			created = "8"
+			w.Write([]byte(`{"experiment_id": "8"}`))
+		case "/api/2.0/mlflow/experiments/get":
+			w.Write([]byte(`{"experiment": {"experiment_id": "8", "name": "vision"}}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	t.Setenv("MLFLOW_EXPERIMENT_NAME", "")
+	t.Setenv("MLFLOW_EXPERIMENT_ID", "")
+	cases := []struct {
+		name, id, expected string
+	}{
+		{"", "", "0"},
+		{"", "3", "3"},
+		{"nlp", "3", "7"},
+		{"vision", "", "8"},
+	}
+	for _, c := range cases {
+		t.Setenv("MLFLOW_EXPERIMENT_NAME", c.name)
+		t.Setenv("MLFLOW_EXPERIMENT_ID", c.id)
+		experimentId, err := client.DefaultExperimentId(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if experimentId != c.expected {
+			t.Errorf("%q %q: got %s, want %s", c.name, c.id, experimentId, c.expected)
+		}
+	}
+	if created != "8" {
+		t.Error("expected the missing experiment to be created")
+	}
+}
@@ -0,0 +1,139 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Error codes returned by the tracking server, from databricks.proto.
+const (
+	ErrorCodeInternalError          = "INTERNAL_ERROR"
+	ErrorCodeTemporarilyUnavailable = "TEMPORARILY_UNAVAILABLE"
+	ErrorCodeBadRequest             = "BAD_REQUEST"
+	ErrorCodeInvalidParameterValue  = "INVALID_PARAMETER_VALUE"
+	ErrorCodeInvalidState           = "INVALID_STATE"
+	ErrorCodeResourceDoesNotExist   = "RESOURCE_DOES_NOT_EXIST"
+	ErrorCodeResourceAlreadyExists  = "RESOURCE_ALREADY_EXISTS"
+	ErrorCodePermissionDenied       = "PERMISSION_DENIED"
+	ErrorCodeUnauthenticated        = "UNAUTHENTICATED"
+	ErrorCodeRequestLimitExceeded   = "REQUEST_LIMIT_EXCEEDED"
+	ErrorCodeNotImplemented         = "NOT_IMPLEMENTED"
+)
+
+// Error is returned for every response with a status other than 200. The
+// error code and message are taken from the response body when the server
+// sent them.
+type Error struct {
+	StatusCode int    `json:"-"`
+	ErrorCode  string `json:"error_code"`
+	Message    string `json:"message"`
+	Method     string `json:"-"`
+	Path       string `json:"-"`
+}
+
+func (e *Error) Error() string {
+	parts := []string{"mlflow"}
+	if e.Method != "" || e.Path != "" {
+		parts = append(parts, fmt.Sprintf("%s %s returned status %d", e.Method, e.Path, e.StatusCode))
+	} else if e.ErrorCode == "" && e.Message == "" {
+		parts = append(parts, fmt.Sprintf("status %d", e.StatusCode))
+	}
+	if e.ErrorCode != "" {
+		parts = append(parts, e.ErrorCode)
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// maxErrorBody limits how much of an error response is read.
+const maxErrorBody = 64 << 10
+
+// newError builds the Error for resp from body, the start of its content.
+func newError(resp *http.Response, body []byte) *Error {
+	e := &Error{}
+	if json.Unmarshal(body, e) != nil {
+		e = &Error{}
+	}
+	e.StatusCode = resp.StatusCode
+	e.Method = resp.Request.Method
+	e.Path = resp.Request.URL.Path
+	return e
+}
+
+// readError reads the body of a failed response and returns its Error.
+func readError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return newError(resp, body)
+}
+
+func hasError(err error, statusCode int, errorCode string) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	if e.ErrorCode != "" {
+		return e.ErrorCode == errorCode
+	}
+	return e.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err means that the requested resource does not
+// exist.
+func IsNotFound(err error) bool {
+	return hasError(err, http.StatusNotFound, ErrorCodeResourceDoesNotExist)
+}
+
+// IsAlreadyExists reports whether err means that the resource being created
+// already exists.
+func IsAlreadyExists(err error) bool {
+	return hasError(err, http.StatusConflict, ErrorCodeResourceAlreadyExists)
+}
+
+// IsPermissionDenied reports whether err means that the caller may not
+// access the resource.
+func IsPermissionDenied(err error) bool {
+	return hasError(err, http.StatusForbidden, ErrorCodePermissionDenied)
+}
+
+// IsUnauthenticated reports whether err means that the request carried no
+// valid credentials.
+func IsUnauthenticated(err error) bool {
+	return hasError(err, http.StatusUnauthorized, ErrorCodeUnauthenticated)
+}
+
+// IsInvalidParameter reports whether err means that the request was
+// rejected as malformed.
+func IsInvalidParameter(err error) bool {
+	return hasError(err, http.StatusBadRequest, ErrorCodeInvalidParameterValue)
+}
+
+// IsNotImplemented reports whether err means that the server does not
+// support the endpoint.
+func IsNotImplemented(err error) bool {
+	return hasError(err, http.StatusNotImplemented, ErrorCodeNotImplemented)
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed
+// when the request is sent again: a network error, a 429 or a 5xx response
+// other than 501.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return true
+	}
+	switch e.ErrorCode {
+	case ErrorCodeTemporarilyUnavailable, ErrorCodeRequestLimitExceeded:
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500 && e.StatusCode != http.StatusNotImplemented
+}
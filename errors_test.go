@@ -0,0 +1,75 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/get":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "Run 'missing' not found"}`))
+		case "/api/2.0/mlflow/experiments/create":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error_code": "RESOURCE_ALREADY_EXISTS", "message": "Experiment 'exp' already exists."}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`<html>bad gateway</html>`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	_, err := client.GetRun(ctx, "missing")
+	var e *Error
+	if !errors.As(err, &e) || e.StatusCode != http.StatusNotFound || e.ErrorCode != ErrorCodeResourceDoesNotExist {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err.Error() != "mlflow: GET /api/2.0/mlflow/runs/get returned status 404: RESOURCE_DOES_NOT_EXIST: Run 'missing' not found" {
+		t.Errorf("unexpected message %s", err)
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) || IsAlreadyExists(err) {
+		t.Errorf("unexpected classification of %v", err)
+	}
+
+	_, err = client.CreateExperiment(ctx, "exp")
+	if !IsAlreadyExists(err) || IsNotFound(err) {
+		t.Errorf("unexpected classification of %v", err)
+	}
+
+	err = client.SetTag(ctx, "run1", "key", "value")
+	if !errors.As(err, &e) || e.StatusCode != http.StatusBadGateway || e.ErrorCode != "" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if IsNotFound(err) || IsNotFound(errors.New("mlflow: not an api error")) {
+		t.Errorf("unexpected classification of %v", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.New("connection refused"), true},
+		{context.Canceled, false},
+		{&Error{StatusCode: http.StatusTooManyRequests}, true},
+		{&Error{StatusCode: http.StatusInternalServerError, ErrorCode: ErrorCodeInternalError}, true},
+		{&Error{StatusCode: http.StatusNotImplemented, ErrorCode: ErrorCodeNotImplemented}, false},
+		{&Error{StatusCode: http.StatusBadRequest, ErrorCode: ErrorCodeInvalidParameterValue}, false},
+		{&Error{StatusCode: http.StatusBadRequest, ErrorCode: ErrorCodeRequestLimitExceeded}, true},
+	}
+	for _, test := range tests {
+		if IsRetryable(test.err) != test.retryable {
+			t.Errorf("IsRetryable(%v) = %v", test.err, !test.retryable)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// exportFileVersion is the version of the mlflow-export-import file format
+// ExportExperiment writes.
+const exportFileVersion = "2"
+
+type exportSystem struct {
+	PackageVersion    string            `json:"package_version"`
+	Script            string            `json:"script"`
+	ExportFileVersion string            `json:"export_file_version"`
+	ExportTime        int64             `json:"export_time"`
+	FormattedTime     string            `json:"_export_time"`
+	TrackingUri       string            `json:"mlflow_tracking_uri"`
+	Platform          map[string]string `json:"platform"`
+}
+
+type exportedExperimentFile struct {
+	System exportSystem `json:"system"`
+	Info   struct {
+		NumTotalRuns  int      `json:"num_total_runs"`
+		NumOkRuns     int      `json:"num_ok_runs"`
+		NumFailedRuns int      `json:"num_failed_runs"`
+		FailedRuns    []string `json:"failed_runs"`
+	} `json:"info"`
+	Mlflow struct {
+		Experiment struct {
+			ExperimentId     string            `json:"experiment_id"`
+			Name             string            `json:"name"`
+			ArtifactLocation string            `json:"artifact_location"`
+			LifecycleStage   string            `json:"lifecycle_stage"`
+			CreationTime     int64             `json:"creation_time,omitempty"`
+			LastUpdateTime   int64             `json:"last_update_time,omitempty"`
+			Tags             map[string]string `json:"tags"`
+		} `json:"experiment"`
+		Runs []string `json:"runs"`
+	} `json:"mlflow"`
+}
+
+type exportedMetricPoint struct {
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+	Step      int64   `json:"step"`
+}
+
+type exportedRunFile struct {
+	System exportSystem `json:"system"`
+	Mlflow struct {
+		Info    RunInfo                          `json:"info"`
+		Params  map[string]string                `json:"params"`
+		Metrics map[string][]exportedMetricPoint `json:"metrics"`
+		Tags    map[string]string                `json:"tags"`
+	} `json:"mlflow"`
+}
+
+func (p *Client) exportSystem(script string) exportSystem {
+	now := time.Now()
+	return exportSystem{
+		PackageVersion:    "go-mlflow",
+		Script:            script,
+		ExportFileVersion: exportFileVersion,
+		ExportTime:        now.Unix(),
+		FormattedTime:     now.UTC().Format("2006-01-02 15:04:05"),
+		TrackingUri:       p.BaseUrl,
+		Platform:          map[string]string{"system": runtime.GOOS, "go_version": runtime.Version()},
+	}
+}
+
+func writeJsonFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+func readJsonFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// ExportExperiment writes the experiment experimentId and its active runs
+// to dir in the directory format of mlflow-export-import, so that it can be
+// imported by ImportExperiment or by mlflow-export-import's
+// import-experiment: experiment.json, and for each run a directory named by
+// its id holding run.json, with the full metric histories, and its
+// artifacts under artifacts.
+func (p *Client) ExportExperiment(ctx context.Context, experimentId string, dir string) error {
+	experiment, err := p.GetExperiment(ctx, experimentId)
+	if err != nil {
+		return err
+	}
+	var file exportedExperimentFile
+	file.System = p.exportSystem("export_experiment")
+	e := &file.Mlflow.Experiment
+	e.ExperimentId, e.Name, e.ArtifactLocation, e.LifecycleStage = experiment.ExperimentId, experiment.Name, experiment.ArtifactLocation, experiment.LifecycleStage
+	e.CreationTime, e.LastUpdateTime = experiment.CreationTime, experiment.LastUpdateTime
+	e.Tags = map[string]string{}
+	for _, tag := range experiment.Tags {
+		e.Tags[tag.Key] = tag.Value
+	}
+	file.Mlflow.Runs = []string{}
+	file.Info.FailedRuns = []string{}
+
+	it := p.IterRuns(ctx, []string{experimentId}, "", ActiveOnly, nil)
+	for it.Next() {
+		run := it.Value()
+		if err := checkRunDir(run.Info.RunId); err != nil {
+			return err
+		}
+		if err := p.exportRun(ctx, run, filepath.Join(dir, run.Info.RunId)); err != nil {
+			return fmt.Errorf("mlflow: exporting run %s: %w", run.Info.RunId, err)
+		}
+		file.Mlflow.Runs = append(file.Mlflow.Runs, run.Info.RunId)
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	file.Info.NumTotalRuns = len(file.Mlflow.Runs)
+	file.Info.NumOkRuns = len(file.Mlflow.Runs)
+	return writeJsonFile(filepath.Join(dir, "experiment.json"), file)
+}
+
+func (p *Client) exportRun(ctx context.Context, run Run, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var file exportedRunFile
+	file.System = p.exportSystem("export_run")
+	file.Mlflow.Info = run.Info
+	file.Mlflow.Params = map[string]string{}
+	for _, param := range run.Data.Params {
+		file.Mlflow.Params[param.Key] = param.Value
+	}
+	file.Mlflow.Tags = map[string]string{}
+	for _, tag := range run.Data.Tags {
+		file.Mlflow.Tags[tag.Key] = tag.Value
+	}
+	file.Mlflow.Metrics = map[string][]exportedMetricPoint{}
+	for _, metric := range run.Data.Metrics {
+		history, err := p.IterMetricHistory(ctx, run.Info.RunId, metric.Key).All()
+		if err != nil {
+			return err
+		}
+		points := []exportedMetricPoint{}
+		for _, m := range history {
+			points = append(points, exportedMetricPoint{Value: m.Value, Timestamp: m.Timestamp, Step: m.Step})
+		}
+		file.Mlflow.Metrics[metric.Key] = points
+	}
+	if err := p.DownloadArtifacts(ctx, run.Info.RunId, "", filepath.Join(dir, "artifacts")); err != nil {
+		return err
+	}
+	return writeJsonFile(filepath.Join(dir, "run.json"), file)
+}
+
+// ImportExperiment imports an experiment exported to dir by
+// ExportExperiment or mlflow-export-import into the experiment called
+// targetExperimentName, creating it if needed, and returns its id. Runs get
+// new ids but keep their name, times, status, params, metric histories,
+// tags and artifacts; parent runs are looked up among the imported runs.
+func (p *Client) ImportExperiment(ctx context.Context, dir string, targetExperimentName string) (string, error) {
+	var file exportedExperimentFile
+	if err := readJsonFile(filepath.Join(dir, "experiment.json"), &file); err != nil {
+		return "", err
+	}
+	for _, oldRunId := range file.Mlflow.Runs {
+		if err := checkRunDir(oldRunId); err != nil {
+			return "", err
+		}
+	}
+	experiment, err := p.GetOrCreateExperiment(ctx, targetExperimentName)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range sortedKeys(file.Mlflow.Experiment.Tags) {
+		if err := p.SetExperimentTag(ctx, experiment.ExperimentId, key, file.Mlflow.Experiment.Tags[key]); err != nil {
+			return "", err
+		}
+	}
+
+	runIds := map[string]string{}
+	parents := map[string]string{}
+	for _, oldRunId := range file.Mlflow.Runs {
+		runId, parentRunId, err := p.importRun(ctx, experiment.ExperimentId, filepath.Join(dir, oldRunId))
+		if err != nil {
+			return "", fmt.Errorf("mlflow: importing run %s: %w", oldRunId, err)
+		}
+		runIds[oldRunId] = runId
+		if parentRunId != "" {
+			parents[runId] = parentRunId
+		}
+	}
+	for _, runId := range sortedKeys(parents) {
+		parentRunId, ok := runIds[parents[runId]]
+		if !ok {
+			continue
+		}
+		if err := p.SetTag(ctx, runId, TagParentRunId, parentRunId); err != nil {
+			return "", err
+		}
+	}
+	return experiment.ExperimentId, nil
+}
+
+// checkRunDir fails when the run id runId does not name a directory right
+// under the export directory, as an id read from experiment.json may not.
+func checkRunDir(runId string) error {
+	if runId == "" || runId == "." || runId == ".." || strings.ContainsAny(runId, `/\`) {
+		return fmt.Errorf("mlflow: run id %q is not a directory name", runId)
+	}
+	return nil
+}
+
+// importRun imports the run exported to dir and returns its new id and the
+// exported id of its parent run.
+func (p *Client) importRun(ctx context.Context, experimentId string, dir string) (string, string, error) {
+	var file exportedRunFile
+	if err := readJsonFile(filepath.Join(dir, "run.json"), &file); err != nil {
+		return "", "", err
+	}
+	info := file.Mlflow.Info
+	tags := map[string]string{}
+	for key, value := range file.Mlflow.Tags {
+		if key != TagRunName && key != TagParentRunId {
+			tags[key] = value
+		}
+	}
+	run, err := p.CreateRun(ctx, experimentId, WithRunName(info.RunName), WithStartTime(FromMillis(info.StartTime)), WithTags(tags))
+	if err != nil {
+		return "", "", err
+	}
+	runId := run.Info.RunId
+
+	var metrics []Metric
+	for _, key := range sortedKeys(file.Mlflow.Metrics) {
+		for _, point := range file.Mlflow.Metrics[key] {
+			metrics = append(metrics, Metric{Key: key, Value: point.Value, Timestamp: point.Timestamp, Step: point.Step})
+		}
+	}
+	var params []Param
+	for _, key := range sortedKeys(file.Mlflow.Params) {
+		params = append(params, Param{Key: key, Value: file.Mlflow.Params[key]})
+	}
+	if len(metrics) > 0 || len(params) > 0 {
+		if err := p.LogBatch(ctx, runId, metrics, params, nil); err != nil {
+			return "", "", err
+		}
+	}
+	artifacts := filepath.Join(dir, "artifacts")
+	if _, err := os.Stat(artifacts); err == nil {
+		if err := p.LogArtifacts(ctx, runId, artifacts, ""); err != nil {
+			return "", "", err
+		}
+	}
+	if info.Status != "" && RunStatus(info.Status) != Running {
+		if info.EndTime != 0 {
+			_, err = p.UpdateRunWithEndTime(ctx, runId, RunStatus(info.Status), info.EndTime)
+		} else {
+			_, err = p.UpdateRun(ctx, runId, RunStatus(info.Status))
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return runId, file.Mlflow.Tags[TagParentRunId], nil
+}
@@ -0,0 +1,139 @@
+package mlflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestExportImportExperiment(t *testing.T) {
+	source := mlflowtest.NewServer()
+	defer source.Close()
+	target := mlflowtest.NewServer()
+	defer target.Close()
+	ctx := context.Background()
+
+	client := mlflow.New(source.URL)
+	experiment, err := client.GetOrCreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetExperimentTag(ctx, experiment.ExperimentId, "team", "ml"); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := client.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("parent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := client.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("child"), mlflow.WithStartTime(time.UnixMilli(1000)), mlflow.WithParentRun(parent.Info.RunId), mlflow.WithTags(map[string]string{"model": "cnn"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	childId := child.Info.RunId
+	if err := client.LogParam(ctx, childId, "lr", "0.1"); err != nil {
+		t.Fatal(err)
+	}
+	for step, loss := range []float64{0.5, 0.25} {
+		if err := client.LogMetric(ctx, childId, "loss", loss, 2000, int64(step)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.LogText(ctx, childId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateRunWithEndTime(ctx, childId, mlflow.Finished, 3000); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := client.ExportExperiment(ctx, experiment.ExperimentId, dir); err != nil {
+		t.Fatal(err)
+	}
+	var runFile struct {
+		Mlflow struct {
+			Metrics map[string][]struct {
+				Value float64 `json:"value"`
+			} `json:"metrics"`
+		} `json:"mlflow"`
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, childId, "run.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &runFile); err != nil || len(runFile.Mlflow.Metrics["loss"]) != 2 {
+		t.Errorf("unexpected run.json %s, %v", b, err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, childId, "artifacts", "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected exported artifact %q, %v", b, err)
+	}
+
+	client = mlflow.New(target.URL)
+	experimentId, err := client.ImportExperiment(ctx, dir, "imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := client.GetExperiment(ctx, experimentId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Name != "imported" || len(imported.Tags) != 1 || imported.Tags[0].Value != "ml" {
+		t.Errorf("unexpected experiment %+v", imported)
+	}
+	runs, err := client.IterRuns(ctx, []string{experimentId}, "attributes.run_name = 'child'", mlflow.ActiveOnly, nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected the child run, got %+v", runs)
+	}
+	run := runs[0]
+	if run.Info.Status != string(mlflow.Finished) || run.Info.StartTime != 1000 || run.Info.EndTime != 3000 {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if lr, _ := run.Param("lr"); lr != "0.1" {
+		t.Errorf("unexpected param %q", lr)
+	}
+	if model, _ := run.Tag("model"); model != "cnn" {
+		t.Errorf("unexpected tag %q", model)
+	}
+	parentId, _ := run.Tag(mlflow.TagParentRunId)
+	if parentRun, err := client.GetRun(ctx, parentId); err != nil || parentRun.Info.RunName != "parent" || parentRun.Info.ExperimentId != experimentId {
+		t.Errorf("expected the imported parent run, got %+v, %v", parentRun, err)
+	}
+	history, err := client.IterMetricHistory(ctx, run.Info.RunId, "loss").All()
+	if err != nil || len(history) != 2 {
+		t.Errorf("unexpected history %+v, %v", history, err)
+	}
+	if data, ok := target.Artifact(run.Info.RunId, "notes/hello.txt"); !ok || string(data) != "hello" {
+		t.Errorf("unexpected imported artifact %q", data)
+	}
+}
+
+func TestImportExperimentBadRunId(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	for _, runId := range []string{"../escape", "a/b", `a\b`, "..", ""} {
+		dir := t.TempDir()
+		experiment, err := json.Marshal(map[string]interface{}{"mlflow": map[string]interface{}{"experiment": map[string]interface{}{"name": "exp"}, "runs": []string{runId}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "experiment.json"), experiment, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mlflow.New(server.URL).ImportExperiment(ctx, dir, "imported"); err == nil {
+			t.Errorf("expected an error importing run %q", runId)
+		}
+	}
+	if _, err := mlflow.New(server.URL).GetExperimentsByName(ctx, "imported"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected no experiment to be created, got %v", err)
+	}
+}
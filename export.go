@@ -0,0 +1,269 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ExportFormat is the file format of ExportRuns.
+type ExportFormat string
+
+const (
+	ExportCSV ExportFormat = "csv"
+	// ExportJSONLines writes one JSON object per line.
+	ExportJSONLines ExportFormat = "jsonl"
+)
+
+// ExportOptions configures ExportRuns.
+type ExportOptions struct {
+	// Format defaults to ExportCSV.
+	Format ExportFormat
+	// History, when set, receives the full history of every metric of the
+	// exported runs, in Format, one point per row: run_id, key, value,
+	// timestamp and step.
+	History io.Writer
+}
+
+// exportTimeLayout formats timestamps in UTC, as spreadsheets parse them.
+const exportTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func exportTime(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return FromMillis(millis).UTC().Format(exportTimeLayout)
+}
+
+var exportRunColumns = []string{"run_id", "run_name", "experiment_id", "user_id", "status", "start_time", "end_time", "artifact_uri", "lifecycle_stage"}
+
+// exportedRun is an exported run, as written in JSON lines.
+type exportedRun struct {
+	RunId          string                 `json:"run_id"`
+	RunName        string                 `json:"run_name"`
+	ExperimentId   string                 `json:"experiment_id"`
+	UserId         string                 `json:"user_id"`
+	Status         string                 `json:"status"`
+	StartTime      string                 `json:"start_time"`
+	EndTime        string                 `json:"end_time"`
+	ArtifactUri    string                 `json:"artifact_uri"`
+	LifecycleStage string                 `json:"lifecycle_stage"`
+	Params         map[string]string      `json:"params"`
+	Metrics        map[string]exportFloat `json:"metrics"`
+	Tags           map[string]string      `json:"tags"`
+}
+
+// exportFloat is a metric value, written as "NaN", "Infinity" or "-Infinity"
+// in JSON when it is not finite, as the tracking server does.
+type exportFloat float64
+
+func (f exportFloat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(floatValue(float64(f)))
+}
+
+type exportedMetric struct {
+	RunId     string      `json:"run_id"`
+	Key       string      `json:"key"`
+	Value     exportFloat `json:"value"`
+	Timestamp string      `json:"timestamp"`
+	Step      int64       `json:"step"`
+}
+
+func newExportedRun(run Run) exportedRun {
+	info := run.Info
+	e := exportedRun{
+		RunId:          info.RunId,
+		RunName:        info.RunName,
+		ExperimentId:   info.ExperimentId,
+		UserId:         info.UserId,
+		Status:         info.Status,
+		StartTime:      exportTime(info.StartTime),
+		EndTime:        exportTime(info.EndTime),
+		ArtifactUri:    info.ArtifactUri,
+		LifecycleStage: info.LifecycleStage,
+		Params:         map[string]string{},
+		Metrics:        map[string]exportFloat{},
+		Tags:           map[string]string{},
+	}
+	for _, param := range run.Data.Params {
+		e.Params[param.Key] = param.Value
+	}
+	for _, metric := range run.Data.Metrics {
+		e.Metrics[metric.Key] = exportFloat(metric.Value)
+	}
+	for _, tag := range run.Data.Tags {
+		e.Tags[tag.Key] = tag.Value
+	}
+	return e
+}
+
+func (e exportedRun) columns() []string {
+	return []string{e.RunId, e.RunName, e.ExperimentId, e.UserId, e.Status, e.StartTime, e.EndTime, e.ArtifactUri, e.LifecycleStage}
+}
+
+// formatFloat formats a metric value for CSV, with the names JSON uses for
+// the values that are not finite.
+func formatFloat(value float64) string {
+	if s, ok := floatValue(value).(string); ok {
+		return s
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// ExportRuns writes the runs of runs, such as IterRuns returns, to w in the
+// format of opts and returns the number of exported runs. JSON lines hold
+// one object per run with params, metrics and tags as objects, and are
+// streamed. CSV has a row per run with a column per param, metric and tag,
+// named params.<key>, metrics.<key> and tags.<key>, and a header naming
+// every column, so runs are buffered until the last one is fetched.
+// Metrics are their latest value.
+func (p *Client) ExportRuns(ctx context.Context, w io.Writer, runs *Iterator[Run], opts ExportOptions) (int, error) {
+	if opts.Format == "" {
+		opts.Format = ExportCSV
+	}
+	if opts.Format != ExportCSV && opts.Format != ExportJSONLines {
+		return 0, fmt.Errorf("mlflow: unknown export format %q", opts.Format)
+	}
+	var history *exportWriter
+	if opts.History != nil {
+		history = newExportWriter(opts.History, opts.Format)
+		if err := history.header([]string{"run_id", "key", "value", "timestamp", "step"}); err != nil {
+			return 0, err
+		}
+	}
+	out := newExportWriter(w, opts.Format)
+	var buffered []exportedRun
+	n := 0
+	for runs.Next() {
+		e := newExportedRun(runs.Value())
+		if opts.Format == ExportCSV {
+			buffered = append(buffered, e)
+		} else if err := out.json(e); err != nil {
+			return n, err
+		}
+		n++
+		if history != nil {
+			for _, key := range sortedKeys(e.Metrics) {
+				if err := p.exportHistory(ctx, history, e.RunId, key); err != nil {
+					return n, err
+				}
+			}
+		}
+	}
+	if err := runs.Err(); err != nil {
+		return n, err
+	}
+	if history != nil {
+		if err := history.flush(); err != nil {
+			return n, err
+		}
+	}
+	if opts.Format == ExportCSV {
+		if err := writeRunsCsv(out, buffered); err != nil {
+			return n, err
+		}
+	}
+	return n, out.flush()
+}
+
+func (p *Client) exportHistory(ctx context.Context, w *exportWriter, runId string, key string) error {
+	it := p.IterMetricHistory(ctx, runId, key)
+	for it.Next() {
+		metric := it.Value()
+		e := exportedMetric{RunId: runId, Key: key, Value: exportFloat(metric.Value), Timestamp: exportTime(metric.Timestamp), Step: metric.Step}
+		var err error
+		if w.csv != nil {
+			err = w.csv.Write([]string{e.RunId, e.Key, formatFloat(float64(e.Value)), e.Timestamp, strconv.FormatInt(e.Step, 10)})
+		} else {
+			err = w.json(e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func writeRunsCsv(w *exportWriter, runs []exportedRun) error {
+	params, metrics, tags := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, e := range runs {
+		for key := range e.Params {
+			params[key] = true
+		}
+		for key := range e.Metrics {
+			metrics[key] = true
+		}
+		for key := range e.Tags {
+			tags[key] = true
+		}
+	}
+	header := append([]string{}, exportRunColumns...)
+	for _, key := range sortedKeys(params) {
+		header = append(header, "params."+key)
+	}
+	for _, key := range sortedKeys(metrics) {
+		header = append(header, "metrics."+key)
+	}
+	for _, key := range sortedKeys(tags) {
+		header = append(header, "tags."+key)
+	}
+	if err := w.header(header); err != nil {
+		return err
+	}
+	for _, e := range runs {
+		row := e.columns()
+		for _, key := range sortedKeys(params) {
+			row = append(row, e.Params[key])
+		}
+		for _, key := range sortedKeys(metrics) {
+			value := ""
+			if v, ok := e.Metrics[key]; ok {
+				value = formatFloat(float64(v))
+			}
+			row = append(row, value)
+		}
+		for _, key := range sortedKeys(tags) {
+			row = append(row, e.Tags[key])
+		}
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportWriter writes rows either as CSV or as JSON lines.
+type exportWriter struct {
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+func newExportWriter(w io.Writer, format ExportFormat) *exportWriter {
+	if format == ExportCSV {
+		return &exportWriter{csv: csv.NewWriter(w)}
+	}
+	return &exportWriter{enc: json.NewEncoder(w)}
+}
+
+// header writes the header of CSV, which JSON lines don't have.
+func (w *exportWriter) header(columns []string) error {
+	if w.csv == nil {
+		return nil
+	}
+	return w.csv.Write(columns)
+}
+
+func (w *exportWriter) json(v interface{}) error {
+	return w.enc.Encode(v)
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,124 @@
+package mlflow_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestExportRuns(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	var runIds []string
+	for i, name := range []string{"a", "b"} {
+		run, err := client.CreateRun(ctx, "0", mlflow.WithRunName(name), mlflow.WithStartTime(time.UnixMilli(1000)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, run.Info.RunId)
+		if err := client.LogParam(ctx, run.Info.RunId, "lr", "0."+name); err != nil {
+			t.Fatal(err)
+		}
+		for step := 0; step <= i; step++ {
+			if err := client.LogMetric(ctx, run.Info.RunId, "loss", float64(step)+0.5, 2000, int64(step)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := client.SetTag(ctx, runIds[1], "team", "ml,ops"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, history bytes.Buffer
+	n, err := client.ExportRuns(ctx, &out, client.IterRuns(ctx, []string{"0"}, "", mlflow.ActiveOnly, []string{"attributes.run_name"}), mlflow.ExportOptions{History: &history})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 runs, got %d", n)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], ",lifecycle_stage,params.lr,metrics.loss,tags.mlflow.runName,tags.team") {
+		t.Fatalf("unexpected csv\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[2], runIds[1]+",b,0,") || !strings.Contains(lines[2], ",1970-01-01T00:00:01.000Z,,") || !strings.HasSuffix(lines[2], `,0.b,1.5,b,"ml,ops"`) {
+		t.Errorf("unexpected row %s", lines[2])
+	}
+	expected := "run_id,key,value,timestamp,step\n" +
+		runIds[0] + ",loss,0.5,1970-01-01T00:00:02.000Z,0\n" +
+		runIds[1] + ",loss,0.5,1970-01-01T00:00:02.000Z,0\n" +
+		runIds[1] + ",loss,1.5,1970-01-01T00:00:02.000Z,1\n"
+	if history.String() != expected {
+		t.Errorf("unexpected history\n%s", history.String())
+	}
+
+	out.Reset()
+	history.Reset()
+	_, err = client.ExportRuns(ctx, &out, client.IterRuns(ctx, []string{"0"}, "params.lr = '0.b'", mlflow.ActiveOnly, nil), mlflow.ExportOptions{Format: mlflow.ExportJSONLines, History: &history})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var run struct {
+		RunId   string             `json:"run_id"`
+		Params  map[string]string  `json:"params"`
+		Metrics map[string]float64 `json:"metrics"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.RunId != runIds[1] || run.Params["lr"] != "0.b" || run.Metrics["loss"] != 1.5 {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if n := strings.Count(history.String(), "\n"); n != 2 {
+		t.Errorf("expected 2 history points, got\n%s", history.String())
+	}
+
+	if _, err := client.ExportRuns(ctx, &out, client.IterRuns(ctx, []string{"0"}, "", mlflow.ActiveOnly, nil), mlflow.ExportOptions{Format: "xlsx"}); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func TestExportRunsNonFinite(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	run, err := client.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]float64{"nan": math.NaN(), "inf": math.Inf(1), "ninf": math.Inf(-1)} {
+		if err := client.LogMetric(ctx, run.Info.RunId, key, value, 1000, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out, history bytes.Buffer
+	if _, err := client.ExportRuns(ctx, &out, client.IterRuns(ctx, []string{"0"}, "", mlflow.ActiveOnly, nil), mlflow.ExportOptions{Format: mlflow.ExportJSONLines, History: &history}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"metrics":{"inf":"Infinity","nan":"NaN","ninf":"-Infinity"}`) {
+		t.Errorf("unexpected run %s", out.String())
+	}
+	if !strings.Contains(history.String(), `"key":"nan","value":"NaN"`) || !strings.Contains(history.String(), `"key":"ninf","value":"-Infinity"`) {
+		t.Errorf("unexpected history %s", history.String())
+	}
+
+	out.Reset()
+	if _, err := client.ExportRuns(ctx, &out, client.IterRuns(ctx, []string{"0"}, "", mlflow.ActiveOnly, nil), mlflow.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out.String()), ",Infinity,NaN,-Infinity") {
+		t.Errorf("unexpected csv\n%s", out.String())
+	}
+}
@@ -0,0 +1,207 @@
+package mlflow
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterBuilder builds the filter strings of SearchRuns, SearchExperiments,
+// SearchRegisteredModels and SearchModelVersions, quoting keys and values
+// as the server's parser expects:
+//
+//	filter, err := mlflow.Filter().Metric("rmse").Lt(0.5).And().Tag("team").Eq("nlp").Build()
+//
+// MLflow only supports the conjunction of clauses, so And merely reads
+// well; clauses are joined with AND whether it is called or not.
+type FilterBuilder struct {
+	clauses []string
+	err     error
+}
+
+// FilterTerm is the left-hand side of a clause, waiting for its comparison.
+type FilterTerm struct {
+	builder *FilterBuilder
+	key     string
+	numeric bool
+}
+
+func Filter() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+func (f *FilterBuilder) Metric(key string) *FilterTerm {
+	return f.term("metrics", key, true)
+}
+
+func (f *FilterBuilder) Param(key string) *FilterTerm {
+	return f.term("params", key, false)
+}
+
+func (f *FilterBuilder) Tag(key string) *FilterTerm {
+	return f.term("tags", key, false)
+}
+
+// Attribute filters runs on a run attribute such as "status", "run_name"
+// or "start_time".
+func (f *FilterBuilder) Attribute(name string) *FilterTerm {
+	return f.term("attributes", name, false)
+}
+
+// Field filters on a top-level field, such as the "name" of an experiment
+// or registered model or the "run_id" of a model version.
+func (f *FilterBuilder) Field(name string) *FilterTerm {
+	return f.term("", name, false)
+}
+
+func (f *FilterBuilder) term(entity string, key string, numeric bool) *FilterTerm {
+	key = quoteFilterKey(key)
+	if entity != "" {
+		key = entity + "." + key
+	}
+	return &FilterTerm{builder: f, key: key, numeric: numeric}
+}
+
+func (f *FilterBuilder) And() *FilterBuilder {
+	return f
+}
+
+// Build returns the filter, or the first error met while building it.
+func (f *FilterBuilder) Build() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.String(), nil
+}
+
+func (f *FilterBuilder) String() string {
+	return strings.Join(f.clauses, " AND ")
+}
+
+func (t *FilterTerm) Eq(value interface{}) *FilterBuilder {
+	return t.compare("=", value)
+}
+
+func (t *FilterTerm) Ne(value interface{}) *FilterBuilder {
+	return t.compare("!=", value)
+}
+
+func (t *FilterTerm) Lt(value interface{}) *FilterBuilder {
+	return t.compare("<", value)
+}
+
+func (t *FilterTerm) Le(value interface{}) *FilterBuilder {
+	return t.compare("<=", value)
+}
+
+func (t *FilterTerm) Gt(value interface{}) *FilterBuilder {
+	return t.compare(">", value)
+}
+
+func (t *FilterTerm) Ge(value interface{}) *FilterBuilder {
+	return t.compare(">=", value)
+}
+
+// Like matches pattern, where % matches any run of characters and _ any
+// single character.
+func (t *FilterTerm) Like(pattern string) *FilterBuilder {
+	return t.compare("LIKE", pattern)
+}
+
+// ILike is the case-insensitive Like.
+func (t *FilterTerm) ILike(pattern string) *FilterBuilder {
+	return t.compare("ILIKE", pattern)
+}
+
+func (t *FilterTerm) In(values ...string) *FilterBuilder {
+	return t.list("IN", values)
+}
+
+func (t *FilterTerm) NotIn(values ...string) *FilterBuilder {
+	return t.list("NOT IN", values)
+}
+
+func (t *FilterTerm) compare(op string, value interface{}) *FilterBuilder {
+	literal, err := filterLiteral(value, t.numeric)
+	return t.add(t.key+" "+op+" "+literal, err)
+}
+
+func (t *FilterTerm) list(op string, values []string) *FilterBuilder {
+	literals := make([]string, len(values))
+	var err error
+	for i, value := range values {
+		var e error
+		literals[i], e = quoteFilterString(value)
+		if err == nil {
+			err = e
+		}
+	}
+	return t.add(t.key+" "+op+" ("+strings.Join(literals, ", ")+")", err)
+}
+
+func (t *FilterTerm) add(clause string, err error) *FilterBuilder {
+	f := t.builder
+	if err != nil {
+		if f.err == nil {
+			f.err = err
+		}
+		return f
+	}
+	f.clauses = append(f.clauses, clause)
+	return f
+}
+
+var plainFilterKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// quoteFilterKey wraps keys with spaces, dashes and other special
+// characters in backticks, or double quotes when they contain one.
+func quoteFilterKey(key string) string {
+	if plainFilterKey.MatchString(key) {
+		return key
+	}
+	if strings.Contains(key, "`") {
+		return `"` + key + `"`
+	}
+	return "`" + key + "`"
+}
+
+// filterLiteral formats value as a number for numeric terms and numeric
+// values, and as a quoted string otherwise. Times are converted to
+// milliseconds since the epoch.
+func filterLiteral(value interface{}, numeric bool) (string, error) {
+	switch value := value.(type) {
+	case string:
+		if numeric {
+			return "", fmt.Errorf("mlflow: metric filters compare numbers, not %q", value)
+		}
+		return quoteFilterString(value)
+	case RunStatus:
+		return quoteFilterString(string(value))
+	case time.Time:
+		return strconv.FormatInt(Millis(value), 10), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32), nil
+	}
+	return "", fmt.Errorf("mlflow: cannot use %T in a filter", value)
+}
+
+// quoteFilterString quotes s in single quotes, or in double quotes when it
+// contains a single quote. The filter grammar has no escapes, so s can't
+// contain both.
+func quoteFilterString(s string) (string, error) {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'", nil
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`, nil
+	}
+	return "", fmt.Errorf("mlflow: filter value %q contains both quote characters", s)
+}
@@ -0,0 +1,44 @@
+package mlflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		filter   *FilterBuilder
+		expected string
+	}{
+		{Filter().Metric("rmse").Lt(0.5).And().Tag("team").Eq("nlp"), "metrics.rmse < 0.5 AND tags.team = 'nlp'"},
+		{Filter().Metric("f1-score").Ge(0.9), "metrics.`f1-score` >= 0.9"},
+		{Filter().Param("model name").Eq("it's"), "params.`model name` = \"it's\""},
+		{Filter().Tag("mlflow.parentRunId").Eq("abc"), "tags.mlflow.parentRunId = 'abc'"},
+		{Filter().Attribute("status").In("FINISHED", "FAILED"), "attributes.status IN ('FINISHED', 'FAILED')"},
+		{Filter().Attribute("start_time").Gt(time.UnixMilli(1700000000000)), "attributes.start_time > 1700000000000"},
+		{Filter().Attribute("status").Ne(Running), "attributes.status != 'RUNNING'"},
+		{Filter().Field("name").ILike("%bert%"), "name ILIKE '%bert%'"},
+		{Filter().Tag("odd`key").Eq("v"), "tags.\"odd`key\" = 'v'"},
+		{Filter(), ""},
+	}
+	for _, c := range cases {
+		filter, err := c.filter.Build()
+		if err != nil {
+			t.Errorf("%s: %v", c.expected, err)
+		}
+		if filter != c.expected {
+			t.Errorf("got %s, want %s", filter, c.expected)
+		}
+	}
+
+	for _, f := range []*FilterBuilder{
+		Filter().Metric("rmse").Eq("low"),
+		Filter().Tag("quote").Eq(`'"`),
+		Filter().Param("lr").Eq([]string{"0.1"}),
+		Filter().Tag("team").In("nlp", `'"`).And().Tag("a").Eq("b"),
+	} {
+		if _, err := f.Build(); err == nil {
+			t.Errorf("expected an error for %s", f)
+		}
+	}
+}
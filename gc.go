@@ -0,0 +1,24 @@
+package mlflow
+
+import (
+	"context"
+	"time"
+)
+
+// GarbageCollector is implemented by tracking stores that can permanently
+// delete what was deleted, as `mlflow gc` does, such as mlflowfile.Store
+// and mlflowsql.Store. The REST API has no such endpoint, so Client does
+// not implement it.
+type GarbageCollector interface {
+	// GC permanently removes the runs deleted more than olderThan ago and
+	// the experiments deleted more than olderThan ago with all their runs,
+	// records and artifacts. With olderThan 0, everything deleted is
+	// removed.
+	GC(ctx context.Context, olderThan time.Duration) (*GCResult, error)
+}
+
+// GCResult lists what GC removed.
+type GCResult struct {
+	ExperimentIds []string
+	RunIds        []string
+}
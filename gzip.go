@@ -0,0 +1,87 @@
+package mlflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// WithGzip compresses JSON request bodies of at least minSize bytes, such as
+// large LogBatch calls. If the server rejects a compressed body the request
+// is sent again uncompressed, and compression stays off from then on.
+// Compressed responses are always decompressed.
+func WithGzip(minSize int) Option {
+	return func(p *Client) {
+		if minSize < 1 {
+			minSize = 1
+		}
+		p.gzipMinSize = minSize
+	}
+}
+
+func (p *Client) compressRequest(size int) bool {
+	return p.gzipMinSize > 0 && size >= p.gzipMinSize && atomic.LoadInt32(&p.gzipRejected) == 0
+}
+
+func (p *Client) disableGzip() {
+	atomic.StoreInt32(&p.gzipRejected, 1)
+}
+
+// isEncodingRejected reports whether err may mean that the server could not
+// read a compressed body. Servers without gzip support answer 415, or 400
+// when they try to parse the compressed bytes as JSON.
+func isEncodingRejected(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == http.StatusUnsupportedMediaType || e.StatusCode == http.StatusBadRequest
+}
+
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressResponse decodes a gzip response body that net/http left
+// compressed, which happens when the request set its own Accept-Encoding.
+func decompressResponse(resp *http.Response) (*http.Response, error) {
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp, nil
+	}
+	r, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		// An empty body.
+		return resp, nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &gzipBody{Reader: r, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return resp, nil
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
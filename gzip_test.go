@@ -0,0 +1,66 @@
+package mlflow
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithGzip(t *testing.T) {
+	acceptGzip := true
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			if !acceptGzip {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error_code": "MALFORMED_REQUEST", "message": "Malformed request"}`))
+				return
+			}
+			body, _ = gzip.NewReader(r.Body)
+		}
+		var batch struct {
+			Metrics []Metric `json:"metrics"`
+		}
+		if err := json.NewDecoder(body).Decode(&batch); err != nil {
+			t.Errorf("could not read the batch: %v", err)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{}`))
+		gz.Close()
+	}))
+	defer server.Close()
+	metrics := make([]Metric, 100)
+	for i := range metrics {
+		metrics[i] = Metric{Key: "loss", Value: 0.5, Step: int64(i)}
+	}
+	ctx := context.Background()
+
+	// Setting Accept-Encoding keeps net/http from decompressing the response.
+	client := New(server.URL, WithGzip(1024), WithHeader("Accept-Encoding", "gzip"))
+	if err := client.LogBatch(ctx, "run1", metrics, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogBatch(ctx, "run1", metrics[:1], nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if encodings[0] != "gzip" || encodings[1] != "" {
+		t.Errorf("unexpected encodings %v", encodings)
+	}
+
+	acceptGzip, encodings = false, nil
+	client = New(server.URL, WithGzip(1024))
+	for i := 0; i < 2; i++ {
+		if err := client.LogBatch(ctx, "run1", metrics, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(encodings) != 3 || encodings[0] != "gzip" || encodings[1] != "" || encodings[2] != "" {
+		t.Errorf("compression was not turned off: %v", encodings)
+	}
+}
@@ -0,0 +1,241 @@
+// Command protogen generates the Go types of MLflow messages from .proto
+// files, keeping the JSON names of the fields as the REST API uses them.
+//
+//	go run ./internal/protogen -out types_gen.go a.proto b.proto
+//
+// It reads the proto2 subset MLflow's files are written in: messages of
+// scalar, enum and message fields, and enums, which are decoded as strings.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+)
+
+// names overrides the Go names of fields that predate the generator.
+var names = map[string]string{
+	"RunInfo.run_uuid": "RunUUid",
+}
+
+// required lists the scalar fields encoded even when empty. Other scalar
+// and repeated fields are omitempty.
+var required = map[string][]string{
+	"Metric":               {"key", "value", "timestamp", "step"},
+	"Param":                {"key", "value"},
+	"RunTag":               {"key", "value"},
+	"ExperimentTag":        {"key", "value"},
+	"InputTag":             {"key", "value"},
+	"RunInfo":              {"run_id", "run_uuid", "experiment_id", "user_id", "status", "start_time", "artifact_uri", "lifecycle_stage"},
+	"Experiment":           {"experiment_id", "name", "artifact_location", "lifecycle_stage"},
+	"ModelInput":           {"model_id"},
+	"ModelOutput":          {"model_id", "step"},
+	"Dataset":              {"name", "digest", "source_type", "source"},
+	"RegisteredModel":      {"name"},
+	"RegisteredModelTag":   {"key", "value"},
+	"RegisteredModelAlias": {"alias", "version"},
+	"ModelVersion":         {"name", "version"},
+	"ModelVersionTag":      {"key", "value"},
+}
+
+var scalars = map[string]string{
+	"string": "string",
+	"bytes":  "[]byte",
+	"bool":   "bool",
+	"double": "float64",
+	"float":  "float32",
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint32": "uint32",
+	"uint64": "uint64",
+}
+
+type field struct {
+	name     string
+	typ      string
+	repeated bool
+	comment  []string
+}
+
+type message struct {
+	name    string
+	fields  []field
+	comment []string
+}
+
+type file struct {
+	messages []message
+	enums    []string
+}
+
+func main() {
+	out := flag.String("out", "types_gen.go", "the Go file to write")
+	pkg := flag.String("package", "mlflow", "the package of the Go file")
+	flag.Parse()
+	src, err := generate(*pkg, flag.Args())
+	if err == nil {
+		err = os.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "protogen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the Go source of the messages of the proto files paths.
+func generate(pkg string, paths []string) ([]byte, error) {
+	var messages []message
+	enums := map[string]bool{}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parse(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		messages = append(messages, f.messages...)
+		for _, enum := range f.enums {
+			enums[enum] = true
+		}
+	}
+	known := map[string]bool{}
+	for _, m := range messages {
+		known[m.name] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by protogen from MLflow's .proto files. DO NOT EDIT.\n\npackage %s\n", pkg)
+	for _, m := range messages {
+		keep := map[string]bool{}
+		for _, name := range required[m.name] {
+			keep[name] = true
+		}
+		buf.WriteString("\n")
+		writeComment(&buf, m.comment)
+		fmt.Fprintf(&buf, "type %s struct {\n", m.name)
+		for _, f := range m.fields {
+			typ, isMessage := scalars[f.typ], false
+			switch {
+			case typ != "":
+			case enums[f.typ]:
+				typ = "string"
+			case known[f.typ]:
+				typ, isMessage = f.typ, true
+			default:
+				return nil, fmt.Errorf("%s.%s: unknown type %s", m.name, f.name, f.typ)
+			}
+			tag := f.name
+			if f.repeated {
+				typ = "[]" + typ
+				tag += ",omitempty"
+			} else if !isMessage && !keep[f.name] {
+				tag += ",omitempty"
+			}
+			name := names[m.name+"."+f.name]
+			if name == "" {
+				name = goName(f.name)
+			}
+			writeComment(&buf, f.comment)
+			fmt.Fprintf(&buf, "%s %s `json:%q`\n", name, typ, tag)
+		}
+		buf.WriteString("}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+func writeComment(buf *bytes.Buffer, comment []string) {
+	for _, line := range comment {
+		buf.WriteString("//" + line + "\n")
+	}
+}
+
+// goName converts a snake_case proto name to an exported Go name, as in
+// run_id to RunId.
+func goName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// parse reads the top-level messages and enums of a proto file. The comments
+// right before a message or a field are kept as its documentation.
+func parse(src string) (*file, error) {
+	f := &file{}
+	var comment []string
+	var current *message
+	depth := 0
+	for n, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("line %d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+		if strings.HasPrefix(line, "//") {
+			comment = append(comment, strings.TrimPrefix(line, "//"))
+			continue
+		}
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		words := strings.Fields(line)
+		switch {
+		case len(words) == 0:
+			if current == nil {
+				comment = nil
+			}
+			continue
+		case line == "}":
+			depth--
+			if depth < 0 {
+				return nil, fail("unexpected }")
+			}
+			if depth == 0 && current != nil {
+				f.messages = append(f.messages, *current)
+				current = nil
+			}
+		case depth > 1:
+			// Inside a nested block, such as an enum of a message.
+			if strings.HasSuffix(line, "{") {
+				depth++
+			}
+		case strings.HasSuffix(line, "{"):
+			if len(words) != 3 || (words[0] != "message" && words[0] != "enum") {
+				return nil, fail("unsupported declaration %q", line)
+			}
+			if depth == 0 && words[0] == "message" {
+				current = &message{name: words[1], comment: comment}
+			} else if depth == 0 {
+				f.enums = append(f.enums, words[1])
+			} else if words[0] == "message" {
+				return nil, fail("nested message %s is not supported", words[1])
+			} else {
+				f.enums = append(f.enums, words[1])
+			}
+			depth++
+		case depth == 0:
+			if words[0] != "syntax" && words[0] != "package" && words[0] != "import" && words[0] != "option" {
+				return nil, fail("unsupported statement %q", line)
+			}
+		case current == nil:
+			// An enum value.
+		default:
+			if len(words) < 5 || words[3] != "=" || (words[0] != "optional" && words[0] != "required" && words[0] != "repeated") {
+				return nil, fail("unsupported field %q", line)
+			}
+			current.fields = append(current.fields, field{name: words[2], typ: words[1], repeated: words[0] == "repeated", comment: comment})
+		}
+		comment = nil
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated block")
+	}
+	return f, nil
+}
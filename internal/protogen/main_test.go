@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGenerated checks that types_gen.go is up to date with the .proto
+// files. Run go generate in the root package after changing them.
+func TestGenerated(t *testing.T) {
+	src, err := generate("mlflow", []string{"proto/service.proto", "proto/model_registry.proto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := os.ReadFile("../../types_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(checkedIn) {
+		t.Error("types_gen.go is out of date, run go generate")
+	}
+}
+
+func TestParse(t *testing.T) {
+	f, err := parse(`
+syntax = "proto2";
+
+// Ignored, not attached to anything.
+
+// A thing.
+message Thing {
+  // The id.
+  optional string thing_id = 1;
+  repeated Kind kinds = 2 [default = 0];
+  enum Kind {
+    A = 1;
+  }
+}
+
+enum Status {
+  READY = 1;
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.messages) != 1 || len(f.enums) != 2 {
+		t.Fatalf("unexpected file %+v", f)
+	}
+	m := f.messages[0]
+	if m.name != "Thing" || strings.Join(m.comment, "") != " A thing." || len(m.fields) != 2 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.fields[0].name != "thing_id" || m.fields[0].typ != "string" || strings.Join(m.fields[0].comment, "") != " The id." {
+		t.Errorf("unexpected field %+v", m.fields[0])
+	}
+	if !m.fields[1].repeated || m.fields[1].typ != "Kind" {
+		t.Errorf("unexpected field %+v", m.fields[1])
+	}
+	if _, err := parse("message Thing {\n  map<string, string> tags = 1;\n}\n"); err == nil {
+		t.Error("expected an error for a map field")
+	}
+	if goName("run_uuid") != "RunUuid" || goName("artifact_uri") != "ArtifactUri" {
+		t.Error("unexpected Go names")
+	}
+}
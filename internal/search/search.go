@@ -0,0 +1,310 @@
+// Package search evaluates MLflow search filters and pages results for the
+// in-process implementations of mlflow.API.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Clause is a single comparison of a filter, such as metrics.rmse < 0.5.
+type Clause struct {
+	// Entity is "metrics", "params", "tags", "attributes" or "" for a top
+	// level field such as the name of a registered model.
+	Entity string
+	Key    string
+	Op     string
+	// Values holds the compared strings, one unless Op is IN or NOT IN.
+	Values []string
+	// Number is the compared number when IsNumber is set.
+	Number   float64
+	IsNumber bool
+}
+
+// Filter is the conjunction of its clauses. The empty filter matches
+// everything.
+type Filter []Clause
+
+var entities = map[string]string{
+	"metric": "metrics", "metrics": "metrics",
+	"param": "params", "params": "params", "parameter": "params", "parameters": "params",
+	"tag": "tags", "tags": "tags",
+	"attribute": "attributes", "attributes": "attributes", "attr": "attributes", "run": "attributes",
+}
+
+var clausePattern = regexp.MustCompile(`(?is)^\s*((?:[A-Za-z_][A-Za-z0-9_]*\.)?(?:` + "`[^`]*`" + `|"[^"]*"|[A-Za-z_][A-Za-z0-9_.]*))\s*(!=|<=|>=|=|<|>|NOT\s+IN|IN|NOT\s+ILIKE|NOT\s+LIKE|ILIKE|LIKE)\s*(.*?)\s*$`)
+
+// Parse parses an MLflow filter string.
+func Parse(filter string) (Filter, error) {
+	var clauses Filter
+	for _, part := range splitAnd(filter) {
+		m := clausePattern.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid clause %q", part)
+		}
+		clause := Clause{Op: strings.ToUpper(strings.Join(strings.Fields(m[2]), " "))}
+		clause.Entity, clause.Key = splitKey(m[1])
+		value := m[3]
+		switch clause.Op {
+		case "IN", "NOT IN":
+			if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+				return nil, fmt.Errorf("invalid list in %q", part)
+			}
+			for _, item := range splitOutsideQuotes(value[1:len(value)-1], ",") {
+				s, ok := unquote(strings.TrimSpace(item))
+				if !ok {
+					return nil, fmt.Errorf("invalid list item in %q", part)
+				}
+				clause.Values = append(clause.Values, s)
+			}
+		default:
+			if s, ok := unquote(value); ok {
+				clause.Values = []string{s}
+			} else if n, err := strconv.ParseFloat(value, 64); err == nil {
+				clause.Number, clause.IsNumber = n, true
+				clause.Values = []string{value}
+			} else {
+				return nil, fmt.Errorf("invalid value in %q", part)
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses, nil
+}
+
+func splitKey(key string) (string, string) {
+	if dot := strings.Index(key, "."); dot > 0 {
+		if entity, ok := entities[strings.ToLower(key[:dot])]; ok {
+			return entity, unquoteKey(key[dot+1:])
+		}
+	}
+	return "", unquoteKey(key)
+}
+
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '`' && key[len(key)-1] == '`' || key[0] == '"' && key[len(key)-1] == '"') {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+func unquote(value string) (string, bool) {
+	if len(value) >= 2 && (value[0] == '\'' && value[len(value)-1] == '\'' || value[0] == '"' && value[len(value)-1] == '"') {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+var andPattern = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+func splitAnd(filter string) []string {
+	if strings.TrimSpace(filter) == "" {
+		return nil
+	}
+	return splitOutsideQuotes(filter, "")
+}
+
+// splitOutsideQuotes splits s on sep, or on the AND keyword when sep is
+// empty, ignoring separators inside quotes and backticks.
+func splitOutsideQuotes(s string, sep string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+			continue
+		}
+		if sep != "" {
+			if strings.HasPrefix(s[i:], sep) {
+				parts = append(parts, s[start:i])
+				start = i + len(sep)
+			}
+			continue
+		}
+		if loc := andPattern.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+			parts = append(parts, s[start:i])
+			start = i + loc[1]
+			i = start - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// Lookup returns the value of entity.key of the searched item, a string or
+// a float64, and whether it has one.
+type Lookup func(entity string, key string) (interface{}, bool)
+
+// Match reports whether the item described by lookup satisfies f.
+func (f Filter) Match(lookup Lookup) bool {
+	for _, clause := range f {
+		value, ok := lookup(clause.Entity, clause.Key)
+		if !ok || !clause.match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Clause) match(value interface{}) bool {
+	if n, ok := value.(float64); ok {
+		if !c.IsNumber {
+			return false
+		}
+		switch c.Op {
+		case "=":
+			return n == c.Number
+		case "!=":
+			return n != c.Number
+		case "<":
+			return n < c.Number
+		case "<=":
+			return n <= c.Number
+		case ">":
+			return n > c.Number
+		case ">=":
+			return n >= c.Number
+		}
+		return false
+	}
+	s := fmt.Sprint(value)
+	switch c.Op {
+	case "=":
+		return s == c.Values[0]
+	case "!=":
+		return s != c.Values[0]
+	case "<", "<=", ">", ">=":
+		cmp := strings.Compare(s, c.Values[0])
+		return c.Op == "<" && cmp < 0 || c.Op == "<=" && cmp <= 0 || c.Op == ">" && cmp > 0 || c.Op == ">=" && cmp >= 0
+	case "LIKE", "NOT LIKE":
+		return like(s, c.Values[0], false) == (c.Op == "LIKE")
+	case "ILIKE", "NOT ILIKE":
+		return like(s, c.Values[0], true) == (c.Op == "ILIKE")
+	case "IN", "NOT IN":
+		found := false
+		for _, v := range c.Values {
+			found = found || s == v
+		}
+		return found == (c.Op == "IN")
+	}
+	return false
+}
+
+// like matches s against a SQL LIKE pattern.
+func like(s string, pattern string, fold bool) bool {
+	var expr strings.Builder
+	if fold {
+		expr.WriteString("(?is)")
+	} else {
+		expr.WriteString("(?s)")
+	}
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(s)
+}
+
+// Page returns the page of items starting at pageToken, an offset, and the
+// token of the next page. A maxResults of zero or less selects
+// defaultMaxResults.
+func Page[T any](items []T, maxResults int, pageToken string, defaultMaxResults int) ([]T, string, error) {
+	offset := 0
+	if pageToken != "" {
+		n, err := strconv.Atoi(pageToken)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+		}
+		offset = n
+	}
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+	if offset >= len(items) {
+		return nil, "", nil
+	}
+	end := offset + maxResults
+	if end >= len(items) {
+		return items[offset:], "", nil
+	}
+	return items[offset:end], strconv.Itoa(end), nil
+}
+
+// Sort sorts items by MLflow order_by clauses such as "metrics.rmse ASC",
+// using less for items that compare equal. Items without a value for a
+// clause sort last.
+func Sort[T any](items []T, orderBy []string, lookup func(T) Lookup, less func(a, b T) bool) {
+	type key struct {
+		entity, key string
+		desc        bool
+	}
+	var keys []key
+	for _, clause := range orderBy {
+		clause = strings.TrimSpace(clause)
+		var k key
+		if i := strings.LastIndexAny(clause, " \t"); i >= 0 {
+			switch direction := strings.ToUpper(clause[i+1:]); direction {
+			case "ASC", "DESC":
+				k.desc = direction == "DESC"
+				clause = strings.TrimSpace(clause[:i])
+			}
+		}
+		f, err := Parse(clause + " = 0")
+		if err != nil || len(f) != 1 {
+			continue
+		}
+		k.entity, k.key = f[0].Entity, f[0].Key
+		keys = append(keys, k)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := lookup(items[i]), lookup(items[j])
+		for _, k := range keys {
+			va, oka := a(k.entity, k.key)
+			vb, okb := b(k.entity, k.key)
+			if !oka || !okb {
+				if oka != okb {
+					return oka
+				}
+				continue
+			}
+			if cmp := compare(va, vb); cmp != 0 {
+				return cmp < 0 != k.desc
+			}
+		}
+		return less(items[i], items[j])
+	})
+}
+
+func compare(a interface{}, b interface{}) int {
+	fa, oka := a.(float64)
+	fb, okb := b.(float64)
+	if oka && okb {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,66 @@
+package search
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	run := map[string]map[string]interface{}{
+		"metrics":    {"rmse": 0.4, "f1-score": 0.9},
+		"params":     {"model": "bert-base"},
+		"tags":       {"team": "nlp", "mlflow.parentRunId": "p1"},
+		"attributes": {"status": "FINISHED", "start_time": 1700000000000.0},
+	}
+	lookup := func(entity string, key string) (interface{}, bool) {
+		value, ok := run[entity][key]
+		return value, ok
+	}
+	cases := map[string]bool{
+		"":                   true,
+		"metrics.rmse < 0.5": true,
+		"metrics.rmse < 0.5 and tags.team = 'nlp'":    true,
+		"metrics.rmse < 0.5 AND tags.team = 'cv'":     false,
+		"metrics.`f1-score` >= 0.9":                   true,
+		"params.model LIKE 'bert%'":                   true,
+		"params.model ILIKE 'BERT%'":                  true,
+		"params.model LIKE 'BERT%'":                   false,
+		"tags.mlflow.parentRunId = 'p1'":              true,
+		"attributes.status IN ('FINISHED', 'FAILED')": true,
+		"attributes.status NOT IN ('FINISHED')":       false,
+		"attributes.start_time > 1600000000000":       true,
+		"tags.note = 'a AND b'":                       false,
+		"metrics.missing > 0":                         false,
+		"params.model = \"bert-base\"":                true,
+	}
+	for filter, expected := range cases {
+		f, err := Parse(filter)
+		if err != nil {
+			t.Errorf("%s: %v", filter, err)
+			continue
+		}
+		if f.Match(lookup) != expected {
+			t.Errorf("%s: expected %v", filter, expected)
+		}
+	}
+	for _, filter := range []string{"metrics.rmse", "metrics.rmse < low", "attributes.status IN 'FINISHED'"} {
+		if _, err := Parse(filter); err == nil {
+			t.Errorf("%s: expected an error", filter)
+		}
+	}
+	if f, _ := Parse("tags.note = 'a AND b'"); len(f) != 1 || f[0].Values[0] != "a AND b" {
+		t.Errorf("unexpected clauses %+v", f)
+	}
+}
+
+func TestPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	page, next, err := Page(items, 2, "", 100)
+	if err != nil || len(page) != 2 || next != "2" {
+		t.Fatalf("unexpected page %v %q %v", page, next, err)
+	}
+	page, next, _ = Page(items, 2, "4", 100)
+	if len(page) != 1 || page[0] != 5 || next != "" {
+		t.Errorf("unexpected last page %v %q", page, next)
+	}
+	if _, _, err := Page(items, 2, "x", 100); err == nil {
+		t.Error("expected an error for an invalid token")
+	}
+}
@@ -0,0 +1,97 @@
+// Package store holds the search helpers shared by the in-process
+// implementations of mlflow.API.
+package store
+
+import (
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+// InView reports whether an entity in lifecycleStage is returned by a
+// search with viewType.
+func InView(lifecycleStage string, viewType mlflow.ViewType) bool {
+	switch viewType {
+	case mlflow.All:
+		return true
+	case mlflow.DeletedOnly:
+		return lifecycleStage == "deleted"
+	}
+	return lifecycleStage != "deleted"
+}
+
+func ExperimentLookup(e *mlflow.Experiment) search.Lookup {
+	return func(entity string, key string) (interface{}, bool) {
+		switch entity {
+		case "tags":
+			for _, tag := range e.Tags {
+				if tag.Key == key {
+					return tag.Value, true
+				}
+			}
+			return nil, false
+		case "", "attributes":
+			switch key {
+			case "name":
+				return e.Name, true
+			case "experiment_id":
+				return e.ExperimentId, true
+			case "creation_time":
+				return float64(e.CreationTime), true
+			case "last_update_time":
+				return float64(e.LastUpdateTime), true
+			}
+		}
+		return nil, false
+	}
+}
+
+func RunLookup(run mlflow.Run) search.Lookup {
+	return func(entity string, key string) (interface{}, bool) {
+		switch entity {
+		case "metrics":
+			if value, ok := run.Metric(key); ok {
+				return value, true
+			}
+		case "params":
+			return run.Param(key)
+		case "tags":
+			return run.Tag(key)
+		case "", "attributes":
+			info := run.Info
+			switch key {
+			case "run_id", "run_uuid":
+				return info.RunId, true
+			case "run_name":
+				return info.RunName, true
+			case "status":
+				return info.Status, true
+			case "user_id":
+				return info.UserId, true
+			case "artifact_uri":
+				return info.ArtifactUri, true
+			case "start_time":
+				return float64(info.StartTime), true
+			case "end_time":
+				return float64(info.EndTime), true
+			}
+		}
+		return nil, false
+	}
+}
+
+// SortRuns sorts runs by orderBy, newest first by default.
+func SortRuns(runs []mlflow.Run, orderBy []string) {
+	search.Sort(runs, orderBy, RunLookup, func(a, b mlflow.Run) bool {
+		if a.Info.StartTime != b.Info.StartTime {
+			return a.Info.StartTime > b.Info.StartTime
+		}
+		return a.Info.RunId < b.Info.RunId
+	})
+}
+
+// SortExperiments sorts experiments by orderBy, newest first by default.
+func SortExperiments(experiments []mlflow.Experiment, orderBy []string) {
+	search.Sort(experiments, orderBy, func(e mlflow.Experiment) search.Lookup { return ExperimentLookup(&e) }, func(a, b mlflow.Experiment) bool {
+		return a.CreationTime > b.CreationTime
+	})
+}
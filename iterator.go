@@ -0,0 +1,131 @@
+package mlflow
+
+import "context"
+
+// Iterator walks the results of a paginated call, fetching the next page
+// when the current one is used up:
+//
+//	it := client.IterRuns(ctx, []string{experimentId}, "", ActiveOnly, nil)
+//	for it.Next() {
+//		run := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, pageToken string) ([]T, string, error)
+
+	page      []T
+	index     int
+	pageToken string
+	last      bool
+	err       error
+}
+
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, pageToken string) ([]T, string, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, index: -1}
+}
+
+// Next advances to the next result and reports whether there is one. It
+// returns false at the end of the results or on an error.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.page) {
+		if it.last {
+			return false
+		}
+		page, next, err := it.fetch(it.ctx, it.pageToken)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.index, it.pageToken, it.last = page, 0, next, next == ""
+	}
+	return true
+}
+
+// Value returns the current result.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects the remaining results.
+func (it *Iterator[T]) All() ([]T, error) {
+	var values []T
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+	return values, it.Err()
+}
+
+func (p *Client) IterExperiments(ctx context.Context, filter string, viewType ViewType, orderBy []string) *Iterator[Experiment] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]Experiment, string, error) {
+		response, err := p.SearchExperiments(ctx, filter, viewType, 0, orderBy, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Experiments, response.NextPageToken, nil
+	})
+}
+
+func (p *Client) IterRuns(ctx context.Context, experimentIds []string, filter string, viewType ViewType, orderBy []string) *Iterator[Run] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]Run, string, error) {
+		response, err := p.SearchRuns(ctx, experimentIds, filter, viewType, 0, orderBy, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Runs, response.NextPageToken, nil
+	})
+}
+
+func (p *Client) IterRegisteredModels(ctx context.Context, filter string, orderBy []string) *Iterator[RegisteredModel] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]RegisteredModel, string, error) {
+		response, err := p.SearchRegisteredModels(ctx, filter, 0, orderBy, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.RegisteredModels, response.NextPageToken, nil
+	})
+}
+
+func (p *Client) IterModelVersions(ctx context.Context, filter string, orderBy []string) *Iterator[ModelVersion] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]ModelVersion, string, error) {
+		response, err := p.SearchModelVersions(ctx, filter, 0, orderBy, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.ModelVersions, response.NextPageToken, nil
+	})
+}
+
+// IterArtifacts walks the artifacts directly under path, without
+// descending into directories.
+func (p *Client) IterArtifacts(ctx context.Context, runId string, path string) *Iterator[FileInfo] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]FileInfo, string, error) {
+		response, err := p.listArtifacts(ctx, runId, path, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Files, response.NextPageToken, nil
+	})
+}
+
+func (p *Client) IterMetricHistory(ctx context.Context, runId string, metricKey string) *Iterator[Metric] {
+	return newIterator(ctx, func(ctx context.Context, pageToken string) ([]Metric, string, error) {
+		response, err := p.GetMetricHistory(ctx, runId, metricKey, 0, pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Metrics, response.NextPageToken, nil
+	})
+}
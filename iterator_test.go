@@ -0,0 +1,76 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIterRuns(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		token, _ := request["page_token"].(string)
+		tokens = append(tokens, token)
+		switch token {
+		case "":
+			w.Write([]byte(`{"runs": [{"info": {"run_id": "1"}}, {"info": {"run_id": "2"}}], "next_page_token": "a"}`))
+		case "a":
+			// An empty page in the middle is skipped.
+			w.Write([]byte(`{"runs": [], "next_page_token": "b"}`))
+		default:
+			w.Write([]byte(`{"runs": [{"info": {"run_id": "3"}}]}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	it := client.IterRuns(context.Background(), []string{"0"}, "", ActiveOnly, nil)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().Info.RunId)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[2] != "3" {
+		t.Errorf("unexpected runs %v", ids)
+	}
+	if len(tokens) != 3 || tokens[1] != "a" || tokens[2] != "b" {
+		t.Errorf("unexpected page tokens %v", tokens)
+	}
+	if it.Next() {
+		t.Error("expected the iterator to stay exhausted")
+	}
+}
+
+func TestIterMetricHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/mlflow/metrics/get-history" || r.URL.Query().Get("metric_key") != "loss" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("page_token") == "" {
+			w.Write([]byte(`{"metrics": [{"key": "loss", "value": 0.5, "step": 0}], "next_page_token": "1"}`))
+			return
+		}
+		w.Write([]byte(`{"metrics": [{"key": "loss", "value": "NaN", "step": 1}]}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	metrics, err := client.IterMetricHistory(context.Background(), "run1", "loss").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 || metrics[0].Value != 0.5 || metrics[1].Step != 1 {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+	it := client.IterMetricHistory(context.Background(), "run1", "missing")
+	if it.Next() || !IsNotFound(it.Err()) {
+		t.Errorf("expected not found, got %v", it.Err())
+	}
+}
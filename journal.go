@@ -0,0 +1,236 @@
+package mlflow
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Journal logs to the tracking server while it is reachable and appends the
+// calls to a local JSON lines file while it is not, so that no tracking data
+// is lost during network partitions. Sync replays the journal once the
+// server is back. Calls made while entries are pending are journaled too,
+// keeping everything in the order it was logged.
+type Journal struct {
+	client *Client
+	path   string
+
+	mu      sync.Mutex
+	pending int
+}
+
+type journalEntry struct {
+	Op      string    `json:"op"`
+	RunId   string    `json:"run_id"`
+	Metrics []Metric  `json:"metrics,omitempty"`
+	Params  []Param   `json:"params,omitempty"`
+	Tags    []RunTag  `json:"tags,omitempty"`
+	Status  RunStatus `json:"status,omitempty"`
+	EndTime int64     `json:"end_time,omitempty"`
+}
+
+const (
+	journalLogBatch  = "log_batch"
+	journalUpdateRun = "update_run"
+)
+
+// OpenJournal opens the journal at path, creating it if needed. Entries left
+// by an earlier process are kept until the next Sync.
+func (p *Client) OpenJournal(path string) (*Journal, error) {
+	entries, err := readJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{client: p, path: path, pending: len(entries)}, nil
+}
+
+// Pending returns the number of journaled calls not yet sent.
+func (j *Journal) Pending() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.pending
+}
+
+func (j *Journal) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	return j.LogBatch(ctx, runId, []Metric{{Key: key, Value: value, Timestamp: timestamp, Step: step}}, nil, nil)
+}
+
+func (j *Journal) LogParam(ctx context.Context, runId string, key string, value string) error {
+	return j.LogBatch(ctx, runId, nil, []Param{{Key: key, Value: value}}, nil)
+}
+
+func (j *Journal) SetTag(ctx context.Context, runId string, key string, value string) error {
+	return j.LogBatch(ctx, runId, nil, nil, []RunTag{{Key: key, Value: value}})
+}
+
+// LogBatch journals batches larger than one request as an entry per request,
+// so that Sync never sends part of a batch twice.
+func (j *Journal) LogBatch(ctx context.Context, runId string, metrics []Metric, params []Param, tags []RunTag) error {
+	return splitBatch(metrics, params, tags, func(metrics []Metric, params []Param, tags []RunTag) error {
+		return j.submit(ctx, journalEntry{Op: journalLogBatch, RunId: runId, Metrics: metrics, Params: params, Tags: tags})
+	})
+}
+
+// UpdateRun sets the run's status and end time, in milliseconds since the
+// epoch.
+func (j *Journal) UpdateRun(ctx context.Context, runId string, status RunStatus, endTime int64) error {
+	return j.submit(ctx, journalEntry{Op: journalUpdateRun, RunId: runId, Status: status, EndTime: endTime})
+}
+
+func (j *Journal) submit(ctx context.Context, entry journalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.pending == 0 {
+		err := j.send(ctx, entry)
+		if err == nil || isRejected(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	if err := j.append(entry); err != nil {
+		return err
+	}
+	j.pending++
+	return nil
+}
+
+func (j *Journal) send(ctx context.Context, entry journalEntry) error {
+	switch entry.Op {
+	case journalUpdateRun:
+		_, err := j.client.UpdateRunWithEndTime(ctx, entry.RunId, entry.Status, entry.EndTime)
+		return err
+	default:
+		return j.client.LogBatch(ctx, entry.RunId, entry.Metrics, entry.Params, entry.Tags)
+	}
+}
+
+func (j *Journal) append(entry journalEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(b, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Sync replays the journal in order. It stops at the first call that fails
+// because the server is still unreachable or overloaded, or because ctx is
+// done, keeping it and the calls after it for the next Sync. Calls the
+// server rejects with a 4xx response are dropped and their errors are
+// returned together as a MultiError.
+func (j *Journal) Sync(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries, err := readJournal(j.path)
+	if err != nil {
+		return err
+	}
+	var errs MultiError
+	sent := 0
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		err := j.send(ctx, entry)
+		if err != nil && (ctx.Err() != nil || !isRejected(err)) {
+			errs = append(errs, err)
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		sent++
+	}
+	if err := writeJournal(j.path, entries[sent:]); err != nil {
+		return err
+	}
+	j.pending = len(entries) - sent
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// isRejected reports whether err means the server refused the call for
+// good, as opposed to it being unreachable or failing transiently.
+func isRejected(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) || IsRetryable(err) {
+		return false
+	}
+	return e.StatusCode >= 400 && e.StatusCode < 500
+}
+
+func readJournal(path string) ([]journalEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []journalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A line cut short by a crash is the last one written.
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func writeJournal(path string, entries []journalEntry) error {
+	if len(entries) == 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,168 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	down := false
+	var logged []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var request struct {
+			Metrics []Metric `json:"metrics"`
+			Params  []Param  `json:"params"`
+			Status  string   `json:"status"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		for _, param := range request.Params {
+			if param.Key == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error_code": "INVALID_PARAMETER_VALUE", "message": "bad param"}`))
+				return
+			}
+			logged = append(logged, param.Key)
+		}
+		for _, metric := range request.Metrics {
+			logged = append(logged, metric.Key)
+		}
+		if request.Status != "" {
+			logged = append(logged, request.Status)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := New(server.URL).OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.LogParam(ctx, "run1", "lr", "0.1"); err != nil || journal.Pending() != 0 {
+		t.Fatalf("online call failed: %v", err)
+	}
+	down = true
+	journal.LogMetric(ctx, "run1", "loss", 0.5, 1000, 0)
+	journal.LogParam(ctx, "run1", "bad", "x")
+	down = false
+	// Calls keep going to the journal until it is synced, to stay in order.
+	journal.UpdateRun(ctx, "run1", Finished, 2000)
+	if journal.Pending() != 3 {
+		t.Fatalf("expected 3 pending calls, got %d", journal.Pending())
+	}
+
+	// A new process sees the entries left behind.
+	journal, err = New(server.URL).OpenJournal(path)
+	if err != nil || journal.Pending() != 3 {
+		t.Fatalf("reopened journal has %d pending calls: %v", journal.Pending(), err)
+	}
+	down = true
+	if err := journal.Sync(ctx); err == nil || journal.Pending() != 3 {
+		t.Errorf("sync while down left %d pending calls: %v", journal.Pending(), err)
+	}
+	down = false
+	err = journal.Sync(ctx)
+	var errs MultiError
+	if !errors.As(err, &errs) || len(errs) != 1 || !IsInvalidParameter(errs[0]) {
+		t.Errorf("expected the rejected param error, got %v", err)
+	}
+	if journal.Pending() != 0 {
+		t.Errorf("expected an empty journal, got %d pending calls", journal.Pending())
+	}
+	if len(logged) != 3 || logged[0] != "lr" || logged[1] != "loss" || logged[2] != "FINISHED" {
+		t.Errorf("logged %v in the wrong order", logged)
+	}
+	if err := journal.LogParam(ctx, "run1", "epochs", "10"); err != nil || journal.Pending() != 0 {
+		t.Errorf("online call after sync failed: %v", err)
+	}
+}
+
+// TestJournalSyncInterrupted checks that Sync keeps the entries it could
+// not send, whether the server is overloaded or ctx is cancelled.
+func TestJournalSyncInterrupted(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var logged []string
+	interrupted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Params []Param `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if request.Params[0].Key == "c" && !interrupted {
+			// The client gives up before the server answers.
+			interrupted = true
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		logged = append(logged, request.Params[0].Key)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	journal, err := New(server.URL).OpenJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := journal.LogParam(ctx, "run1", key, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, status = range []int{http.StatusTooManyRequests, http.StatusInternalServerError} {
+		if err := journal.Sync(ctx); err == nil || journal.Pending() != 4 {
+			t.Errorf("sync on status %d left %d pending calls: %v", status, journal.Pending(), err)
+		}
+	}
+
+	status = http.StatusOK
+	err = journal.Sync(ctx)
+	var errs MultiError
+	if !errors.As(err, &errs) || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("expected the sync to be cancelled, got %v", err)
+	}
+	if journal.Pending() != 2 {
+		t.Fatalf("expected 2 pending calls after the cancelled sync, got %d", journal.Pending())
+	}
+	if err := journal.Sync(context.Background()); err != nil || journal.Pending() != 0 {
+		t.Errorf("sync left %d pending calls: %v", journal.Pending(), err)
+	}
+	if len(logged) != 4 || logged[2] != "c" || logged[3] != "d" {
+		t.Errorf("logged %v", logged)
+	}
+}
+
+func TestJournalSplitsBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := New("http://127.0.0.1:1").OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := make([]Param, maxBatchParams+1)
+	for i := range params {
+		params[i] = Param{Key: randomId(4), Value: "1"}
+	}
+	if err := journal.LogBatch(context.Background(), "run1", nil, params, nil); err != nil {
+		t.Fatal(err)
+	}
+	if journal.Pending() != 2 {
+		t.Errorf("expected an entry per request, got %d", journal.Pending())
+	}
+}
@@ -0,0 +1,236 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+type LoggedModelStatus string
+
+const (
+	LoggedModelPending      LoggedModelStatus = "LOGGED_MODEL_PENDING"
+	LoggedModelReady        LoggedModelStatus = "LOGGED_MODEL_READY"
+	LoggedModelUploadFailed LoggedModelStatus = "LOGGED_MODEL_UPLOAD_FAILED"
+)
+
+// LoggedModel is a model checkpoint tracked on its own rather than as the
+// artifacts of a run, available in MLflow 3.0 and later.
+type LoggedModel struct {
+	Info LoggedModelInfo `json:"info"`
+	Data LoggedModelData `json:"data"`
+}
+
+type LoggedModelInfo struct {
+	ModelId                string                    `json:"model_id"`
+	ExperimentId           string                    `json:"experiment_id"`
+	Name                   string                    `json:"name"`
+	CreationTimestampMs    int64                     `json:"creation_timestamp_ms,omitempty"`
+	LastUpdatedTimestampMs int64                     `json:"last_updated_timestamp_ms,omitempty"`
+	ArtifactUri            string                    `json:"artifact_uri,omitempty"`
+	Status                 LoggedModelStatus         `json:"status,omitempty"`
+	CreatorId              string                    `json:"creator_id,omitempty"`
+	ModelType              string                    `json:"model_type,omitempty"`
+	SourceRunId            string                    `json:"source_run_id,omitempty"`
+	StatusMessage          string                    `json:"status_message,omitempty"`
+	Tags                   []LoggedModelTag          `json:"tags,omitempty"`
+	Registrations          []LoggedModelRegistration `json:"registrations,omitempty"`
+}
+
+type LoggedModelData struct {
+	Params  []LoggedModelParameter `json:"params,omitempty"`
+	Metrics []Metric               `json:"metrics,omitempty"`
+}
+
+type LoggedModelTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type LoggedModelParameter struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type LoggedModelRegistration struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// LoggedModelOrderBy orders the results of SearchLoggedModels by an
+// attribute such as "creation_timestamp", or by a metric such as
+// "metrics.accuracy", evaluated on a dataset when DatasetName is set.
+type LoggedModelOrderBy struct {
+	FieldName     string `json:"field_name"`
+	Ascending     bool   `json:"ascending"`
+	DatasetName   string `json:"dataset_name,omitempty"`
+	DatasetDigest string `json:"dataset_digest,omitempty"`
+}
+
+type ResponseLoggedModel struct {
+	Model LoggedModel `json:"model"`
+}
+
+type ResponseSearchLoggedModels struct {
+	Models        []LoggedModel `json:"models"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+}
+
+// Tag returns the value of the tag key of the model.
+func (m *LoggedModel) Tag(key string) (string, bool) {
+	for _, tag := range m.Info.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+func decodeLoggedModel(body []byte, err error) (*LoggedModel, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseLoggedModel
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Model, nil
+}
+
+func loggedModelTags(tags map[string]string) []LoggedModelTag {
+	list := make([]LoggedModelTag, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		list = append(list, LoggedModelTag{Key: key, Value: tags[key]})
+	}
+	return list
+}
+
+func loggedModelParams(params map[string]string) []LoggedModelParameter {
+	list := make([]LoggedModelParameter, 0, len(params))
+	for _, key := range sortedKeys(params) {
+		list = append(list, LoggedModelParameter{Key: key, Value: params[key]})
+	}
+	return list
+}
+
+func (p *Client) requireLoggedModels() error {
+	return p.require("logged models", func(c *Capabilities) bool { return c.LoggedModels })
+}
+
+// CreateLoggedModel creates a model in the LoggedModelPending status. Upload
+// its files under Info.ArtifactUri, then call FinalizeLoggedModel. sourceRunId
+// may be empty.
+func (p *Client) CreateLoggedModel(ctx context.Context, experimentId string, name string, modelType string, sourceRunId string, params map[string]string, tags map[string]string) (*LoggedModel, error) {
+	if err := p.requireLoggedModels(); err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/logged-models"
+	request := map[string]interface{}{"experiment_id": experimentId, "params": loggedModelParams(params), "tags": loggedModelTags(tags)}
+	if name != "" {
+		request["name"] = name
+	}
+	if modelType != "" {
+		request["model_type"] = modelType
+	}
+	if sourceRunId != "" {
+		request["source_run_id"] = sourceRunId
+	}
+	return decodeLoggedModel(p.HandlePost(ctx, url, request))
+}
+
+func (p *Client) GetLoggedModel(ctx context.Context, modelId string) (*LoggedModel, error) {
+	if err := p.requireLoggedModels(); err != nil {
+		return nil, err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId)
+	return decodeLoggedModel(p.HandleGet(ctx, endpoint, nil))
+}
+
+func (p *Client) DeleteLoggedModel(ctx context.Context, modelId string) error {
+	if err := p.requireLoggedModels(); err != nil {
+		return err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId)
+	_, err := p.HandleDelete(ctx, endpoint, map[string]interface{}{"model_id": modelId})
+	return err
+}
+
+// FinalizeLoggedModel sets the final status of a model, LoggedModelReady
+// once its files are uploaded or LoggedModelUploadFailed.
+func (p *Client) FinalizeLoggedModel(ctx context.Context, modelId string, status LoggedModelStatus) (*LoggedModel, error) {
+	if err := p.requireLoggedModels(); err != nil {
+		return nil, err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId)
+	return decodeLoggedModel(p.HandlePatch(ctx, endpoint, map[string]interface{}{"model_id": modelId, "status": status}))
+}
+
+func (p *Client) SearchLoggedModels(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []LoggedModelOrderBy, pageToken string) (*ResponseSearchLoggedModels, error) {
+	if err := p.requireLoggedModels(); err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/logged-models/search"
+	request := map[string]interface{}{"experiment_ids": experimentIds}
+	if filter != "" {
+		request["filter"] = filter
+	}
+	if maxResults > 0 {
+		request["max_results"] = maxResults
+	}
+	if len(orderBy) > 0 {
+		request["order_by"] = orderBy
+	}
+	if pageToken != "" {
+		request["page_token"] = pageToken
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchLoggedModels
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Client) SetLoggedModelTags(ctx context.Context, modelId string, tags map[string]string) error {
+	if err := p.requireLoggedModels(); err != nil {
+		return err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId) + "/tags"
+	_, err := p.HandlePatch(ctx, endpoint, map[string]interface{}{"model_id": modelId, "tags": loggedModelTags(tags)})
+	return err
+}
+
+func (p *Client) DeleteLoggedModelTag(ctx context.Context, modelId string, key string) error {
+	if err := p.requireLoggedModels(); err != nil {
+		return err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId) + "/tags/" + url.PathEscape(key)
+	_, err := p.HandleDelete(ctx, endpoint, map[string]interface{}{"model_id": modelId, "tag_key": key})
+	return err
+}
+
+func (p *Client) LogLoggedModelParams(ctx context.Context, modelId string, params map[string]string) error {
+	if err := p.requireLoggedModels(); err != nil {
+		return err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/logged-models/" + url.PathEscape(modelId) + "/params"
+	_, err := p.HandlePost(ctx, endpoint, map[string]interface{}{"model_id": modelId, "params": loggedModelParams(params)})
+	return err
+}
+
+// LogLoggedModelMetric logs a metric of a model, evaluated in the run runId.
+// The metric is also one of the run's metrics.
+func (p *Client) LogLoggedModelMetric(ctx context.Context, modelId string, runId string, key string, value float64, timestamp int64, step int64) error {
+	if err := p.requireLoggedModels(); err != nil {
+		return err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/log-metric"
+	request := map[string]interface{}{"run_id": runId, "model_id": modelId, "key": key, "value": floatValue(value), "timestamp": timestamp, "step": step}
+	_, err := p.HandlePost(ctx, url, request)
+	return err
+}
@@ -0,0 +1,117 @@
+package mlflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestLoggedModels(t *testing.T) {
+	model := mlflow.LoggedModel{Info: mlflow.LoggedModelInfo{ModelId: "m-1", ExperimentId: "0", Name: "agent", Status: mlflow.LoggedModelPending}}
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&request)
+		request["endpoint"] = r.Method + " " + r.URL.Path
+		requests = append(requests, request)
+		switch request["endpoint"] {
+		case "POST /api/2.0/mlflow/logged-models":
+			json.NewEncoder(w).Encode(mlflow.ResponseLoggedModel{Model: model})
+		case "GET /api/2.0/mlflow/logged-models/m-1":
+			json.NewEncoder(w).Encode(mlflow.ResponseLoggedModel{Model: model})
+		case "PATCH /api/2.0/mlflow/logged-models/m-1":
+			model.Info.Status = mlflow.LoggedModelStatus(request["status"].(string))
+			json.NewEncoder(w).Encode(mlflow.ResponseLoggedModel{Model: model})
+		case "POST /api/2.0/mlflow/logged-models/search":
+			json.NewEncoder(w).Encode(mlflow.ResponseSearchLoggedModels{Models: []mlflow.LoggedModel{model}})
+		default:
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	created, err := client.CreateLoggedModel(ctx, "0", "agent", "agent", "run-1", map[string]string{"b": "2", "a": "1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Info.ModelId != "m-1" || created.Info.Status != mlflow.LoggedModelPending {
+		t.Errorf("unexpected model %+v", created)
+	}
+	if params, _ := json.Marshal(requests[0]["params"]); string(params) != `[{"key":"a","value":"1"},{"key":"b","value":"2"}]` || requests[0]["source_run_id"] != "run-1" {
+		t.Errorf("unexpected request %+v", requests[0])
+	}
+	if err := client.SetLoggedModelTags(ctx, "m-1", map[string]string{"stage": "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogLoggedModelParams(ctx, "m-1", map[string]string{"temperature": "0.2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogLoggedModelMetric(ctx, "m-1", "run-1", "accuracy", 0.9, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteLoggedModelTag(ctx, "m-1", "stage"); err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := client.FinalizeLoggedModel(ctx, "m-1", mlflow.LoggedModelReady)
+	if err != nil || finalized.Info.Status != mlflow.LoggedModelReady {
+		t.Errorf("unexpected model %+v, %v", finalized, err)
+	}
+	got, err := client.GetLoggedModel(ctx, "m-1")
+	if err != nil || got.Info.Status != mlflow.LoggedModelReady {
+		t.Errorf("unexpected model %+v, %v", got, err)
+	}
+	response, err := client.SearchLoggedModels(ctx, []string{"0"}, "name = 'agent'", 10, []mlflow.LoggedModelOrderBy{{FieldName: "metrics.accuracy", DatasetName: "eval"}}, "")
+	if err != nil || len(response.Models) != 1 {
+		t.Errorf("unexpected search response %+v, %v", response, err)
+	}
+	if err := client.DeleteLoggedModel(ctx, "m-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /api/2.0/mlflow/logged-models",
+		"PATCH /api/2.0/mlflow/logged-models/m-1/tags",
+		"POST /api/2.0/mlflow/logged-models/m-1/params",
+		"POST /api/2.0/mlflow/runs/log-metric",
+		"DELETE /api/2.0/mlflow/logged-models/m-1/tags/stage",
+		"PATCH /api/2.0/mlflow/logged-models/m-1",
+		"GET /api/2.0/mlflow/logged-models/m-1",
+		"POST /api/2.0/mlflow/logged-models/search",
+		"DELETE /api/2.0/mlflow/logged-models/m-1",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+	for i, endpoint := range expected {
+		if requests[i]["endpoint"] != endpoint {
+			t.Errorf("request %d: expected %s, got %s", i, endpoint, requests[i]["endpoint"])
+		}
+	}
+	if requests[3]["model_id"] != "m-1" || requests[3]["run_id"] != "run-1" {
+		t.Errorf("unexpected metric request %+v", requests[3])
+	}
+}
+
+func TestLoggedModelsUnsupported(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	if _, err := client.ProbeCapabilities(ctx); err != nil {
+		t.Fatal(err)
+	}
+	server.ClearRequests()
+	if _, err := client.CreateLoggedModel(ctx, "0", "agent", "", "", nil, nil); !mlflow.IsNotImplemented(err) {
+		t.Errorf("expected logged models to be unsupported, got %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("expected no request, got %+v", server.Requests())
+	}
+}
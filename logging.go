@@ -0,0 +1,153 @@
+package mlflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Logger receives debug events from a Client as a message followed by
+// alternating keys and values. A *slog.Logger satisfies it.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+}
+
+// WithLogger logs the method, path, status, latency and attempt of every
+// request sent to logger.
+func WithLogger(logger Logger) Option {
+	return func(p *Client) {
+		p.logger = logger
+	}
+}
+
+// WithBodyLogging also logs JSON request and response bodies, up to
+// maxBodyLog bytes each, with secrets such as passwords, tokens and
+// credentials redacted. It needs WithLogger.
+func WithBodyLogging() Option {
+	return func(p *Client) {
+		p.logBodies = true
+	}
+}
+
+const maxBodyLog = 4096
+
+type attemptKey struct{}
+
+// Attempt returns the retry attempt of req, counting from 1, so that
+// middleware inside WithRetry can tell retries apart.
+func Attempt(req *http.Request) int {
+	if n, ok := req.Context().Value(attemptKey{}).(int); ok {
+		return n
+	}
+	return 1
+}
+
+func withAttempt(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, n)
+}
+
+// logRequest sends req, logging it when a logger is configured.
+func (p *Client) logRequest(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if p.logger == nil {
+		return send(req)
+	}
+	var requestBody string
+	if p.logBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody = dumpBody(body, req.Header)
+			body.Close()
+		}
+	}
+	start := time.Now()
+	resp, err := send(req)
+	kv := []interface{}{"method", req.Method, "path", req.URL.Path, "attempt", Attempt(req), "latency", time.Since(start)}
+	if err != nil {
+		kv = append(kv, "error", err.Error())
+	} else {
+		kv = append(kv, "status", resp.StatusCode)
+	}
+	if p.logBodies {
+		if requestBody != "" {
+			kv = append(kv, "request_body", requestBody)
+		}
+		if err == nil && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+			b, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			if readErr == nil {
+				kv = append(kv, "response_body", redactBody(b))
+			}
+		}
+	}
+	p.logger.Debug("mlflow request", kv...)
+	return resp, err
+}
+
+func dumpBody(body io.Reader, header http.Header) string {
+	if header.Get("Content-Encoding") == "gzip" {
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			return ""
+		}
+		body = r
+	}
+	if !strings.HasPrefix(header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	return redactBody(b)
+}
+
+var secretKey = regexp.MustCompile(`(?i)password|secret|token|authorization|credential|api_?key|access_?key`)
+
+// redactBody replaces the values of secret looking fields of a JSON body,
+// including tags and params whose key looks secret, and truncates it.
+func redactBody(b []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return "[unparsed body]"
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[unparsed body]"
+	}
+	if len(redacted) > maxBodyLog {
+		return string(redacted[:maxBodyLog]) + "...[truncated]"
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if secretKey.MatchString(k) {
+				value[k] = "[REDACTED]"
+			} else {
+				value[k] = redactValue(v)
+			}
+		}
+		if key, ok := value["key"].(string); ok && secretKey.MatchString(key) {
+			if _, ok := value["value"]; ok {
+				value["value"] = "[REDACTED]"
+			}
+		}
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redactValue(v)
+		}
+		return value
+	}
+	return value
+}
@@ -0,0 +1,57 @@
+package mlflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	entries []map[string]interface{}
+}
+
+func (l *recordingLogger) Debug(msg string, keysAndValues ...interface{}) {
+	entry := map[string]interface{}{"msg": msg}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		entry[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func TestWithLogger(t *testing.T) {
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user": {"username": "alice", "password": "hunter2"}}`))
+	}))
+	defer server.Close()
+	logger := &recordingLogger{}
+	client := New(server.URL, WithLogger(logger), WithBodyLogging(), WithRetry(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+
+	if err := client.SetTag(context.Background(), "run1", "hf_token", "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected an entry per attempt, got %v", logger.entries)
+	}
+	first, second := logger.entries[0], logger.entries[1]
+	if first["path"] != "/api/2.0/mlflow/runs/set-tag" || first["status"] != 503 || first["attempt"] != 1 || second["attempt"] != 2 {
+		t.Errorf("unexpected entries %v", logger.entries)
+	}
+	if _, ok := second["latency"].(time.Duration); !ok {
+		t.Errorf("missing latency in %v", second)
+	}
+	dump := fmt.Sprint(second["request_body"], second["response_body"])
+	if strings.Contains(dump, "abc123") || strings.Contains(dump, "hunter2") || !strings.Contains(dump, "alice") || !strings.Contains(dump, "hf_token") {
+		t.Errorf("secrets were not redacted: %s", dump)
+	}
+}
@@ -0,0 +1,201 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+const (
+	// The get-history-bulk-interval endpoint accepts up to 100 runs and
+	// returns up to 2500 steps per run.
+	maxBulkIntervalRuns    = 100
+	maxBulkIntervalResults = 2500
+)
+
+// MetricHistoryOptions configures GetMetricHistories.
+type MetricHistoryOptions struct {
+	// MaxResults, when set, samples the histories to at most MaxResults
+	// steps per run, always keeping the first and last steps, through the
+	// get-history-bulk-interval endpoint of MLflow 2.10 and later. It is
+	// capped at 2500. Older servers return the full histories.
+	MaxResults int
+	// Concurrency is the number of requests sent at the same time.
+	// Defaults to 8.
+	Concurrency int
+}
+
+// MetricSeries is the history of a metric on several runs, aligned by
+// step: Values[i][j] is the value of the metric on RunIds[i] at Steps[j],
+// when Present[i][j]. A step logged several times keeps its latest value.
+type MetricSeries struct {
+	Key     string
+	RunIds  []string
+	Steps   []int64
+	Values  [][]float64
+	Present [][]bool
+}
+
+// GetMetricHistories fetches the histories of metricKeys on runIds in
+// parallel and returns a series per key, in the order of metricKeys.
+func (p *Client) GetMetricHistories(ctx context.Context, runIds []string, metricKeys []string, opts MetricHistoryOptions) ([]MetricSeries, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	if opts.MaxResults > maxBulkIntervalResults {
+		opts.MaxResults = maxBulkIntervalResults
+	}
+	histories := make([]map[string][]Metric, len(metricKeys))
+	for i := range histories {
+		histories[i] = map[string][]Metric{}
+	}
+	var mu sync.Mutex
+	add := func(i int, metrics []Metric) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, metric := range metrics {
+			histories[i][metric.RunId] = append(histories[i][metric.RunId], metric)
+		}
+	}
+
+	bulk := opts.MaxResults > 0 && len(runIds) > 0
+	if bulk {
+		type job struct {
+			key    int
+			runIds []string
+		}
+		var jobs []job
+		for i := range metricKeys {
+			for start := 0; start < len(runIds); start += maxBulkIntervalRuns {
+				end := start + maxBulkIntervalRuns
+				if end > len(runIds) {
+					end = len(runIds)
+				}
+				jobs = append(jobs, job{i, runIds[start:end]})
+			}
+		}
+		fetch := func(ctx context.Context, j job) error {
+			metrics, err := p.getMetricHistoryBulkInterval(ctx, j.runIds, metricKeys[j.key], opts.MaxResults)
+			if err == nil {
+				add(j.key, metrics)
+			}
+			return err
+		}
+		// The first request finds out whether the server has the endpoint.
+		if len(jobs) > 0 {
+			if err := fetch(ctx, jobs[0]); endpointMissing(err) {
+				bulk = false
+			} else if err != nil {
+				return nil, err
+			} else if err := parallel(ctx, len(jobs)-1, opts.Concurrency, func(ctx context.Context, i int) error {
+				return fetch(ctx, jobs[i+1])
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !bulk {
+		err := parallel(ctx, len(metricKeys)*len(runIds), opts.Concurrency, func(ctx context.Context, i int) error {
+			key, runId := i/len(runIds), runIds[i%len(runIds)]
+			var metrics []Metric
+			it := p.IterMetricHistory(ctx, runId, metricKeys[key])
+			for it.Next() {
+				metric := it.Value()
+				metric.RunId = runId
+				metrics = append(metrics, metric)
+			}
+			if err := it.Err(); err != nil {
+				return err
+			}
+			add(key, metrics)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	series := make([]MetricSeries, len(metricKeys))
+	for i, key := range metricKeys {
+		series[i] = alignMetrics(key, runIds, histories[i])
+	}
+	return series, nil
+}
+
+func (p *Client) getMetricHistoryBulkInterval(ctx context.Context, runIds []string, metricKey string, maxResults int) ([]Metric, error) {
+	url := p.BaseUrl + "/ajax-api/2.0/mlflow/metrics/get-history-bulk-interval"
+	body, err := p.HandleGet(ctx, url, map[string]interface{}{"run_ids": runIds, "metric_key": metricKey, "max_results": maxResults})
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseGetMetricHistory
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Metrics, nil
+}
+
+func alignMetrics(key string, runIds []string, histories map[string][]Metric) MetricSeries {
+	series := MetricSeries{Key: key, RunIds: runIds, Values: make([][]float64, len(runIds)), Present: make([][]bool, len(runIds))}
+	index := map[int64]int{}
+	for _, runId := range runIds {
+		for _, metric := range histories[runId] {
+			index[metric.Step] = 0
+		}
+	}
+	for step := range index {
+		series.Steps = append(series.Steps, step)
+	}
+	sort.Slice(series.Steps, func(i, j int) bool { return series.Steps[i] < series.Steps[j] })
+	for j, step := range series.Steps {
+		index[step] = j
+	}
+	for i, runId := range runIds {
+		series.Values[i] = make([]float64, len(series.Steps))
+		series.Present[i] = make([]bool, len(series.Steps))
+		timestamps := make([]int64, len(series.Steps))
+		for _, metric := range histories[runId] {
+			j := index[metric.Step]
+			if !series.Present[i][j] || metric.Timestamp >= timestamps[j] {
+				series.Values[i][j], series.Present[i][j], timestamps[j] = metric.Value, true, metric.Timestamp
+			}
+		}
+	}
+	return series
+}
+
+// parallel calls fn for 0 to n-1 on up to concurrency goroutines,
+// returning the first error, after which the remaining calls are skipped.
+func parallel(ctx context.Context, n int, concurrency int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan int)
+	var once sync.Once
+	var first error
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() { first = err; cancel() })
+				}
+			}
+		}()
+	}
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,106 @@
+package mlflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestGetMetricHistoriesBulkInterval(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ajax-api/2.0/mlflow/metrics/get-history-bulk-interval" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		atomic.AddInt32(&requests, 1)
+		query := r.URL.Query()
+		if query.Get("max_results") != "2500" || query.Get("metric_key") != "loss" {
+			t.Errorf("unexpected query %v", query)
+		}
+		var metrics []mlflow.Metric
+		for _, runId := range query["run_ids"] {
+			metrics = append(metrics, mlflow.Metric{Key: "loss", Value: 1, Step: 0, RunId: runId}, mlflow.Metric{Key: "loss", Value: 0.5, Step: 10, RunId: runId})
+		}
+		json.NewEncoder(w).Encode(mlflow.ResponseGetMetricHistory{Metrics: metrics})
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	runIds := make([]string, 150)
+	for i := range runIds {
+		runIds[i] = fmt.Sprintf("run-%d", i)
+	}
+	series, err := client.GetMetricHistories(context.Background(), runIds, []string{"loss"}, mlflow.MetricHistoryOptions{MaxResults: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the runs to be fetched in 2 requests, got %d", requests)
+	}
+	if len(series) != 1 || !reflect.DeepEqual(series[0].Steps, []int64{0, 10}) || len(series[0].Values) != 150 {
+		t.Fatalf("unexpected series %+v", series)
+	}
+	if last := series[0].Values[149]; !reflect.DeepEqual(last, []float64{1, 0.5}) {
+		t.Errorf("unexpected values %v", last)
+	}
+}
+
+func TestGetMetricHistoriesFallback(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	var runIds []string
+	for i := 0; i < 3; i++ {
+		run, err := client.CreateRun(ctx, "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, run.Info.RunId)
+	}
+	metrics := [][]mlflow.Metric{
+		{{Key: "loss", Value: 1, Timestamp: 1, Step: 0}, {Key: "loss", Value: 0.5, Timestamp: 2, Step: 1}},
+		{{Key: "loss", Value: 0.8, Timestamp: 1, Step: 1}, {Key: "loss", Value: 0.7, Timestamp: 2, Step: 1}, {Key: "acc", Value: 0.9, Timestamp: 1, Step: 2}},
+		nil,
+	}
+	for i, runId := range runIds {
+		if err := client.LogBatch(ctx, runId, metrics[i], nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	series, err := client.GetMetricHistories(ctx, runIds, []string{"loss", "acc"}, mlflow.MetricHistoryOptions{MaxResults: 100, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []mlflow.MetricSeries{
+		{
+			Key:     "loss",
+			RunIds:  runIds,
+			Steps:   []int64{0, 1},
+			Values:  [][]float64{{1, 0.5}, {0, 0.7}, {0, 0}},
+			Present: [][]bool{{true, true}, {false, true}, {false, false}},
+		},
+		{
+			Key:     "acc",
+			RunIds:  runIds,
+			Steps:   []int64{2},
+			Values:  [][]float64{{0}, {0.9}, {0}},
+			Present: [][]bool{{false}, {true}, {false}},
+		},
+	}
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("unexpected series %+v", series)
+	}
+
+	if _, err := client.GetMetricHistories(ctx, []string{runIds[0], "missing"}, []string{"loss"}, mlflow.MetricHistoryOptions{}); !mlflow.IsNotFound(err) {
+		t.Errorf("expected a missing run to fail, got %v", err)
+	}
+}
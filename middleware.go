@@ -0,0 +1,58 @@
+package mlflow
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RoundTripFunc sends a single request to the tracking server.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every request made by a Client. The next
+// function adds the client's headers and credentials, so a middleware that
+// calls it more than once, for example to retry, gets a freshly signed
+// request each time. Such a middleware must rewind the body with
+// req.GetBody before calling next again.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middleware to the client. The first middleware
+// given is the outermost one.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(p *Client) {
+		p.middleware = append(p.middleware, middleware...)
+	}
+}
+
+// idResources are the path segments followed by an id, such as traces in
+// traces/<request_id>/tags, unless the next segment is one of idActions.
+var (
+	idResources = map[string]bool{"traces": true, "assessments": true, "logged-models": true, "tags": true, "endpoints": true}
+	idActions   = map[string]bool{"search": true, "delete-traces": true}
+)
+
+// Operation names the API call made by req after its path, such as
+// "runs/log-batch" or "mlflow-artifacts/artifacts". Ids in the path are
+// replaced with {id}, as in "traces/{id}/tags", so that the number of
+// operations stays bounded. It is meant for labelling requests in
+// middleware.
+func Operation(req *http.Request) string {
+	path := req.URL.Path
+	for _, prefix := range []string{"/api/2.0/mlflow/", "/ajax-api/2.0/mlflow/", "/api/3.0/mlflow/", "/api/2.0/"} {
+		if i := strings.Index(path, prefix); i >= 0 {
+			path = path[i+len(prefix):]
+			break
+		}
+	}
+	if strings.HasPrefix(path, "mlflow-artifacts/") {
+		// Drop the artifact path.
+		parts := strings.SplitN(path, "/", 3)
+		return strings.Join(parts[:2], "/")
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if idResources[segments[i-1]] && !idActions[segments[i]] {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,72 @@
+package mlflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" "+req.Header.Get("Authorization"))
+				req.Header.Set("X-"+name, "1")
+				resp, err := next(req)
+				order = append(order, name+" done")
+				return resp, err
+			}
+		}
+	}
+	client := New(server.URL, WithToken("secret"), WithMiddleware(trace("Outer")), WithMiddleware(trace("Inner")))
+	if _, err := client.HandlePost(context.Background(), server.URL+"/api/2.0/mlflow/runs/set-tag", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Outer ", "Inner ", "Inner done", "Outer done"}
+	if len(order) != len(expected) {
+		t.Fatalf("unexpected order %v", order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("unexpected order %v", order)
+		}
+	}
+	if header.Get("X-Outer") != "1" || header.Get("X-Inner") != "1" || header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("unexpected headers %v", header)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	cases := map[string]string{
+		"http://localhost/api/2.0/mlflow/runs/log-batch":                                 "runs/log-batch",
+		"http://localhost/ajax-api/2.0/mlflow/get-trace-artifact":                        "get-trace-artifact",
+		"http://localhost/prefix/api/2.0/mlflow/experiments/get":                         "experiments/get",
+		"http://localhost/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/model.pkl": "mlflow-artifacts/artifacts",
+		"http://localhost/api/2.0/mlflow/traces":                                         "traces",
+		"http://localhost/api/2.0/mlflow/traces/search":                                  "traces/search",
+		"http://localhost/api/2.0/mlflow/traces/delete-traces":                           "traces/delete-traces",
+		"http://localhost/api/2.0/mlflow/traces/tr-1/tags":                               "traces/{id}/tags",
+		"http://localhost/api/2.0/mlflow/traces/tr-1/info":                               "traces/{id}/info",
+		"http://localhost/api/3.0/mlflow/traces/tr-1/assessments":                        "traces/{id}/assessments",
+		"http://localhost/api/3.0/mlflow/traces/tr-1/assessments/a-1":                    "traces/{id}/assessments/{id}",
+		"http://localhost/api/2.0/mlflow/logged-models/search":                           "logged-models/search",
+		"http://localhost/api/2.0/mlflow/logged-models/m-1":                              "logged-models/{id}",
+		"http://localhost/api/2.0/mlflow/logged-models/m-1/tags/stage":                   "logged-models/{id}/tags/{id}",
+		"http://localhost/api/2.0/endpoints/chat":                                        "endpoints/{id}",
+	}
+	for url, expected := range cases {
+		req, _ := http.NewRequest("GET", url, nil)
+		if operation := Operation(req); operation != expected {
+			t.Errorf("%s: got %s, want %s", url, operation, expected)
+		}
+	}
+}
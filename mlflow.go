@@ -2,11 +2,17 @@ package mlflow
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"os"
+	"sort"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,42 +20,187 @@ type Client struct {
 	// HTTP client used to communicate with the API.
 	Client  *http.Client
 	BaseUrl string
+
+	username string
+	password string
+	token    string
+	// tokenSource takes precedence over the static credentials.
+	tokenSource TokenSource
+
+	userAgent string
+	headers   http.Header
+	signer    RequestSigner
+
+	middleware []Middleware
+	limiter    *rateLimiter
+	cache      *responseCache
+	logger     Logger
+	logBodies  bool
+
+	gzipMinSize int
+	// gzipRejected is set atomically once the server refuses gzip bodies.
+	gzipRejected int32
+	// capabilities holds the *Capabilities found by ProbeCapabilities.
+	capabilities atomic.Value
+
+	timeout         time.Duration
+	artifactTimeout time.Duration
+
+	databricks bool
+	// ownTransport is set once Client and its transport are private copies
+	// that options may change.
+	ownTransport bool
 }
 
+// The types of MLflow's messages, such as Run and ModelVersion, are
+// generated from the .proto files in internal/protogen/proto.
+//go:generate go run ./internal/protogen -out types_gen.go internal/protogen/proto/service.proto internal/protogen/proto/model_registry.proto
+
 type ResponseExperiment struct {
 	Experiment Experiment `json:"experiment"`
 }
 
-type Experiment struct {
-	ExperimentId     string `json:"experiment_id"`
-	Name             string `json:"name"`
-	ArtifactLocation string `json:"artifact_location"`
-	LifecycleStage   string `json:"lifecycle_stage"`
-}
-
 type ResponseCreateExperiment struct {
 	ExperimentId string `json:"experiment_id"`
 }
 
+type ResponseSearchExperiments struct {
+	Experiments   []Experiment `json:"experiments"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+}
+
 type ResponseRun struct {
 	Run Run `json:"run"`
 }
 
-type Run struct {
-	Info RunInfo                `json:"info"`
-	Data map[string]interface{} `json:"data"`
+// Metric returns the latest value of the metric key.
+func (r *Run) Metric(key string) (float64, bool) {
+	for _, metric := range r.Data.Metrics {
+		if metric.Key == key {
+			return metric.Value, true
+		}
+	}
+	return 0, false
+}
+
+func (r *Run) Param(key string) (string, bool) {
+	for _, param := range r.Data.Params {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return "", false
+}
+
+func (r *Run) Tag(key string) (string, bool) {
+	for _, tag := range r.Data.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// Time returns Timestamp as a time.Time.
+func (m Metric) Time() time.Time {
+	return FromMillis(m.Timestamp)
+}
+
+// The tracking server encodes non-finite metric values as the strings "NaN",
+// "Infinity" and "-Infinity", which encoding/json cannot handle on float64.
+func (m *Metric) UnmarshalJSON(data []byte) error {
+	type metric Metric
+	var raw struct {
+		metric
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Metric(raw.metric)
+	if len(raw.Value) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw.Value, &s); err == nil {
+		v, err := parseFloatValue(s)
+		if err != nil {
+			return err
+		}
+		m.Value = v
+		return nil
+	}
+	return json.Unmarshal(raw.Value, &m.Value)
+}
+
+func (m Metric) MarshalJSON() ([]byte, error) {
+	type metric Metric
+	raw := struct {
+		metric
+		Value interface{} `json:"value"`
+	}{metric: metric(m), Value: floatValue(m.Value)}
+	return json.Marshal(raw)
+}
+
+// floatValue returns v in the form the tracking server expects in JSON.
+func floatValue(v float64) interface{} {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return v
 }
 
-type RunInfo struct {
-	RunUUid        string `json:"run_uuid"`
-	ExperimentId   string `json:"experiment_id"`
-	UserId         string `json:"user_id"`
-	Status         string `json:"status"`
-	StartTime      int64  `json:"start_time"`
-	EndTime        int64  `json:"end_time,omitempty"`
-	ArtifactUri    string `json:"artifact_uri"`
-	LifecycleStage string `json:"lifecycle_stage"`
-	RunId          string `json:"run_id"`
+func parseFloatValue(s string) (float64, error) {
+	switch s {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "-Infinity":
+		return math.Inf(-1), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// StartedAt returns StartTime as a time.Time.
+func (r RunInfo) StartedAt() time.Time {
+	return FromMillis(r.StartTime)
+}
+
+// EndedAt returns EndTime as a time.Time, the zero time while the run
+// hasn't ended.
+func (r RunInfo) EndedAt() time.Time {
+	return FromMillis(r.EndTime)
+}
+
+// Millis converts t to the milliseconds since the epoch MLflow uses for
+// timestamps.
+func Millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// FromMillis converts milliseconds since the epoch to a time.Time. Zero is
+// the zero time.
+func FromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+type ResponseSearchRuns struct {
+	Runs          []Run  `json:"runs"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+type ResponseGetMetricHistory struct {
+	Metrics       []Metric `json:"metrics"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
 }
 
 type ResponseRunUpdate struct {
@@ -67,6 +218,14 @@ const (
 	Uninitialized RunStatus = "UNINITIALIZED"
 )
 
+type ViewType string
+
+const (
+	ActiveOnly  ViewType = "ACTIVE_ONLY"
+	DeletedOnly ViewType = "DELETED_ONLY"
+	All         ViewType = "ALL"
+)
+
 func AddQuery(q url.Values, key string, value interface{}) {
 	switch value := value.(type) {
 	case string:
@@ -92,15 +251,93 @@ func AddQuery(q url.Values, key string, value interface{}) {
 	}
 }
 
-func New(url string) *Client {
-	return &Client{
-		Client:  http.DefaultClient,
-		BaseUrl: url,
+// New returns a client for the tracking server at url. Credentials are taken
+// from MLFLOW_TRACKING_USERNAME, MLFLOW_TRACKING_PASSWORD and
+// MLFLOW_TRACKING_TOKEN unless overridden by opts.
+func New(url string, opts ...Option) *Client {
+	p := &Client{
+		Client:   http.DefaultClient,
+		BaseUrl:  url,
+		username: os.Getenv("MLFLOW_TRACKING_USERNAME"),
+		password: os.Getenv("MLFLOW_TRACKING_PASSWORD"),
+		token:    os.Getenv("MLFLOW_TRACKING_TOKEN"),
 	}
+	p.transport().MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-func (p *Client) HandleGet(url string, params map[string]interface{}) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
+// do sends req to the tracking server through the configured middleware,
+// within the client's request timeout.
+func (p *Client) do(req *http.Request) (*http.Response, error) {
+	return p.doWithTimeout(req, p.timeout)
+}
+
+func (p *Client) doWithTimeout(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if d, ok := req.Context().Value(timeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	next := RoundTripFunc(p.send)
+	for i := len(p.middleware) - 1; i >= 0; i-- {
+		next = p.middleware[i](next)
+	}
+	if timeout <= 0 {
+		return next(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := next(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The timeout also covers reading the body.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// send adds the configured headers and credentials to req, signs it and
+// sends it once the rate limit allows.
+func (p *Client) send(req *http.Request) (*http.Response, error) {
+	if p.limiter != nil {
+		if err := p.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	for key, values := range p.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+	if p.tokenSource != nil {
+		token, err := p.tokenSource.Token()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token.authorization())
+	} else if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	} else if p.username != "" || p.password != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	if p.signer != nil {
+		if err := p.signer.SignRequest(req); err != nil {
+			return nil, err
+		}
+	}
+	return p.logRequest(req, func(req *http.Request) (*http.Response, error) {
+		resp, err := p.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		return decompressResponse(resp)
+	})
+}
+
+func (p *Client) HandleGet(ctx context.Context, url string, params map[string]interface{}) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +346,7 @@ func (p *Client) HandleGet(url string, params map[string]interface{}) ([]byte, e
 		AddQuery(q, key, value)
 	}
 	req.URL.RawQuery = q.Encode()
-	resp, err := p.Client.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -118,24 +355,101 @@ func (p *Client) HandleGet(url string, params map[string]interface{}) ([]byte, e
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return body, nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp, body)
 	}
-	return nil, nil
+	return body, nil
 }
 
-func (p *Client) HandlePost(url string, request interface{}) ([]byte, error) {
+func (p *Client) getStream(ctx context.Context, url string, params map[string]interface{}) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	for key, value := range params {
+		AddQuery(q, key, value)
+	}
+	req.URL.RawQuery = q.Encode()
+	resp, err := p.doWithTimeout(req, p.artifactTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, readError(resp)
+	}
+	return resp.Body, nil
+}
+
+func (p *Client) putStream(ctx context.Context, url string, body io.Reader, size int64) error {
+	if size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := p.doWithTimeout(req, p.artifactTimeout)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return readError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (p *Client) HandlePost(ctx context.Context, url string, request interface{}) ([]byte, error) {
+	return p.handleJSON(ctx, "POST", url, request)
+}
+
+func (p *Client) HandlePatch(ctx context.Context, url string, request interface{}) ([]byte, error) {
+	return p.handleJSON(ctx, "PATCH", url, request)
+}
+
+func (p *Client) HandleDelete(ctx context.Context, url string, request interface{}) ([]byte, error) {
+	return p.handleJSON(ctx, "DELETE", url, request)
+}
+
+func (p *Client) handleJSON(ctx context.Context, method string, url string, request interface{}) ([]byte, error) {
 	b, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
+	if p.compressRequest(len(b)) {
+		compressed, err := gzipBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.sendJSON(ctx, method, url, compressed, "gzip")
+		if !isEncodingRejected(err) {
+			return body, err
+		}
+		body, err = p.sendJSON(ctx, method, url, b, "")
+		if err == nil {
+			p.disableGzip()
+		}
+		return body, err
+	}
+	return p.sendJSON(ctx, method, url, b, "")
+}
+
+func (p *Client) sendJSON(ctx context.Context, method string, url string, b []byte, contentEncoding string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(b))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
 
-	resp, err := p.Client.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -144,15 +458,16 @@ func (p *Client) HandlePost(url string, request interface{}) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return body, nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp, body)
 	}
-	return nil, nil
+	return body, nil
 }
 
-func (p *Client) GetExperiment(experimentId string) (*Experiment, error) {
+func (p *Client) GetExperiment(ctx context.Context, experimentId string) (*Experiment, error) {
 	url := p.BaseUrl + "/api/2.0/mlflow/experiments/get"
-	body, err := p.HandleGet(url, map[string]interface{}{"experiment_id": experimentId})
+	params := map[string]interface{}{"experiment_id": experimentId}
+	body, err := p.cached(url, params, func() ([]byte, error) { return p.HandleGet(ctx, url, params) })
 	if err != nil {
 		return nil, err
 	}
@@ -164,9 +479,9 @@ func (p *Client) GetExperiment(experimentId string) (*Experiment, error) {
 	return &response.Experiment, nil
 }
 
-func (p *Client) GetExperimentsByName(name string) (*Experiment, error) {
+func (p *Client) GetExperimentsByName(ctx context.Context, name string) (*Experiment, error) {
 	url := p.BaseUrl + "/api/2.0/mlflow/experiments/get-by-name"
-	body, err := p.HandleGet(url, map[string]interface{}{"experiment_name": name})
+	body, err := p.HandleGet(ctx, url, map[string]interface{}{"experiment_name": name})
 	if err != nil {
 		return nil, err
 	}
@@ -178,9 +493,57 @@ func (p *Client) GetExperimentsByName(name string) (*Experiment, error) {
 	return &response.Experiment, nil
 }
 
-func (p *Client) CreateExperiment(name string) (*string, error) {
+// GetOrCreateExperiment returns the experiment called name, creating it if
+// it doesn't exist. When another client creates it at the same time, the
+// experiment it created is returned.
+func (p *Client) GetOrCreateExperiment(ctx context.Context, name string) (*Experiment, error) {
+	experiment, err := p.GetExperimentsByName(ctx, name)
+	if !IsNotFound(err) {
+		return experiment, err
+	}
+	experimentId, err := p.CreateExperiment(ctx, name)
+	if IsAlreadyExists(err) {
+		return p.GetExperimentsByName(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.GetExperiment(ctx, *experimentId)
+}
+
+func (p *Client) SearchExperiments(ctx context.Context, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchExperiments, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/search"
+	request := map[string]interface{}{}
+	if filter != "" {
+		request["filter"] = filter
+	}
+	if viewType != "" {
+		request["view_type"] = viewType
+	}
+	if maxResults > 0 {
+		request["max_results"] = maxResults
+	}
+	if len(orderBy) > 0 {
+		request["order_by"] = orderBy
+	}
+	if pageToken != "" {
+		request["page_token"] = pageToken
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchExperiments
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Client) CreateExperiment(ctx context.Context, name string) (*string, error) {
 	url := p.BaseUrl + "/api/2.0/mlflow/experiments/create"
-	body, err := p.HandlePost(url, map[string]interface{}{"name": name})
+	body, err := p.HandlePost(ctx, url, map[string]interface{}{"name": name})
 	if err != nil {
 		return nil, err
 	}
@@ -192,9 +555,22 @@ func (p *Client) CreateExperiment(name string) (*string, error) {
 	return &response.ExperimentId, nil
 }
 
-func (p *Client) CreateRunWithStartTime(experimentId string, startTime int64, tags []map[string]string) (*Run, error) {
+func (p *Client) SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/experiments/set-experiment-tag"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"experiment_id": experimentId, "key": key, "value": value})
+	return err
+}
+
+// CreateRun creates a run in experimentId, started now unless WithStartTime
+// is given.
+func (p *Client) CreateRun(ctx context.Context, experimentId string, opts ...RunOption) (*Run, error) {
+	spec := NewRunSpec(opts...)
 	url := p.BaseUrl + "/api/2.0/mlflow/runs/create"
-	body, err := p.HandlePost(url, map[string]interface{}{"experiment_id": experimentId, "start_time": startTime, "tags": tags})
+	request := map[string]interface{}{"experiment_id": experimentId, "start_time": Millis(spec.StartTime), "tags": spec.Tags}
+	if spec.Name != "" {
+		request["run_name"] = spec.Name
+	}
+	body, err := p.HandlePost(ctx, url, request)
 	if err != nil {
 		return nil, err
 	}
@@ -206,13 +582,45 @@ func (p *Client) CreateRunWithStartTime(experimentId string, startTime int64, ta
 	return &response.Run, nil
 }
 
-func (p *Client) CreateRun(experimentId string, tags []map[string]string) (*Run, error) {
-	return p.CreateRunWithStartTime(experimentId, time.Now().Unix(), tags)
+// CreateRunAt creates a run started at startTime.
+func (p *Client) CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...RunOption) (*Run, error) {
+	return p.CreateRun(ctx, experimentId, append(opts, WithStartTime(startTime))...)
+}
+
+// CreateRunWithStartTime creates a run started at startTime, in
+// milliseconds since the epoch.
+//
+// Deprecated: Use CreateRun with WithStartTime and WithTags.
+func (p *Client) CreateRunWithStartTime(ctx context.Context, experimentId string, startTime int64, tags []map[string]string) (*Run, error) {
+	return p.CreateRun(ctx, experimentId, WithStartTime(FromMillis(startTime)), withTagMaps(tags))
+}
+
+// Deprecated: Use CreateRun with WithRunName and WithTags.
+func (p *Client) CreateRunWithName(ctx context.Context, experimentId string, runName string, tags []map[string]string) (*Run, error) {
+	return p.CreateRun(ctx, experimentId, WithRunName(runName), withTagMaps(tags))
 }
 
-func (p *Client) UpdateRunWithEndTime(runId string, status RunStatus, endTime int64) (*RunInfo, error) {
+// Deprecated: Use CreateRun with WithParentRun and WithTags.
+func (p *Client) CreateChildRun(ctx context.Context, parentRunId string, experimentId string, tags []map[string]string) (*Run, error) {
+	return p.CreateRun(ctx, experimentId, WithParentRun(parentRunId), withTagMaps(tags))
+}
+
+// withTagMaps adds tags given as {"key": ..., "value": ...} maps.
+func withTagMaps(tags []map[string]string) RunOption {
+	return func(o *runOptions) {
+		for _, tag := range tags {
+			o.tags = append(o.tags, RunTag{Key: tag["key"], Value: tag["value"]})
+		}
+	}
+}
+
+func (p *Client) updateRun(ctx context.Context, runId string, status RunStatus, runName string, endTime int64) (*RunInfo, error) {
 	url := p.BaseUrl + "/api/2.0/mlflow/runs/update"
-	body, err := p.HandlePost(url, map[string]interface{}{"run_id": runId, "status": status, "end_time": endTime})
+	request := map[string]interface{}{"run_id": runId, "status": status, "end_time": endTime}
+	if runName != "" {
+		request["run_name"] = runName
+	}
+	body, err := p.HandlePost(ctx, url, request)
 	if err != nil {
 		return nil, err
 	}
@@ -224,19 +632,48 @@ func (p *Client) UpdateRunWithEndTime(runId string, status RunStatus, endTime in
 	return &response.Info, nil
 }
 
-func (p *Client) UpdateRun(runId string, status RunStatus) (*RunInfo, error) {
-	return p.UpdateRunWithEndTime(runId, status, time.Now().Unix())
+// UpdateRunWithEndTime sets the status of a run and its end time, in
+// milliseconds since the epoch.
+func (p *Client) UpdateRunWithEndTime(ctx context.Context, runId string, status RunStatus, endTime int64) (*RunInfo, error) {
+	return p.updateRun(ctx, runId, status, "", endTime)
+}
+
+// UpdateRunAt sets the status of a run and its end time.
+func (p *Client) UpdateRunAt(ctx context.Context, runId string, status RunStatus, endTime time.Time) (*RunInfo, error) {
+	return p.UpdateRunWithEndTime(ctx, runId, status, Millis(endTime))
+}
+
+func (p *Client) UpdateRunWithName(ctx context.Context, runId string, status RunStatus, runName string) (*RunInfo, error) {
+	return p.updateRun(ctx, runId, status, runName, Millis(time.Now()))
 }
 
-func (p *Client) DeleteRun(runId string) error {
+func (p *Client) UpdateRun(ctx context.Context, runId string, status RunStatus) (*RunInfo, error) {
+	return p.UpdateRunAt(ctx, runId, status, time.Now())
+}
+
+func (p *Client) DeleteRun(ctx context.Context, runId string) error {
 	url := p.BaseUrl + "/api/2.0/mlflow/runs/delete"
-	_, err := p.HandlePost(url, map[string]interface{}{"run_id": runId})
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"run_id": runId})
 	return err
 }
 
-func (p *Client) GetRun(runId string) (*Run, error) {
+func (p *Client) GetRun(ctx context.Context, runId string) (*Run, error) {
+	return p.getRun(ctx, runId, true)
+}
+
+// getRun fetches a run, bypassing the cache unless useCache, for the
+// read-modify-write of run tags.
+func (p *Client) getRun(ctx context.Context, runId string, useCache bool) (*Run, error) {
 	url := p.BaseUrl + "/api/2.0/mlflow/runs/get"
-	body, err := p.HandleGet(url, map[string]interface{}{"run_id": runId})
+	params := map[string]interface{}{"run_id": runId}
+	fetch := func() ([]byte, error) { return p.HandleGet(ctx, url, params) }
+	var body []byte
+	var err error
+	if useCache {
+		body, err = p.cached(url, params, fetch)
+	} else {
+		body, err = fetch()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -247,3 +684,182 @@ func (p *Client) GetRun(runId string) (*Run, error) {
 	}
 	return &response.Run, nil
 }
+
+func (p *Client) LogInputs(ctx context.Context, runId string, datasets []DatasetInput) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/log-inputs"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"run_id": runId, "datasets": datasets})
+	return err
+}
+
+func (p *Client) SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType ViewType, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRuns, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/search"
+	request := map[string]interface{}{"experiment_ids": experimentIds}
+	if filter != "" {
+		request["filter"] = filter
+	}
+	if viewType != "" {
+		request["run_view_type"] = viewType
+	}
+	if maxResults > 0 {
+		request["max_results"] = maxResults
+	}
+	if len(orderBy) > 0 {
+		request["order_by"] = orderBy
+	}
+	if pageToken != "" {
+		request["page_token"] = pageToken
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchRuns
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Client) ListChildRuns(ctx context.Context, parentRunId string) ([]Run, error) {
+	parent, err := p.GetRun(ctx, parentRunId)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := Filter().Tag(TagParentRunId).Eq(parentRunId).Build()
+	if err != nil {
+		return nil, err
+	}
+	return p.IterRuns(ctx, []string{parent.Info.ExperimentId}, filter, ActiveOnly, nil).All()
+}
+
+func (p *Client) SetTag(ctx context.Context, runId string, key string, value string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/set-tag"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"run_id": runId, "key": key, "value": value})
+	return err
+}
+
+// LogMetric logs a metric value at timestamp, in milliseconds since the
+// epoch, and step.
+func (p *Client) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/log-metric"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"run_id": runId, "key": key, "value": floatValue(value), "timestamp": timestamp, "step": step})
+	return err
+}
+
+// LogMetricAt logs a metric value at step, timestamped t.
+func (p *Client) LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error {
+	return p.LogMetric(ctx, runId, key, value, Millis(t), step)
+}
+
+func (p *Client) LogParam(ctx context.Context, runId string, key string, value string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/log-parameter"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"run_id": runId, "key": key, "value": value})
+	return err
+}
+
+func (p *Client) GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*ResponseGetMetricHistory, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/metrics/get-history"
+	params := map[string]interface{}{"run_id": runId, "metric_key": metricKey}
+	if maxResults > 0 {
+		params["max_results"] = maxResults
+	}
+	if pageToken != "" {
+		params["page_token"] = pageToken
+	}
+	body, err := p.HandleGet(ctx, url, params)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseGetMetricHistory
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Limits on a single runs/log-batch request.
+const (
+	maxBatchMetrics = 1000
+	maxBatchParams  = 100
+	maxBatchTags    = 100
+	maxBatchEntries = 1000
+)
+
+// LogBatch logs metrics, params and tags together. Batches larger than the
+// server accepts in one request are split into several requests.
+func (p *Client) LogBatch(ctx context.Context, runId string, metrics []Metric, params []Param, tags []RunTag) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/runs/log-batch"
+	return splitBatch(metrics, params, tags, func(metrics []Metric, params []Param, tags []RunTag) error {
+		request := map[string]interface{}{"run_id": runId, "metrics": metrics, "params": params, "tags": tags}
+		_, err := p.HandlePost(ctx, url, request)
+		return err
+	})
+}
+
+// splitBatch calls send with chunks of the batch small enough for one
+// log-batch request, in order, until one fails. An empty batch is one empty
+// chunk.
+func splitBatch(metrics []Metric, params []Param, tags []RunTag, send func(metrics []Metric, params []Param, tags []RunTag) error) error {
+	for {
+		nParams := len(params)
+		if nParams > maxBatchParams {
+			nParams = maxBatchParams
+		}
+		nTags := len(tags)
+		if nTags > maxBatchTags {
+			nTags = maxBatchTags
+		}
+		nMetrics := len(metrics)
+		if nMetrics > maxBatchEntries-nParams-nTags {
+			nMetrics = maxBatchEntries - nParams - nTags
+		}
+		if nMetrics > maxBatchMetrics {
+			nMetrics = maxBatchMetrics
+		}
+		if err := send(metrics[:nMetrics], params[:nParams], tags[:nTags]); err != nil {
+			return err
+		}
+		metrics, params, tags = metrics[nMetrics:], params[nParams:], tags[nTags:]
+		if len(metrics) == 0 && len(params) == 0 && len(tags) == 0 {
+			return nil
+		}
+	}
+}
+
+// LogMetrics logs the metrics at step, timestamped now, in as few requests
+// as possible.
+func (p *Client) LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error {
+	timestamp := Millis(time.Now())
+	batch := make([]Metric, 0, len(metrics))
+	for _, key := range sortedKeys(metrics) {
+		batch = append(batch, Metric{Key: key, Value: metrics[key], Timestamp: timestamp, Step: step})
+	}
+	return p.LogBatch(ctx, runId, batch, nil, nil)
+}
+
+func (p *Client) LogParams(ctx context.Context, runId string, params map[string]string) error {
+	batch := make([]Param, 0, len(params))
+	for _, key := range sortedKeys(params) {
+		batch = append(batch, Param{Key: key, Value: params[key]})
+	}
+	return p.LogBatch(ctx, runId, nil, batch, nil)
+}
+
+func (p *Client) SetTags(ctx context.Context, runId string, tags map[string]string) error {
+	batch := make([]RunTag, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		batch = append(batch, RunTag{Key: key, Value: tags[key]})
+	}
+	return p.LogBatch(ctx, runId, nil, nil, batch)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
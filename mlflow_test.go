@@ -1,16 +1,258 @@
-package mlflow
+package mlflow_test
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
 
 func TestGetExperiment(t *testing.T) {
-	client := New("http://localhost:5000")
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
 	t.Run("GetExperiment", func(t *testing.T) {
-		t.Log("Test GetExperiment")
-		experimentId, _ := client.CreateExperiment("test4")
-		t.Log(experimentId)
-		experiment, _ := client.GetExperiment(*experimentId)
-		if experiment.ExperimentId != *experimentId {
-			t.Errorf("Expected experiment id 1, got %s", experiment.ExperimentId)
+		experimentId, err := client.CreateExperiment(context.Background(), "test4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		experiment, err := client.GetExperiment(context.Background(), *experimentId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if experiment.ExperimentId != *experimentId || experiment.Name != "test4" {
+			t.Errorf("Expected experiment %s, got %+v", *experimentId, experiment)
 		}
 	})
 }
+
+func TestGetRunData(t *testing.T) {
+	recorded, err := os.ReadFile("testdata/runs_get.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/mlflow/runs/get" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write(recorded)
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	run, err := client.GetRun(context.Background(), "0a1b2c3d4e5f4a6b8c9d0e1f2a3b4c5d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.RunName != "bustling-owl-42" {
+		t.Errorf("Expected run name bustling-owl-42, got %s", run.Info.RunName)
+	}
+	if len(run.Data.Metrics) != 2 || run.Data.Metrics[0].Key != "rmse" || run.Data.Metrics[0].Value != 0.25 || run.Data.Metrics[0].Step != 10 {
+		t.Errorf("unexpected metrics %+v", run.Data.Metrics)
+	}
+	if !math.IsNaN(run.Data.Metrics[1].Value) {
+		t.Errorf("Expected NaN metric value, got %v", run.Data.Metrics[1].Value)
+	}
+	if len(run.Data.Params) != 2 || run.Data.Params[1].Key != "l1_ratio" || run.Data.Params[1].Value != "0.1" {
+		t.Errorf("unexpected params %+v", run.Data.Params)
+	}
+	if len(run.Data.Tags) != 2 || run.Data.Tags[0].Key != "mlflow.user" {
+		t.Errorf("unexpected tags %+v", run.Data.Tags)
+	}
+	if rmse, ok := run.Metric("rmse"); !ok || rmse != 0.25 {
+		t.Errorf("unexpected rmse %v %v", rmse, ok)
+	}
+	if alpha, ok := run.Param("alpha"); !ok || alpha != "0.5" {
+		t.Errorf("unexpected alpha %q %v", alpha, ok)
+	}
+	if user, ok := run.Tag(mlflow.TagUser); !ok || user != "mlflow" {
+		t.Errorf("unexpected user %q %v", user, ok)
+	}
+	if _, ok := run.Metric("accuracy"); ok {
+		t.Error("expected no accuracy metric")
+	}
+	b, err := json.Marshal(run.Data.Metrics[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"value":"NaN"`) {
+		t.Errorf("Expected NaN to be encoded as a string, got %s", b)
+	}
+}
+
+func TestRunTimestamps(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/create":
+			w.Write([]byte(`{"run": {"info": {"run_id": "run1", "start_time": 1700000000123}}}`))
+		default:
+			w.Write([]byte(`{"run_info": {"run_id": "run1", "start_time": 1700000000123, "end_time": 1700000060000}}`))
+		}
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	before := time.Now().UnixMilli()
+	if _, err := client.CreateRun(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := client.UpdateRun(ctx, "run1", mlflow.Finished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().UnixMilli()
+	for i, key := range []string{"start_time", "end_time"} {
+		if ms := int64(requests[i][key].(float64)); ms < before || ms > after {
+			t.Errorf("expected %s in milliseconds, got %d", key, ms)
+		}
+	}
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	if _, err := client.CreateRunAt(ctx, "1", start); err != nil {
+		t.Fatal(err)
+	}
+	if ms := int64(requests[2]["start_time"].(float64)); ms != 1709294400500 {
+		t.Errorf("unexpected start time %d", ms)
+	}
+	if !info.StartedAt().Equal(time.UnixMilli(1700000000123)) || info.EndedAt().Sub(info.StartedAt()) != 59877*time.Millisecond {
+		t.Errorf("unexpected run times %v %v", info.StartedAt(), info.EndedAt())
+	}
+	if !mlflow.FromMillis(0).IsZero() || mlflow.Millis(start) != 1709294400500 {
+		t.Error("unexpected millisecond conversion")
+	}
+}
+
+func TestGetOrCreateExperiment(t *testing.T) {
+	existing := map[string]string{"nlp": "1"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/experiments/get-by-name":
+			name := r.URL.Query().Get("experiment_name")
+			if id, ok := existing[name]; ok {
+				w.Write([]byte(`{"experiment": {"experiment_id": "` + id + `", "name": "` + name + `"}}`))
+				return
+			}
+			if name == "racy" {
+				// Another client creates it before our create call.
+				existing[name] = "3"
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no experiment"}`))
+		case "/api/2.0/mlflow/experiments/create":
+			var request map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request["name"] == "racy" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error_code": "RESOURCE_ALREADY_EXISTS", "message": "exists"}`))
+				return
+			}
+			w.Write([]byte(`{"experiment_id": "2"}`))
+		case "/api/2.0/mlflow/experiments/get":
+			w.Write([]byte(`{"experiment": {"experiment_id": "2", "name": "vision"}}`))
+		}
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	for name, expected := range map[string]string{"nlp": "1", "vision": "2", "racy": "3"} {
+		experiment, err := client.GetOrCreateExperiment(ctx, name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if experiment.ExperimentId != expected || experiment.Name != name {
+			t.Errorf("%s: unexpected experiment %+v", name, experiment)
+		}
+	}
+}
+
+func TestLogMaps(t *testing.T) {
+	var requests []mlflow.RunData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request mlflow.RunData
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	if err := client.LogMetrics(ctx, "run1", map[string]float64{"rmse": 0.5, "mae": 0.25, "r2": 0.9}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LogParams(ctx, "run1", map[string]string{"lr": "0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetTags(ctx, "run1", map[string]string{"team": "nlp", "stage": "eval"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected one request per call, got %d", len(requests))
+	}
+	metrics := requests[0].Metrics
+	if len(metrics) != 3 || metrics[0].Key != "mae" || metrics[2].Key != "rmse" || metrics[1].Step != 3 || metrics[0].Timestamp != metrics[2].Timestamp {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+	if params := requests[1].Params; len(params) != 1 || params[0].Value != "0.1" {
+		t.Errorf("unexpected params %+v", params)
+	}
+	if tags := requests[2].Tags; len(tags) != 2 || tags[0].Key != "stage" {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+}
+
+func TestCreateRunOptions(t *testing.T) {
+	var request struct {
+		ExperimentId string          `json:"experiment_id"`
+		RunName      string          `json:"run_name"`
+		StartTime    int64           `json:"start_time"`
+		Tags         []mlflow.RunTag `json:"tags"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"run": {"info": {"run_id": "run1"}}}`))
+	}))
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	start := time.UnixMilli(1700000000000)
+	_, err := client.CreateRun(ctx, "1",
+		mlflow.WithRunName("train"),
+		mlflow.WithStartTime(start),
+		mlflow.WithTags(map[string]string{"team": "nlp", "stage": "dev"}),
+		mlflow.WithParentRun("parent"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []mlflow.RunTag{{Key: "stage", Value: "dev"}, {Key: "team", Value: "nlp"}, {Key: mlflow.TagParentRunId, Value: "parent"}}
+	if request.ExperimentId != "1" || request.RunName != "train" || request.StartTime != 1700000000000 || len(request.Tags) != len(expected) {
+		t.Fatalf("unexpected request %+v", request)
+	}
+	for i := range expected {
+		if request.Tags[i] != expected[i] {
+			t.Errorf("tag %d: got %v, want %v", i, request.Tags[i], expected[i])
+		}
+	}
+
+	if _, err := client.CreateChildRun(ctx, "parent", "1", []map[string]string{{"key": "team", "value": "nlp"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(request.Tags) != 2 || request.Tags[0].Key != "team" || request.Tags[1].Value != "parent" {
+		t.Errorf("unexpected tags %+v", request.Tags)
+	}
+}
@@ -0,0 +1,160 @@
+package mlflowfake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"gopkg.in/yaml.v3"
+)
+
+// putArtifact stores data at artifactPath, relative to the artifact root of
+// the run.
+func (s *Store) putArtifact(runId string, data []byte, artifactPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.activeRun(runId)
+	if err != nil {
+		return err
+	}
+	r.artifacts[strings.Trim(path.Clean("/"+artifactPath), "/")] = data
+	return nil
+}
+
+func (s *Store) LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error {
+	data, err := ioutil.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+	return s.putArtifact(runId, data, path.Join(artifactPath, filepath.Base(localPath)))
+}
+
+func (s *Store) LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error {
+	return filepath.Walk(localDir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(localPath)
+		if err != nil {
+			return err
+		}
+		return s.putArtifact(runId, data, path.Join(artifactPath, filepath.ToSlash(rel)))
+	})
+}
+
+// LogDict serializes obj as mlflow.Client does: YAML for .yaml and .yml
+// files, indented JSON otherwise.
+func (s *Store) LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path.Ext(artifactFile)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+	}
+	return s.putArtifact(runId, data, artifactFile)
+}
+
+func (s *Store) LogText(ctx context.Context, runId string, text string, artifactFile string) error {
+	return s.putArtifact(runId, []byte(text), artifactFile)
+}
+
+// ListArtifacts lists the files and directories directly under dir.
+func (s *Store) ListArtifacts(ctx context.Context, runId string, dir string) ([]mlflow.FileInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.runState(runId)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(dir, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	entries := map[string]*mlflow.FileInfo{}
+	for artifactPath, data := range r.artifacts {
+		if !strings.HasPrefix(artifactPath, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(artifactPath, prefix)
+		if i := strings.Index(name, "/"); i >= 0 {
+			entries[prefix+name[:i]] = &mlflow.FileInfo{Path: prefix + name[:i], IsDir: true}
+			continue
+		}
+		entries[artifactPath] = &mlflow.FileInfo{Path: artifactPath, FileSize: int64(len(data))}
+	}
+	var files []mlflow.FileInfo
+	for _, entry := range entries {
+		files = append(files, *entry)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func (s *Store) OpenArtifact(ctx context.Context, runId string, artifactPath string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.runState(runId)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := r.artifacts[strings.Trim(artifactPath, "/")]
+	if !ok {
+		return nil, notFound("Artifact %s of run %s not found", artifactPath, runId)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// DownloadArtifacts writes the file at remotePath, or every file under it,
+// to the same relative path under localDir. Download options are ignored.
+func (s *Store) DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...mlflow.DownloadOption) error {
+	s.mu.Lock()
+	r, err := s.runState(runId)
+	var files map[string][]byte
+	if err == nil {
+		prefix := strings.Trim(remotePath, "/")
+		files = map[string][]byte{}
+		for artifactPath, data := range r.artifacts {
+			if prefix == "" || artifactPath == prefix || strings.HasPrefix(artifactPath, prefix+"/") {
+				files[artifactPath] = data
+			}
+		}
+		if len(files) == 0 {
+			err = notFound("Artifact %s of run %s not found", remotePath, runId)
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for artifactPath, data := range files {
+		localPath := filepath.Join(localDir, filepath.FromSlash(artifactPath))
+		if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(localPath, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
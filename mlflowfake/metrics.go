@@ -0,0 +1,117 @@
+package mlflowfake
+
+import (
+	"context"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+func (s *Store) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	return s.LogBatch(ctx, runId, []mlflow.Metric{{Key: key, Value: value, Timestamp: timestamp, Step: step}}, nil, nil)
+}
+
+func (s *Store) LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error {
+	return s.LogMetric(ctx, runId, key, value, mlflow.Millis(t), step)
+}
+
+func (s *Store) LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error {
+	timestamp := mlflow.Millis(time.Now())
+	var batch []mlflow.Metric
+	for key, value := range metrics {
+		batch = append(batch, mlflow.Metric{Key: key, Value: value, Timestamp: timestamp, Step: step})
+	}
+	return s.LogBatch(ctx, runId, batch, nil, nil)
+}
+
+func (s *Store) LogParam(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, []mlflow.Param{{Key: key, Value: value}}, nil)
+}
+
+func (s *Store) LogParams(ctx context.Context, runId string, params map[string]string) error {
+	var batch []mlflow.Param
+	for key, value := range params {
+		batch = append(batch, mlflow.Param{Key: key, Value: value})
+	}
+	return s.LogBatch(ctx, runId, nil, batch, nil)
+}
+
+func (s *Store) LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error {
+	params, err := mlflow.FlattenParams(cfg)
+	if err != nil {
+		return err
+	}
+	return s.LogBatch(ctx, runId, nil, params, nil)
+}
+
+// LogBatch logs to a run atomically: when a param would change the value it
+// was logged with, nothing is logged and INVALID_PARAMETER_VALUE is returned,
+// as the tracking server does.
+func (s *Store) LogBatch(ctx context.Context, runId string, metrics []mlflow.Metric, params []mlflow.Param, tags []mlflow.RunTag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.activeRun(runId)
+	if err != nil {
+		return err
+	}
+	run := mlflow.Run{Data: r.run.Data}
+	var newParams []mlflow.Param
+	for _, param := range params {
+		if value, ok := run.Param(param.Key); ok {
+			if value != param.Value {
+				return invalidParameter("Changing param values is not allowed. Param with key='%s' was already logged with value='%s' for run ID='%s'. Attempted logging new value '%s'.", param.Key, value, runId, param.Value)
+			}
+			continue
+		}
+		run.Data.Params = append(run.Data.Params, param)
+		newParams = append(newParams, param)
+	}
+	r.run.Data.Params = append(r.run.Data.Params, newParams...)
+	for _, metric := range metrics {
+		r.history[metric.Key] = append(r.history[metric.Key], metric)
+		setLatestMetric(r, metric)
+	}
+	for _, tag := range tags {
+		setRunTag(r, tag)
+		switch tag.Key {
+		case mlflow.TagUser:
+			r.run.Info.UserId = tag.Value
+		case mlflow.TagRunName:
+			r.run.Info.RunName = tag.Value
+		}
+	}
+	return nil
+}
+
+// setLatestMetric keeps the value at the highest step, and the latest
+// timestamp within a step, as the metric value of the run.
+func setLatestMetric(r *runState, metric mlflow.Metric) {
+	metrics := r.run.Data.Metrics
+	for i := range metrics {
+		if metrics[i].Key != metric.Key {
+			continue
+		}
+		latest := metrics[i]
+		if metric.Step > latest.Step || metric.Step == latest.Step && metric.Timestamp >= latest.Timestamp {
+			metrics[i] = metric
+		}
+		return
+	}
+	r.run.Data.Metrics = append(metrics, metric)
+}
+
+func (s *Store) GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*mlflow.ResponseGetMetricHistory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.runState(runId)
+	if err != nil {
+		return nil, err
+	}
+	history := append([]mlflow.Metric(nil), r.history[metricKey]...)
+	page, next, err := search.Page(history, maxResults, pageToken, 25000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseGetMetricHistory{Metrics: page, NextPageToken: next}, nil
+}
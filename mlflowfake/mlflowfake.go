@@ -0,0 +1,197 @@
+// Package mlflowfake is an in-memory implementation of mlflow.API for unit
+// tests of code that tracks experiments or uses the model registry. It keeps
+// experiments, runs with their metric histories and artifacts, registered
+// models with their versions, and prompts, and returns the same errors as a
+// tracking server, so mlflow.IsNotFound and friends work on them. Traces and
+// webhooks are not implemented.
+package mlflowfake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+)
+
+// Store is the fake. The zero value is not usable; create it with New.
+type Store struct {
+	mlflow.UnimplementedAPI
+
+	mu          sync.Mutex
+	experiments []*mlflow.Experiment
+	runs        []*runState
+	models      []*modelState
+	nextRunId   int
+}
+
+type runState struct {
+	run       mlflow.Run
+	history   map[string][]mlflow.Metric
+	artifacts map[string][]byte
+}
+
+var _ mlflow.API = (*Store)(nil)
+
+// New returns an empty store holding only the Default experiment with id
+// "0", as a new tracking server does.
+func New() *Store {
+	s := &Store{}
+	s.experiments = append(s.experiments, newExperiment("0", "Default"))
+	return s
+}
+
+func newExperiment(id string, name string) *mlflow.Experiment {
+	now := mlflow.Millis(time.Now())
+	return &mlflow.Experiment{
+		ExperimentId:     id,
+		Name:             name,
+		ArtifactLocation: "mlflow-artifacts:/" + id,
+		LifecycleStage:   "active",
+		CreationTime:     now,
+		LastUpdateTime:   now,
+	}
+}
+
+func newError(statusCode int, errorCode string, format string, args ...interface{}) error {
+	return &mlflow.Error{StatusCode: statusCode, ErrorCode: errorCode, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return newError(http.StatusNotFound, mlflow.ErrorCodeResourceDoesNotExist, format, args...)
+}
+
+func invalidParameter(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeInvalidParameterValue, format, args...)
+}
+
+func alreadyExists(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeResourceAlreadyExists, format, args...)
+}
+
+func copyExperiment(e *mlflow.Experiment) *mlflow.Experiment {
+	c := *e
+	c.Tags = append([]mlflow.ExperimentTag(nil), e.Tags...)
+	return &c
+}
+
+func (s *Store) experiment(experimentId string) (*mlflow.Experiment, error) {
+	for _, e := range s.experiments {
+		if e.ExperimentId == experimentId {
+			return e, nil
+		}
+	}
+	return nil, notFound("No Experiment with id=%s exists", experimentId)
+}
+
+func (s *Store) GetExperiment(ctx context.Context, experimentId string) (*mlflow.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.experiment(experimentId)
+	if err != nil {
+		return nil, err
+	}
+	return copyExperiment(e), nil
+}
+
+func (s *Store) GetExperimentsByName(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.experiments {
+		if e.Name == name {
+			return copyExperiment(e), nil
+		}
+	}
+	return nil, notFound("Could not find experiment with name '%s'", name)
+}
+
+func (s *Store) GetOrCreateExperiment(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	experiment, err := s.GetExperimentsByName(ctx, name)
+	if !mlflow.IsNotFound(err) {
+		return experiment, err
+	}
+	experimentId, err := s.CreateExperiment(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExperiment(ctx, *experimentId)
+}
+
+func (s *Store) SearchExperiments(ctx context.Context, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchExperiments, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []mlflow.Experiment
+	for _, e := range s.experiments {
+		if store.InView(e.LifecycleStage, viewType) && f.Match(store.ExperimentLookup(e)) {
+			matched = append(matched, *copyExperiment(e))
+		}
+	}
+	store.SortExperiments(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchExperiments{Experiments: page, NextPageToken: next}, nil
+}
+
+func (s *Store) CreateExperiment(ctx context.Context, name string) (*string, error) {
+	if name == "" {
+		return nil, invalidParameter("Invalid experiment name: ''")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.experiments {
+		if e.Name == name {
+			return nil, alreadyExists("Experiment '%s' already exists.", name)
+		}
+	}
+	id := fmt.Sprint(len(s.experiments))
+	s.experiments = append(s.experiments, newExperiment(id, name))
+	return &id, nil
+}
+
+func (s *Store) SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.experiment(experimentId)
+	if err != nil {
+		return err
+	}
+	for i := range e.Tags {
+		if e.Tags[i].Key == key {
+			e.Tags[i].Value = value
+			return nil
+		}
+	}
+	e.Tags = append(e.Tags, mlflow.ExperimentTag{Key: key, Value: value})
+	return nil
+}
+
+func (s *Store) SetExperimentNote(ctx context.Context, experimentId string, note string) error {
+	return s.SetExperimentTag(ctx, experimentId, mlflow.TagNote, note)
+}
+
+// DefaultExperimentId resolves MLFLOW_EXPERIMENT_NAME and
+// MLFLOW_EXPERIMENT_ID as mlflow.Client does.
+func (s *Store) DefaultExperimentId(ctx context.Context) (string, error) {
+	if name := os.Getenv("MLFLOW_EXPERIMENT_NAME"); name != "" {
+		experiment, err := s.GetOrCreateExperiment(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return experiment.ExperimentId, nil
+	}
+	if experimentId := os.Getenv("MLFLOW_EXPERIMENT_ID"); experimentId != "" {
+		return experimentId, nil
+	}
+	return "0", nil
+}
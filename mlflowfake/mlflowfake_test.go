@@ -0,0 +1,237 @@
+package mlflowfake
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+// train stands for application code that only depends on mlflow.API.
+func train(ctx context.Context, api mlflow.API, experimentName string, lr float64) (string, error) {
+	experiment, err := api.GetOrCreateExperiment(ctx, experimentName)
+	if err != nil {
+		return "", err
+	}
+	run, err := api.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("train"), mlflow.WithTags(map[string]string{"team": "ml"}))
+	if err != nil {
+		return "", err
+	}
+	runId := run.Info.RunId
+	if err := api.LogParams(ctx, runId, map[string]string{"lr": "0.1"}); err != nil {
+		return "", err
+	}
+	for step := int64(0); step < 3; step++ {
+		if err := api.LogMetric(ctx, runId, "loss", lr/float64(step+1), int64(1000+step), step); err != nil {
+			return "", err
+		}
+	}
+	if _, err := api.UpdateRun(ctx, runId, mlflow.Finished); err != nil {
+		return "", err
+	}
+	return runId, nil
+}
+
+func TestRuns(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	runId, err := train(ctx, store, "exp", 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := store.GetRun(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.RunName != "train" || run.Info.Status != string(mlflow.Finished) || run.Info.EndTime == 0 {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if loss, _ := run.Metric("loss"); loss != 0.25 {
+		t.Errorf("expected the latest loss 0.25, got %v", loss)
+	}
+	if team, _ := run.Tag("team"); team != "ml" {
+		t.Errorf("expected tag team=ml, got %q", team)
+	}
+	if name, _ := run.Tag(mlflow.TagRunName); name != "train" {
+		t.Errorf("expected run name tag, got %q", name)
+	}
+	history, err := store.GetMetricHistory(ctx, runId, "loss", 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Metrics) != 2 || history.NextPageToken == "" {
+		t.Fatalf("unexpected first page %+v", history)
+	}
+	history, err = store.GetMetricHistory(ctx, runId, "loss", 2, history.NextPageToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Metrics) != 1 || history.Metrics[0].Step != 2 || history.NextPageToken != "" {
+		t.Errorf("unexpected second page %+v", history)
+	}
+
+	err = store.LogParam(ctx, runId, "lr", "0.2")
+	if !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected changing a param to fail, got %v", err)
+	}
+	if err := store.LogParam(ctx, runId, "lr", "0.1"); err != nil {
+		t.Errorf("expected logging the same value again to succeed, got %v", err)
+	}
+	_, err = store.GetRun(ctx, "missing")
+	if !mlflow.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestExperiments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	experiment, err := store.GetExperimentsByName(ctx, "Default")
+	if err != nil || experiment.ExperimentId != "0" {
+		t.Fatalf("expected the Default experiment, got %+v, %v", experiment, err)
+	}
+	experimentId, err := store.CreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.CreateExperiment(ctx, "exp")
+	if !mlflow.IsAlreadyExists(err) {
+		t.Errorf("expected already exists, got %v", err)
+	}
+	if err := store.SetExperimentNote(ctx, *experimentId, "notes"); err != nil {
+		t.Fatal(err)
+	}
+	response, err := store.SearchExperiments(ctx, "name LIKE 'ex%'", mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Experiments) != 1 || response.Experiments[0].Tags[0].Value != "notes" {
+		t.Errorf("unexpected search result %+v", response.Experiments)
+	}
+}
+
+func TestSearchRuns(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	start := time.Unix(1000, 0)
+	var runIds []string
+	for i, rmse := range []float64{0.3, 0.1, 0.2} {
+		run, err := store.CreateRunAt(ctx, "0", start.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, run.Info.RunId)
+		if err := store.LogMetric(ctx, run.Info.RunId, "rmse", rmse, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	child, err := store.CreateRun(ctx, "0", mlflow.WithParentRun(runIds[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteRun(ctx, runIds[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := store.SearchRuns(ctx, []string{"0"}, "", mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 3 || response.Runs[0].Info.RunId != child.Info.RunId {
+		t.Errorf("expected active runs newest first, got %+v", response.Runs)
+	}
+	response, err = store.SearchRuns(ctx, []string{"0"}, "metrics.rmse < 0.25", mlflow.All, 0, []string{"metrics.rmse ASC"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 2 || response.Runs[0].Info.RunId != runIds[1] || response.Runs[1].Info.RunId != runIds[2] {
+		t.Errorf("unexpected runs %+v", response.Runs)
+	}
+	children, err := store.ListChildRuns(ctx, runIds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1 || children[0].Info.RunId != child.Info.RunId {
+		t.Errorf("unexpected children %+v", children)
+	}
+	_, err = store.SearchRuns(ctx, []string{"0"}, "metrics.rmse <", mlflow.All, 0, nil, "")
+	if !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected an invalid filter to fail, got %v", err)
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	run, err := store.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := store.LogText(ctx, runId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.LogDict(ctx, runId, map[string]int{"a": 1}, "config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := store.ListArtifacts(ctx, runId, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "config.yaml" || !files[1].IsDir || files[1].Path != "notes" {
+		t.Errorf("unexpected artifacts %+v", files)
+	}
+	r, err := store.OpenArtifact(ctx, runId, "config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if b, _ := ioutil.ReadAll(r); string(b) != "a: 1\n" {
+		t.Errorf("unexpected yaml %q", b)
+	}
+
+	dir := t.TempDir()
+	if err := store.DownloadArtifacts(ctx, runId, "notes", dir); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+	_, err = store.OpenArtifact(ctx, runId, "missing")
+	if !mlflow.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	_, err := New().GetTrace(context.Background(), "tr-1")
+	if !mlflow.IsNotImplemented(err) {
+		t.Errorf("expected traces to be unimplemented, got %v", err)
+	}
+}
+
+func TestListChildRunsPages(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	parent, err := store.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// More children than SearchRuns returns in one page.
+	for i := 0; i < 1001; i++ {
+		if _, err := store.CreateRun(ctx, "0", mlflow.WithParentRun(parent.Info.RunId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	children, err := store.ListChildRuns(ctx, parent.Info.RunId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1001 {
+		t.Errorf("expected 1001 children, got %d", len(children))
+	}
+}
@@ -0,0 +1,166 @@
+package mlflowfake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+// Prompts are kept in the registry as mlflow.Client keeps them: registered
+// models and model versions marked by mlflow.TagIsPrompt.
+
+func isPrompt(model *mlflow.RegisteredModel) bool {
+	for _, tag := range model.Tags {
+		if tag.Key == mlflow.TagIsPrompt {
+			return tag.Value == "true"
+		}
+	}
+	return false
+}
+
+func newPrompt(model *mlflow.RegisteredModel) *mlflow.Prompt {
+	prompt := &mlflow.Prompt{
+		Name:                 model.Name,
+		Description:          model.Description,
+		CreationTimestamp:    model.CreationTimestamp,
+		LastUpdatedTimestamp: model.LastUpdatedTimestamp,
+		Tags:                 map[string]string{},
+		Aliases:              map[string]string{},
+	}
+	for _, tag := range model.Tags {
+		if tag.Key != mlflow.TagIsPrompt {
+			prompt.Tags[tag.Key] = tag.Value
+		}
+	}
+	for _, alias := range model.Aliases {
+		prompt.Aliases[alias.Alias] = alias.Version
+	}
+	return prompt
+}
+
+func newPromptVersion(version *mlflow.ModelVersion) (*mlflow.PromptVersion, error) {
+	prompt := &mlflow.PromptVersion{
+		Name:                 version.Name,
+		Version:              version.Version,
+		CommitMessage:        version.Description,
+		CreationTimestamp:    version.CreationTimestamp,
+		LastUpdatedTimestamp: version.LastUpdatedTimestamp,
+		UserId:               version.UserId,
+		Tags:                 map[string]string{},
+		Aliases:              version.Aliases,
+	}
+	marked := false
+	for _, tag := range version.Tags {
+		switch tag.Key {
+		case mlflow.TagIsPrompt:
+			marked = tag.Value == "true"
+		case mlflow.TagPromptText:
+			prompt.Template = tag.Value
+		default:
+			prompt.Tags[tag.Key] = tag.Value
+		}
+	}
+	if !marked {
+		return nil, fmt.Errorf("mlflow: %s version %s is a model version, not a prompt", version.Name, version.Version)
+	}
+	return prompt, nil
+}
+
+func (s *Store) RegisterPrompt(ctx context.Context, name string, template string, commitMessage string, tags map[string]string) (*mlflow.PromptVersion, error) {
+	model, err := s.GetRegisteredModel(ctx, name)
+	if mlflow.IsNotFound(err) {
+		if _, err = s.CreateRegisteredModel(ctx, name, ""); err != nil && !mlflow.IsAlreadyExists(err) {
+			return nil, err
+		}
+		if err := s.SetRegisteredModelTag(ctx, name, mlflow.TagIsPrompt, "true"); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !isPrompt(model) {
+		return nil, fmt.Errorf("mlflow: %s is a registered model, not a prompt", name)
+	}
+
+	versionTags := []mlflow.ModelVersionTag{{Key: mlflow.TagIsPrompt, Value: "true"}, {Key: mlflow.TagPromptText, Value: template}}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		versionTags = append(versionTags, mlflow.ModelVersionTag{Key: key, Value: tags[key]})
+	}
+	version, err := s.CreateModelVersion(ctx, name, "dummy-source", "", versionTags...)
+	if err != nil {
+		return nil, err
+	}
+	if commitMessage != "" {
+		if version, err = s.UpdateModelVersion(ctx, name, version.Version, commitMessage); err != nil {
+			return nil, err
+		}
+	}
+	return newPromptVersion(version)
+}
+
+func (s *Store) GetPrompt(ctx context.Context, name string) (*mlflow.Prompt, error) {
+	model, err := s.GetRegisteredModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !isPrompt(model) {
+		return nil, fmt.Errorf("mlflow: %s is a registered model, not a prompt", name)
+	}
+	return newPrompt(model), nil
+}
+
+func (s *Store) GetPromptVersion(ctx context.Context, name string, version string) (*mlflow.PromptVersion, error) {
+	modelVersion, err := s.GetModelVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	return newPromptVersion(modelVersion)
+}
+
+// LoadPrompt accepts the same prompts:/ uris as mlflow.Client.
+func (s *Store) LoadPrompt(ctx context.Context, uri string) (*mlflow.PromptVersion, error) {
+	ref := strings.TrimPrefix(uri, "prompts:/")
+	if ref == uri {
+		return nil, fmt.Errorf("mlflow: %s is not a prompts:/ uri", uri)
+	}
+	version, err := s.ResolveModelUri(ctx, "models:/"+ref)
+	if err != nil {
+		return nil, err
+	}
+	return newPromptVersion(version)
+}
+
+func (s *Store) SearchPrompts(ctx context.Context, filter string, maxResults int, pageToken string) ([]mlflow.Prompt, string, error) {
+	promptFilter := "tags.`" + mlflow.TagIsPrompt + "` = 'true'"
+	if filter != "" {
+		promptFilter += " AND " + filter
+	}
+	response, err := s.SearchRegisteredModels(ctx, promptFilter, maxResults, nil, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	prompts := make([]mlflow.Prompt, len(response.RegisteredModels))
+	for i := range response.RegisteredModels {
+		prompts[i] = *newPrompt(&response.RegisteredModels[i])
+	}
+	return prompts, response.NextPageToken, nil
+}
+
+func (s *Store) SetPromptAlias(ctx context.Context, name string, alias string, version string) error {
+	return s.SetRegisteredModelAlias(ctx, name, alias, version)
+}
+
+func (s *Store) DeletePromptAlias(ctx context.Context, name string, alias string) error {
+	return s.DeleteRegisteredModelAlias(ctx, name, alias)
+}
+
+func (s *Store) DeletePromptVersion(ctx context.Context, name string, version string) error {
+	return s.DeleteModelVersion(ctx, name, version)
+}
@@ -0,0 +1,69 @@
+package mlflowfake
+
+import (
+	"context"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func TestPrompts(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	if _, err := store.RegisterPrompt(ctx, "qa", "Answer {{question}}.", "first draft", nil); err != nil {
+		t.Fatal(err)
+	}
+	v2, err := store.RegisterPrompt(ctx, "qa", "Answer {{question}} about {{topic}}.", "", map[string]string{"author": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.Version != "2" || v2.Tags["author"] != "alice" || len(v2.Tags) != 1 {
+		t.Errorf("unexpected version %+v", v2)
+	}
+	if err := store.SetPromptAlias(ctx, "qa", "production", "2"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.LoadPrompt(ctx, "prompts:/qa@production")
+	if err != nil || loaded.Template != "Answer {{question}} about {{topic}}." || len(loaded.Aliases) != 1 {
+		t.Errorf("unexpected version %+v, %v", loaded, err)
+	}
+	v1, err := store.GetPromptVersion(ctx, "qa", "1")
+	if err != nil || v1.CommitMessage != "first draft" || v1.Template != "Answer {{question}}." {
+		t.Errorf("unexpected version %+v, %v", v1, err)
+	}
+	prompt, err := store.GetPrompt(ctx, "qa")
+	if err != nil || prompt.Aliases["production"] != "2" || len(prompt.Tags) != 0 {
+		t.Errorf("unexpected prompt %+v, %v", prompt, err)
+	}
+
+	if _, err := store.CreateRegisteredModel(ctx, "classifier", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateModelVersion(ctx, "classifier", "runs:/1/model", ""); err != nil {
+		t.Fatal(err)
+	}
+	prompts, _, err := store.SearchPrompts(ctx, "", 0, "")
+	if err != nil || len(prompts) != 1 || prompts[0].Name != "qa" {
+		t.Errorf("unexpected prompts %+v, %v", prompts, err)
+	}
+	if _, err := store.RegisterPrompt(ctx, "classifier", "{{x}}", "", nil); err == nil {
+		t.Error("expected registering a prompt over a model to fail")
+	}
+	if _, err := store.GetPromptVersion(ctx, "classifier", "1"); err == nil {
+		t.Error("expected a model version not to load as a prompt")
+	}
+
+	if err := store.DeletePromptAlias(ctx, "qa", "production"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadPrompt(ctx, "prompts:/qa@production"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected the alias to be deleted, got %v", err)
+	}
+	if err := store.DeletePromptVersion(ctx, "qa", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetPromptVersion(ctx, "qa", "1"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected the version to be deleted, got %v", err)
+	}
+}
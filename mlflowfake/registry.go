@@ -0,0 +1,551 @@
+package mlflowfake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+type modelState struct {
+	model       mlflow.RegisteredModel
+	versions    []*mlflow.ModelVersion
+	lastVersion int
+}
+
+func (s *Store) modelState(name string) (*modelState, error) {
+	for _, m := range s.models {
+		if m.model.Name == name {
+			return m, nil
+		}
+	}
+	return nil, notFound("Registered Model with name=%s not found", name)
+}
+
+func (s *Store) modelVersion(name string, version string) (*modelState, *mlflow.ModelVersion, error) {
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range m.versions {
+		if v.Version == version {
+			return m, v, nil
+		}
+	}
+	return nil, nil, notFound("Model Version (name=%s, version=%s) not found", name, version)
+}
+
+// copyModel returns the model with its latest version in each stage, as the
+// tracking server does.
+func copyModel(m *modelState) mlflow.RegisteredModel {
+	model := m.model
+	model.Tags = append([]mlflow.RegisteredModelTag(nil), m.model.Tags...)
+	model.Aliases = append([]mlflow.RegisteredModelAlias(nil), m.model.Aliases...)
+	model.LatestVersions = latestVersions(m, nil)
+	return model
+}
+
+func copyModelVersion(m *modelState, v *mlflow.ModelVersion) mlflow.ModelVersion {
+	version := *v
+	version.Tags = append([]mlflow.ModelVersionTag(nil), v.Tags...)
+	version.Aliases = nil
+	for _, alias := range m.model.Aliases {
+		if alias.Version == v.Version {
+			version.Aliases = append(version.Aliases, alias.Alias)
+		}
+	}
+	return version
+}
+
+// latestVersions returns the newest version in each of stages, or in every
+// stage when stages is empty.
+func latestVersions(m *modelState, stages []string) []mlflow.ModelVersion {
+	latest := map[string]*mlflow.ModelVersion{}
+	var order []string
+	for _, v := range m.versions {
+		stage := strings.ToLower(v.CurrentStage)
+		if latest[stage] == nil {
+			order = append(order, stage)
+		}
+		latest[stage] = v
+	}
+	if len(stages) > 0 {
+		order = nil
+		for _, stage := range stages {
+			order = append(order, strings.ToLower(stage))
+		}
+	}
+	var versions []mlflow.ModelVersion
+	for _, stage := range order {
+		if v := latest[stage]; v != nil {
+			versions = append(versions, copyModelVersion(m, v))
+		}
+	}
+	return versions
+}
+
+func (s *Store) CreateRegisteredModel(ctx context.Context, name string, description string) (*mlflow.RegisteredModel, error) {
+	if name == "" {
+		return nil, invalidParameter("Registered model name cannot be empty.")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.modelState(name); err == nil {
+		return nil, alreadyExists("Registered Model (name=%s) already exists.", name)
+	}
+	now := mlflow.Millis(time.Now())
+	m := &modelState{model: mlflow.RegisteredModel{Name: name, Description: description, CreationTimestamp: now, LastUpdatedTimestamp: now}}
+	s.models = append(s.models, m)
+	model := copyModel(m)
+	return &model, nil
+}
+
+func (s *Store) GetRegisteredModel(ctx context.Context, name string) (*mlflow.RegisteredModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, err
+	}
+	model := copyModel(m)
+	return &model, nil
+}
+
+// updateModel applies fn to the model called name and returns the result.
+func (s *Store) updateModel(name string, fn func(m *modelState) error) (*mlflow.RegisteredModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(m); err != nil {
+		return nil, err
+	}
+	m.model.LastUpdatedTimestamp = mlflow.Millis(time.Now())
+	model := copyModel(m)
+	return &model, nil
+}
+
+func (s *Store) UpdateRegisteredModel(ctx context.Context, name string, description string) (*mlflow.RegisteredModel, error) {
+	return s.updateModel(name, func(m *modelState) error {
+		m.model.Description = description
+		return nil
+	})
+}
+
+func (s *Store) RenameRegisteredModel(ctx context.Context, name string, newName string) (*mlflow.RegisteredModel, error) {
+	return s.updateModel(name, func(m *modelState) error {
+		if _, err := s.modelState(newName); err == nil {
+			return alreadyExists("Registered Model (name=%s) already exists.", newName)
+		}
+		m.model.Name = newName
+		for _, v := range m.versions {
+			v.Name = newName
+		}
+		return nil
+	})
+}
+
+func (s *Store) DeleteRegisteredModel(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, m := range s.models {
+		if m.model.Name == name {
+			s.models = append(s.models[:i], s.models[i+1:]...)
+			return nil
+		}
+	}
+	return notFound("Registered Model with name=%s not found", name)
+}
+
+func (s *Store) SearchRegisteredModels(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchRegisteredModels, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []mlflow.RegisteredModel
+	for _, m := range s.models {
+		model := copyModel(m)
+		if f.Match(modelLookup(model)) {
+			matched = append(matched, model)
+		}
+	}
+	search.Sort(matched, orderBy, modelLookup, func(a, b mlflow.RegisteredModel) bool {
+		return a.Name < b.Name
+	})
+	page, next, err := search.Page(matched, maxResults, pageToken, 100)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchRegisteredModels{RegisteredModels: page, NextPageToken: next}, nil
+}
+
+func modelLookup(model mlflow.RegisteredModel) search.Lookup {
+	return func(entity string, key string) (interface{}, bool) {
+		switch entity {
+		case "tags":
+			for _, tag := range model.Tags {
+				if tag.Key == key {
+					return tag.Value, true
+				}
+			}
+		case "", "attributes":
+			switch key {
+			case "name":
+				return model.Name, true
+			case "creation_timestamp":
+				return float64(model.CreationTimestamp), true
+			case "last_updated_timestamp":
+				return float64(model.LastUpdatedTimestamp), true
+			}
+		}
+		return nil, false
+	}
+}
+
+func (s *Store) SetRegisteredModelTag(ctx context.Context, name string, key string, value string) error {
+	_, err := s.updateModel(name, func(m *modelState) error {
+		for i := range m.model.Tags {
+			if m.model.Tags[i].Key == key {
+				m.model.Tags[i].Value = value
+				return nil
+			}
+		}
+		m.model.Tags = append(m.model.Tags, mlflow.RegisteredModelTag{Key: key, Value: value})
+		return nil
+	})
+	return err
+}
+
+func (s *Store) DeleteRegisteredModelTag(ctx context.Context, name string, key string) error {
+	_, err := s.updateModel(name, func(m *modelState) error {
+		for i := range m.model.Tags {
+			if m.model.Tags[i].Key == key {
+				m.model.Tags = append(m.model.Tags[:i], m.model.Tags[i+1:]...)
+				return nil
+			}
+		}
+		return notFound("No tag with name: %s in registered model %s", key, name)
+	})
+	return err
+}
+
+func (s *Store) SetRegisteredModelAlias(ctx context.Context, name string, alias string, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, _, err := s.modelVersion(name, version)
+	if err != nil {
+		return err
+	}
+	for i := range m.model.Aliases {
+		if m.model.Aliases[i].Alias == alias {
+			m.model.Aliases[i].Version = version
+			return nil
+		}
+	}
+	m.model.Aliases = append(m.model.Aliases, mlflow.RegisteredModelAlias{Alias: alias, Version: version})
+	return nil
+}
+
+func (s *Store) DeleteRegisteredModelAlias(ctx context.Context, name string, alias string) error {
+	_, err := s.updateModel(name, func(m *modelState) error {
+		for i := range m.model.Aliases {
+			if m.model.Aliases[i].Alias == alias {
+				m.model.Aliases = append(m.model.Aliases[:i], m.model.Aliases[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// CreateModelVersion registers the next version of a model. It is READY
+// right away.
+func (s *Store) CreateModelVersion(ctx context.Context, name string, source string, runId string, tags ...mlflow.ModelVersionTag) (*mlflow.ModelVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, err
+	}
+	now := mlflow.Millis(time.Now())
+	m.lastVersion++
+	v := &mlflow.ModelVersion{
+		Name:                 name,
+		Version:              strconv.Itoa(m.lastVersion),
+		CreationTimestamp:    now,
+		LastUpdatedTimestamp: now,
+		CurrentStage:         mlflow.StageNone,
+		Source:               source,
+		RunId:                runId,
+		Status:               string(mlflow.Ready),
+		Tags:                 append([]mlflow.ModelVersionTag(nil), tags...),
+	}
+	m.versions = append(m.versions, v)
+	m.model.LastUpdatedTimestamp = now
+	version := copyModelVersion(m, v)
+	return &version, nil
+}
+
+func (s *Store) GetModelVersion(ctx context.Context, name string, version string) (*mlflow.ModelVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, v, err := s.modelVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	modelVersion := copyModelVersion(m, v)
+	return &modelVersion, nil
+}
+
+// updateModelVersion applies fn to a model version and returns the result.
+func (s *Store) updateModelVersion(name string, version string, fn func(m *modelState, v *mlflow.ModelVersion) error) (*mlflow.ModelVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, v, err := s.modelVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(m, v); err != nil {
+		return nil, err
+	}
+	v.LastUpdatedTimestamp = mlflow.Millis(time.Now())
+	modelVersion := copyModelVersion(m, v)
+	return &modelVersion, nil
+}
+
+func (s *Store) UpdateModelVersion(ctx context.Context, name string, version string, description string) (*mlflow.ModelVersion, error) {
+	return s.updateModelVersion(name, version, func(m *modelState, v *mlflow.ModelVersion) error {
+		v.Description = description
+		return nil
+	})
+}
+
+// DeleteModelVersion deletes a version along with the aliases pointing to it.
+func (s *Store) DeleteModelVersion(ctx context.Context, name string, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, _, err := s.modelVersion(name, version)
+	if err != nil {
+		return err
+	}
+	for i, v := range m.versions {
+		if v.Version == version {
+			m.versions = append(m.versions[:i], m.versions[i+1:]...)
+			break
+		}
+	}
+	aliases := m.model.Aliases[:0]
+	for _, alias := range m.model.Aliases {
+		if alias.Version != version {
+			aliases = append(aliases, alias)
+		}
+	}
+	m.model.Aliases = aliases
+	return nil
+}
+
+func (s *Store) SearchModelVersions(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchModelVersions, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []mlflow.ModelVersion
+	for _, m := range s.models {
+		for _, v := range m.versions {
+			version := copyModelVersion(m, v)
+			if f.Match(modelVersionLookup(version)) {
+				matched = append(matched, version)
+			}
+		}
+	}
+	search.Sort(matched, orderBy, modelVersionLookup, func(a, b mlflow.ModelVersion) bool {
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		va, _ := strconv.Atoi(a.Version)
+		vb, _ := strconv.Atoi(b.Version)
+		return va > vb
+	})
+	page, next, err := search.Page(matched, maxResults, pageToken, 10000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchModelVersions{ModelVersions: page, NextPageToken: next}, nil
+}
+
+func modelVersionLookup(version mlflow.ModelVersion) search.Lookup {
+	return func(entity string, key string) (interface{}, bool) {
+		switch entity {
+		case "tags":
+			for _, tag := range version.Tags {
+				if tag.Key == key {
+					return tag.Value, true
+				}
+			}
+		case "", "attributes":
+			switch key {
+			case "name":
+				return version.Name, true
+			case "run_id":
+				return version.RunId, true
+			case "source_path":
+				return version.Source, true
+			case "current_stage":
+				return version.CurrentStage, true
+			case "version_number":
+				n, err := strconv.ParseFloat(version.Version, 64)
+				return n, err == nil
+			case "creation_timestamp":
+				return float64(version.CreationTimestamp), true
+			case "last_updated_timestamp":
+				return float64(version.LastUpdatedTimestamp), true
+			}
+		}
+		return nil, false
+	}
+}
+
+func (s *Store) GetLatestVersions(ctx context.Context, name string, stages []string) ([]mlflow.ModelVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, err
+	}
+	return latestVersions(m, stages), nil
+}
+
+func (s *Store) GetModelVersionByAlias(ctx context.Context, name string, alias string) (*mlflow.ModelVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.modelState(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range m.model.Aliases {
+		if a.Alias == alias {
+			_, v, err := s.modelVersion(name, a.Version)
+			if err != nil {
+				return nil, err
+			}
+			modelVersion := copyModelVersion(m, v)
+			return &modelVersion, nil
+		}
+	}
+	return nil, notFound("Registered model alias %s not found.", alias)
+}
+
+// ResolveModelUri accepts the same models:/ uris as mlflow.Client.
+func (s *Store) ResolveModelUri(ctx context.Context, uri string) (*mlflow.ModelVersion, error) {
+	ref := strings.TrimPrefix(uri, "models:/")
+	if ref == uri || ref == "" {
+		return nil, fmt.Errorf("mlflow: %s is not a models:/ uri", uri)
+	}
+	if i := strings.LastIndex(ref, "@"); i >= 0 && !strings.Contains(ref[i:], "/") {
+		return s.GetModelVersionByAlias(ctx, ref[:i], ref[i+1:])
+	}
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return nil, fmt.Errorf("mlflow: %s does not name a version, stage or alias", uri)
+	}
+	name, suffix := ref[:i], ref[i+1:]
+	if _, err := strconv.Atoi(suffix); err == nil {
+		return s.GetModelVersion(ctx, name, suffix)
+	}
+	var stages []string
+	if !strings.EqualFold(suffix, "latest") {
+		stages = []string{suffix}
+	}
+	versions, err := s.GetLatestVersions(ctx, name, stages)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("mlflow: no version of %s in stage %s", name, suffix)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, _ := strconv.Atoi(versions[i].Version)
+		b, _ := strconv.Atoi(versions[j].Version)
+		return a > b
+	})
+	return &versions[0], nil
+}
+
+// TransitionModelVersionStage moves a version to stage. With
+// archiveExisting, the other versions in Staging or Production are archived.
+func (s *Store) TransitionModelVersionStage(ctx context.Context, name string, version string, stage string, archiveExisting bool) (*mlflow.ModelVersion, error) {
+	canonical := ""
+	for _, known := range []string{mlflow.StageNone, mlflow.StageStaging, mlflow.StageProduction, mlflow.StageArchived} {
+		if strings.EqualFold(stage, known) {
+			canonical = known
+		}
+	}
+	if canonical == "" {
+		return nil, invalidParameter("Invalid Model Version stage: %s.", stage)
+	}
+	return s.updateModelVersion(name, version, func(m *modelState, v *mlflow.ModelVersion) error {
+		if archiveExisting && (canonical == mlflow.StageStaging || canonical == mlflow.StageProduction) {
+			for _, other := range m.versions {
+				if other != v && other.CurrentStage == canonical {
+					other.CurrentStage = mlflow.StageArchived
+				}
+			}
+		}
+		v.CurrentStage = canonical
+		return nil
+	})
+}
+
+// WaitForModelVersion returns the version right away, since versions of the
+// fake are always READY.
+func (s *Store) WaitForModelVersion(ctx context.Context, name string, version string, interval time.Duration) (*mlflow.ModelVersion, error) {
+	return s.GetModelVersion(ctx, name, version)
+}
+
+func (s *Store) SetModelVersionTag(ctx context.Context, name string, version string, key string, value string) error {
+	_, err := s.updateModelVersion(name, version, func(m *modelState, v *mlflow.ModelVersion) error {
+		for i := range v.Tags {
+			if v.Tags[i].Key == key {
+				v.Tags[i].Value = value
+				return nil
+			}
+		}
+		v.Tags = append(v.Tags, mlflow.ModelVersionTag{Key: key, Value: value})
+		return nil
+	})
+	return err
+}
+
+func (s *Store) DeleteModelVersionTag(ctx context.Context, name string, version string, key string) error {
+	_, err := s.updateModelVersion(name, version, func(m *modelState, v *mlflow.ModelVersion) error {
+		for i := range v.Tags {
+			if v.Tags[i].Key == key {
+				v.Tags = append(v.Tags[:i], v.Tags[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// GetModelVersionDownloadUri returns the source of the version.
+func (s *Store) GetModelVersionDownloadUri(ctx context.Context, name string, version string) (string, error) {
+	modelVersion, err := s.GetModelVersion(ctx, name, version)
+	if err != nil {
+		return "", err
+	}
+	return modelVersion.Source, nil
+}
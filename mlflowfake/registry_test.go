@@ -0,0 +1,98 @@
+package mlflowfake
+
+import (
+	"context"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	if _, err := store.CreateRegisteredModel(ctx, "model", "a model"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := store.CreateRegisteredModel(ctx, "model", "")
+	if !mlflow.IsAlreadyExists(err) {
+		t.Errorf("expected already exists, got %v", err)
+	}
+	for _, source := range []string{"runs:/a/model", "runs:/b/model"} {
+		if _, err := store.CreateModelVersion(ctx, "model", source, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.TransitionModelVersionStage(ctx, "model", "1", "production", false); err != nil {
+		t.Fatal(err)
+	}
+	version, err := store.TransitionModelVersionStage(ctx, "model", "2", mlflow.StageProduction, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.CurrentStage != mlflow.StageProduction {
+		t.Errorf("unexpected stage %s", version.CurrentStage)
+	}
+	old, err := store.GetModelVersion(ctx, "model", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.CurrentStage != mlflow.StageArchived {
+		t.Errorf("expected version 1 to be archived, got %s", old.CurrentStage)
+	}
+
+	if err := store.SetRegisteredModelAlias(ctx, "model", "champion", "1"); err != nil {
+		t.Fatal(err)
+	}
+	for uri, expected := range map[string]string{
+		"models:/model@champion":   "1",
+		"models:/model/2":          "2",
+		"models:/model/Archived":   "1",
+		"models:/model/latest":     "2",
+		"models:/model/Production": "2",
+	} {
+		version, err := store.ResolveModelUri(ctx, uri)
+		if err != nil {
+			t.Errorf("%s: %v", uri, err)
+			continue
+		}
+		if version.Version != expected {
+			t.Errorf("%s resolved to version %s, expected %s", uri, version.Version, expected)
+		}
+	}
+	if aliases := old.Aliases; len(aliases) != 0 {
+		t.Errorf("expected a copy without the later alias, got %v", aliases)
+	}
+
+	model, err := store.GetRegisteredModel(ctx, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(model.LatestVersions) != 2 || len(model.Aliases) != 1 {
+		t.Errorf("unexpected model %+v", model)
+	}
+	versions, err := store.SearchModelVersions(ctx, "name = 'model' AND source_path LIKE '%/b/%'", 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions.ModelVersions) != 1 || versions.ModelVersions[0].Version != "2" {
+		t.Errorf("unexpected versions %+v", versions.ModelVersions)
+	}
+
+	if err := store.DeleteModelVersion(ctx, "model", "1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetModelVersionByAlias(ctx, "model", "champion")
+	if !mlflow.IsNotFound(err) {
+		t.Errorf("expected the alias to be deleted with its version, got %v", err)
+	}
+	if _, err := store.RenameRegisteredModel(ctx, "model", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	models, err := store.SearchRegisteredModels(ctx, "name LIKE 'ren%'", 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models.RegisteredModels) != 1 || models.RegisteredModels[0].LatestVersions[0].Name != "renamed" {
+		t.Errorf("unexpected models %+v", models.RegisteredModels)
+	}
+}
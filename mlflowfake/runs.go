@@ -0,0 +1,242 @@
+package mlflowfake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+)
+
+func (s *Store) runState(runId string) (*runState, error) {
+	for _, r := range s.runs {
+		if r.run.Info.RunId == runId {
+			return r, nil
+		}
+	}
+	return nil, notFound("Run with id=%s not found", runId)
+}
+
+// activeRun returns a run that can still be logged to.
+func (s *Store) activeRun(runId string) (*runState, error) {
+	r, err := s.runState(runId)
+	if err != nil {
+		return nil, err
+	}
+	if r.run.Info.LifecycleStage == "deleted" {
+		return nil, invalidParameter("The run %s must be in the 'active' state. Current state is deleted.", runId)
+	}
+	return r, nil
+}
+
+func copyRun(r *runState) mlflow.Run {
+	run := r.run
+	run.Data.Metrics = append([]mlflow.Metric(nil), r.run.Data.Metrics...)
+	run.Data.Params = append([]mlflow.Param(nil), r.run.Data.Params...)
+	run.Data.Tags = append([]mlflow.RunTag(nil), r.run.Data.Tags...)
+	run.Inputs.DatasetInputs = append([]mlflow.DatasetInput(nil), r.run.Inputs.DatasetInputs...)
+	return run
+}
+
+func (s *Store) CreateRun(ctx context.Context, experimentId string, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	spec := mlflow.NewRunSpec(opts...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	experiment, err := s.experiment(experimentId)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.LifecycleStage == "deleted" {
+		return nil, invalidParameter("The experiment %s must be in the 'active' state.", experimentId)
+	}
+	s.nextRunId++
+	runId := fmt.Sprintf("%032x", s.nextRunId)
+	r := &runState{
+		run: mlflow.Run{Info: mlflow.RunInfo{
+			RunUUid:        runId,
+			RunId:          runId,
+			RunName:        spec.Name,
+			ExperimentId:   experimentId,
+			Status:         string(mlflow.Running),
+			StartTime:      mlflow.Millis(spec.StartTime),
+			ArtifactUri:    experiment.ArtifactLocation + "/" + runId + "/artifacts",
+			LifecycleStage: "active",
+		}},
+		history:   map[string][]mlflow.Metric{},
+		artifacts: map[string][]byte{},
+	}
+	for _, tag := range spec.Tags {
+		setRunTag(r, tag)
+	}
+	if spec.Name != "" {
+		setRunTag(r, mlflow.RunTag{Key: mlflow.TagRunName, Value: spec.Name})
+	}
+	for _, tag := range r.run.Data.Tags {
+		if tag.Key == mlflow.TagUser {
+			r.run.Info.UserId = tag.Value
+		}
+	}
+	s.runs = append(s.runs, r)
+	run := copyRun(r)
+	return &run, nil
+}
+
+func (s *Store) CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	return s.CreateRun(ctx, experimentId, append(opts, mlflow.WithStartTime(startTime))...)
+}
+
+func (s *Store) updateRun(runId string, status mlflow.RunStatus, runName string, endTime int64) (*mlflow.RunInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.activeRun(runId)
+	if err != nil {
+		return nil, err
+	}
+	r.run.Info.Status = string(status)
+	if status != mlflow.Running && status != mlflow.Scheduled {
+		r.run.Info.EndTime = endTime
+	}
+	if runName != "" {
+		r.run.Info.RunName = runName
+		setRunTag(r, mlflow.RunTag{Key: mlflow.TagRunName, Value: runName})
+	}
+	info := r.run.Info
+	return &info, nil
+}
+
+func (s *Store) UpdateRun(ctx context.Context, runId string, status mlflow.RunStatus) (*mlflow.RunInfo, error) {
+	return s.UpdateRunAt(ctx, runId, status, time.Now())
+}
+
+func (s *Store) UpdateRunAt(ctx context.Context, runId string, status mlflow.RunStatus, endTime time.Time) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, "", mlflow.Millis(endTime))
+}
+
+func (s *Store) UpdateRunWithEndTime(ctx context.Context, runId string, status mlflow.RunStatus, endTime int64) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, "", endTime)
+}
+
+func (s *Store) UpdateRunWithName(ctx context.Context, runId string, status mlflow.RunStatus, runName string) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, runName, mlflow.Millis(time.Now()))
+}
+
+// DeleteRun marks a run deleted. It is kept, so that SearchRuns with
+// mlflow.DeletedOnly still finds it.
+func (s *Store) DeleteRun(ctx context.Context, runId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.runState(runId)
+	if err != nil {
+		return err
+	}
+	r.run.Info.LifecycleStage = "deleted"
+	return nil
+}
+
+func (s *Store) GetRun(ctx context.Context, runId string) (*mlflow.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.runState(runId)
+	if err != nil {
+		return nil, err
+	}
+	run := copyRun(r)
+	return &run, nil
+}
+
+func (s *Store) SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchRuns, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	experiments := map[string]bool{}
+	for _, experimentId := range experimentIds {
+		experiments[experimentId] = true
+	}
+	var matched []mlflow.Run
+	for _, r := range s.runs {
+		if experiments[r.run.Info.ExperimentId] && store.InView(r.run.Info.LifecycleStage, viewType) && f.Match(store.RunLookup(r.run)) {
+			matched = append(matched, copyRun(r))
+		}
+	}
+	store.SortRuns(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchRuns{Runs: page, NextPageToken: next}, nil
+}
+
+func (s *Store) ListChildRuns(ctx context.Context, parentRunId string) ([]mlflow.Run, error) {
+	parent, err := s.GetRun(ctx, parentRunId)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := mlflow.Filter().Tag(mlflow.TagParentRunId).Eq(parentRunId).Build()
+	if err != nil {
+		return nil, err
+	}
+	var runs []mlflow.Run
+	pageToken := ""
+	for {
+		response, err := s.SearchRuns(ctx, []string{parent.Info.ExperimentId}, filter, mlflow.ActiveOnly, 0, nil, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, response.Runs...)
+		if response.NextPageToken == "" {
+			return runs, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
+
+func (s *Store) LogInputs(ctx context.Context, runId string, datasets []mlflow.DatasetInput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.activeRun(runId)
+	if err != nil {
+		return err
+	}
+	r.run.Inputs.DatasetInputs = append(r.run.Inputs.DatasetInputs, datasets...)
+	return nil
+}
+
+func setRunTag(r *runState, tag mlflow.RunTag) {
+	tags := r.run.Data.Tags
+	for i := range tags {
+		if tags[i].Key == tag.Key {
+			tags[i].Value = tag.Value
+			return
+		}
+	}
+	r.run.Data.Tags = append(tags, tag)
+}
+
+func (s *Store) SetTag(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: key, Value: value}})
+}
+
+func (s *Store) SetTags(ctx context.Context, runId string, tags map[string]string) error {
+	var runTags []mlflow.RunTag
+	for key, value := range tags {
+		runTags = append(runTags, mlflow.RunTag{Key: key, Value: value})
+	}
+	return s.LogBatch(ctx, runId, nil, nil, runTags)
+}
+
+func (s *Store) SetRunNote(ctx context.Context, runId string, note string) error {
+	return s.SetTag(ctx, runId, mlflow.TagNote, note)
+}
+
+func (s *Store) SetRunSource(ctx context.Context, runId string, name string, sourceType mlflow.SourceType) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: mlflow.TagSourceName, Value: name}, {Key: mlflow.TagSourceType, Value: string(sourceType)}})
+}
+
+func (s *Store) SetRunUser(ctx context.Context, runId string, user string) error {
+	return s.SetTag(ctx, runId, mlflow.TagUser, user)
+}
@@ -0,0 +1,201 @@
+package mlflowfile
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"gopkg.in/yaml.v3"
+)
+
+// artifactPath returns the local path of an artifact of a run, taken
+// relative to the run's artifact_uri, which the store makes a file: uri.
+func (s *Store) artifactPath(runId string, artifactPath string, active bool) (string, error) {
+	dir, err := s.runDir(runId)
+	if err == nil && active {
+		dir, err = s.activeRunDir(runId)
+	}
+	if err != nil {
+		return "", err
+	}
+	meta, err := readMeta(dir)
+	if err != nil {
+		return "", err
+	}
+	root := meta.ArtifactUri
+	if strings.HasPrefix(root, "file:") {
+		u, err := url.Parse(root)
+		if err != nil {
+			return "", err
+		}
+		root = filepath.FromSlash(u.Path)
+	}
+	rel := strings.Trim(path.Clean("/"+artifactPath), "/")
+	return filepath.Join(root, filepath.FromSlash(rel)), nil
+}
+
+func (s *Store) putArtifact(runId string, r io.Reader, artifactPath string) error {
+	s.mu.Lock()
+	dst, err := s.artifactPath(runId, artifactPath, true)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Store) LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.putArtifact(runId, f, path.Join(artifactPath, filepath.Base(localPath)))
+}
+
+func (s *Store) LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error {
+	return filepath.Walk(localDir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return s.putArtifact(runId, f, path.Join(artifactPath, filepath.ToSlash(rel)))
+	})
+}
+
+// LogDict serializes obj as mlflow.Client does: YAML for .yaml and .yml
+// files, indented JSON otherwise.
+func (s *Store) LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path.Ext(artifactFile)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+	}
+	return s.putArtifact(runId, strings.NewReader(string(data)), artifactFile)
+}
+
+func (s *Store) LogText(ctx context.Context, runId string, text string, artifactFile string) error {
+	return s.putArtifact(runId, strings.NewReader(text), artifactFile)
+}
+
+// ListArtifacts lists the files and directories directly under dir.
+func (s *Store) ListArtifacts(ctx context.Context, runId string, dir string) ([]mlflow.FileInfo, error) {
+	s.mu.Lock()
+	local, err := s.artifactPath(runId, dir, false)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir(local)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(path.Clean("/"+dir), "/")
+	var files []mlflow.FileInfo
+	for _, entry := range entries {
+		file := mlflow.FileInfo{Path: path.Join(prefix, entry.Name()), IsDir: entry.IsDir()}
+		if !entry.IsDir() {
+			file.FileSize = entry.Size()
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func (s *Store) OpenArtifact(ctx context.Context, runId string, artifactPath string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	local, err := s.artifactPath(runId, artifactPath, false)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(local)
+	if os.IsNotExist(err) {
+		return nil, notFound("Artifact %s of run %s not found", artifactPath, runId)
+	}
+	return f, err
+}
+
+// DownloadArtifacts copies the file at remotePath, or every file under it,
+// to the same relative path under localDir. Download options are ignored.
+func (s *Store) DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...mlflow.DownloadOption) error {
+	s.mu.Lock()
+	root, err := s.artifactPath(runId, "", false)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	rel := filepath.FromSlash(strings.Trim(path.Clean("/"+remotePath), "/"))
+	src := filepath.Join(root, rel)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return notFound("Artifact %s of run %s not found", remotePath, runId)
+	}
+	return filepath.Walk(src, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, localPath)
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		dst := filepath.Join(localDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		out, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
@@ -0,0 +1,119 @@
+package mlflowfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+var _ mlflow.GarbageCollector = (*Store)(nil)
+
+// runDirs returns the run directories of an experiment directory.
+func runDirs(experimentDir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(experimentDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		dir := filepath.Join(experimentDir, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "meta.yaml")); entry.IsDir() && err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// GC permanently removes deleted runs, by the deleted_time of their
+// meta.yaml, and the experiments the Python client moved to .trash, by
+// their last_update_time, as `mlflow gc` does. Runs deleted by versions of
+// MLflow that did not record deleted_time are only removed with olderThan
+// 0. Artifacts are deleted through mlflow.ArtifactRepository, wherever the
+// artifact_uri of a run points.
+func (s *Store) GC(ctx context.Context, olderThan time.Duration) (*mlflow.GCResult, error) {
+	threshold := mlflow.Millis(time.Now().Add(-olderThan))
+	expired := func(t *int64) bool {
+		return olderThan <= 0 || t != nil && *t <= threshold
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := &mlflow.GCResult{}
+	ids, err := s.experimentIds()
+	if err != nil {
+		return nil, err
+	}
+	for _, experimentId := range ids {
+		dirs, err := runDirs(filepath.Join(s.root, experimentId))
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			meta, err := readMeta(dir)
+			if err != nil {
+				return nil, err
+			}
+			if meta.LifecycleStage != "deleted" || !expired(meta.DeletedTime) {
+				continue
+			}
+			if err := s.removeRun(ctx, dir, meta); err != nil {
+				return nil, err
+			}
+			result.RunIds = append(result.RunIds, meta.RunId)
+		}
+	}
+
+	trash := filepath.Join(s.root, ".trash")
+	entries, err := ioutil.ReadDir(trash)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(trash, entry.Name())
+		var meta experimentMeta
+		if err := readYaml(filepath.Join(dir, "meta.yaml"), &meta); err != nil {
+			continue
+		}
+		if !expired(&meta.LastUpdateTime) {
+			continue
+		}
+		dirs, err := runDirs(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, runDir := range dirs {
+			runMeta, err := readMeta(runDir)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.removeRun(ctx, runDir, runMeta); err != nil {
+				return nil, err
+			}
+			result.RunIds = append(result.RunIds, runMeta.RunId)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		result.ExperimentIds = append(result.ExperimentIds, entry.Name())
+	}
+	sort.Strings(result.RunIds)
+	return result, nil
+}
+
+// removeRun deletes the artifacts of a run and then its directory.
+func (s *Store) removeRun(ctx context.Context, dir string, meta *runMeta) error {
+	if meta.ArtifactUri != "" {
+		repo, err := s.artifacts.ArtifactRepository(meta.ArtifactUri)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, ""); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,160 @@
+package mlflowfile
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+func (s *Store) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	return s.LogBatch(ctx, runId, []mlflow.Metric{{Key: key, Value: value, Timestamp: timestamp, Step: step}}, nil, nil)
+}
+
+func (s *Store) LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error {
+	return s.LogMetric(ctx, runId, key, value, mlflow.Millis(t), step)
+}
+
+func (s *Store) LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error {
+	timestamp := mlflow.Millis(time.Now())
+	var batch []mlflow.Metric
+	for _, key := range sortedKeys(metrics) {
+		batch = append(batch, mlflow.Metric{Key: key, Value: metrics[key], Timestamp: timestamp, Step: step})
+	}
+	return s.LogBatch(ctx, runId, batch, nil, nil)
+}
+
+func (s *Store) LogParam(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, []mlflow.Param{{Key: key, Value: value}}, nil)
+}
+
+func (s *Store) LogParams(ctx context.Context, runId string, params map[string]string) error {
+	var batch []mlflow.Param
+	for _, key := range sortedKeys(params) {
+		batch = append(batch, mlflow.Param{Key: key, Value: params[key]})
+	}
+	return s.LogBatch(ctx, runId, nil, batch, nil)
+}
+
+func (s *Store) LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error {
+	params, err := mlflow.FlattenParams(cfg)
+	if err != nil {
+		return err
+	}
+	return s.LogBatch(ctx, runId, nil, params, nil)
+}
+
+// LogBatch checks every key and param before writing anything: when a param
+// would change the value it was logged with, nothing is logged and
+// INVALID_PARAMETER_VALUE is returned, as the tracking server does.
+func (s *Store) LogBatch(ctx context.Context, runId string, metrics []mlflow.Metric, params []mlflow.Param, tags []mlflow.RunTag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir, err := s.activeRunDir(runId)
+	if err != nil {
+		return err
+	}
+	paramPaths := make([]string, len(params))
+	for i, param := range params {
+		path, err := keyPath(filepath.Join(dir, "params"), param.Key)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err == nil && string(b) != param.Value {
+			return invalidParameter("Changing param values is not allowed. Param with key='%s' was already logged with value='%s' for run ID='%s'. Attempted logging new value '%s'.", param.Key, b, runId, param.Value)
+		}
+		paramPaths[i] = path
+	}
+	metricPaths := make([]string, len(metrics))
+	for i, metric := range metrics {
+		if metricPaths[i], err = keyPath(filepath.Join(dir, "metrics"), metric.Key); err != nil {
+			return err
+		}
+	}
+	tagPaths := make([]string, len(tags))
+	for i, tag := range tags {
+		if tagPaths[i], err = keyPath(filepath.Join(dir, "tags"), tag.Key); err != nil {
+			return err
+		}
+	}
+
+	for i, param := range params {
+		if err := writeFile(paramPaths[i], []byte(param.Value)); err != nil {
+			return err
+		}
+	}
+	for i, metric := range metrics {
+		line := fmt.Sprintf("%d %s %d\n", metric.Timestamp, strconv.FormatFloat(metric.Value, 'g', -1, 64), metric.Step)
+		if err := appendFile(metricPaths[i], line); err != nil {
+			return err
+		}
+	}
+	for i, tag := range tags {
+		if err := writeFile(tagPaths[i], []byte(tag.Value)); err != nil {
+			return err
+		}
+		if tag.Key == mlflow.TagUser || tag.Key == mlflow.TagRunName {
+			meta, err := readMeta(dir)
+			if err != nil {
+				return err
+			}
+			if tag.Key == mlflow.TagUser {
+				meta.UserId = tag.Value
+			} else {
+				meta.RunName = tag.Value
+			}
+			if err := writeYaml(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func appendFile(path string, s string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Store) GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*mlflow.ResponseGetMetricHistory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir, err := s.runDir(runId)
+	if err != nil {
+		return nil, err
+	}
+	path, err := keyPath(filepath.Join(dir, "metrics"), metricKey)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	history, err := parseHistory(metricKey, string(b))
+	if err != nil {
+		return nil, err
+	}
+	page, next, err := search.Page(history, maxResults, pageToken, 25000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseGetMetricHistory{Metrics: page, NextPageToken: next}, nil
+}
@@ -0,0 +1,415 @@
+// Package mlflowfile implements mlflow.API against a local mlruns
+// directory, in the layout of the Python client's file store, so that
+// programs can track experiments without a tracking server and browse them
+// with `mlflow ui --backend-store-uri ./mlruns`:
+//
+//	mlruns/<experiment id>/meta.yaml
+//	mlruns/<experiment id>/tags/<key>
+//	mlruns/<experiment id>/<run id>/meta.yaml
+//	mlruns/<experiment id>/<run id>/metrics/<key>   one "timestamp value step" line per point
+//	mlruns/<experiment id>/<run id>/params/<key>
+//	mlruns/<experiment id>/<run id>/tags/<key>
+//	mlruns/<experiment id>/<run id>/artifacts/
+//
+// Experiments, runs, metrics, params, tags and artifacts are implemented;
+// the model registry, traces and webhooks are not.
+package mlflowfile
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Store is a file store rooted at an mlruns directory. Create it with New.
+// Its methods are safe for concurrent use within a process, but not across
+// processes writing the same directory.
+type Store struct {
+	mlflow.UnimplementedAPI
+
+	root      string
+	artifacts *mlflow.Client
+	mu        sync.Mutex
+}
+
+// Options configures a Store. Zero values select the defaults.
+type Options struct {
+	// ArtifactClient resolves the artifact repositories of runs, for GC.
+	// Defaults to a client without a tracking server, which supports every
+	// artifact uri but mlflow-artifacts.
+	ArtifactClient *mlflow.Client
+}
+
+var _ mlflow.API = (*Store)(nil)
+
+// Open returns the tracking backend of a tracking uri: a Store for a
+// filesystem path or a file: uri, and an mlflow.Client configured with opts
+// for an http(s) or databricks uri.
+func Open(uri string, opts ...mlflow.Option) (mlflow.API, error) {
+	u, err := url.Parse(uri)
+	scheme := ""
+	if err == nil && len(u.Scheme) > 1 {
+		// Single letter schemes are Windows drive letters.
+		scheme = u.Scheme
+	}
+	switch scheme {
+	case "":
+		return New(uri)
+	case "file":
+		return New(filepath.FromSlash(u.Path))
+	case "http", "https":
+		return mlflow.New(strings.TrimSuffix(uri, "/"), opts...), nil
+	case "databricks":
+		return mlflow.NewDatabricksFromUri(uri, opts...)
+	}
+	return nil, fmt.Errorf("mlflowfile: unsupported tracking uri %s", uri)
+}
+
+// New returns the store of the mlruns directory root, creating it with the
+// Default experiment "0" if needed, as the Python client does.
+func New(root string) (*Store, error) {
+	return NewWithOptions(root, Options{})
+}
+
+func NewWithOptions(root string, opts Options) (*Store, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := opts.ArtifactClient
+	if artifacts == nil {
+		artifacts = mlflow.New("")
+	}
+	s := &Store{root: root, artifacts: artifacts}
+	if _, err := os.Stat(filepath.Join(root, "0", "meta.yaml")); os.IsNotExist(err) {
+		if err := s.createExperiment("0", "Default"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func newError(statusCode int, errorCode string, format string, args ...interface{}) error {
+	return &mlflow.Error{StatusCode: statusCode, ErrorCode: errorCode, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return newError(http.StatusNotFound, mlflow.ErrorCodeResourceDoesNotExist, format, args...)
+}
+
+func invalidParameter(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeInvalidParameterValue, format, args...)
+}
+
+func alreadyExists(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeResourceAlreadyExists, format, args...)
+}
+
+func fileUri(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func readYaml(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, v)
+}
+
+func writeYaml(path string, v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, b)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// keyPath returns the file of a metric, param or tag key under dir. Keys
+// may contain slashes, which nest directories, but may not escape dir.
+func keyPath(dir string, key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", invalidParameter("Invalid key '%s'", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", invalidParameter("Invalid key '%s'", key)
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(key)), nil
+}
+
+// readKeys reads the files under dir, keyed by their slash-separated path
+// relative to it.
+func readKeys(dir string) (map[string]string, error) {
+	values := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == dir {
+			return filepath.SkipDir
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		values[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	return values, err
+}
+
+// experimentMeta is the meta.yaml of an experiment.
+type experimentMeta struct {
+	ArtifactLocation string `yaml:"artifact_location"`
+	CreationTime     int64  `yaml:"creation_time"`
+	ExperimentId     string `yaml:"experiment_id"`
+	LastUpdateTime   int64  `yaml:"last_update_time"`
+	LifecycleStage   string `yaml:"lifecycle_stage"`
+	Name             string `yaml:"name"`
+}
+
+func (s *Store) createExperiment(experimentId string, name string) error {
+	dir := filepath.Join(s.root, experimentId)
+	now := mlflow.Millis(time.Now())
+	return writeYaml(filepath.Join(dir, "meta.yaml"), experimentMeta{
+		ArtifactLocation: fileUri(dir),
+		CreationTime:     now,
+		ExperimentId:     experimentId,
+		LastUpdateTime:   now,
+		LifecycleStage:   "active",
+		Name:             name,
+	})
+}
+
+func (s *Store) readExperiment(experimentId string) (*mlflow.Experiment, error) {
+	dir := filepath.Join(s.root, experimentId)
+	var meta experimentMeta
+	if err := readYaml(filepath.Join(dir, "meta.yaml"), &meta); err != nil {
+		if os.IsNotExist(err) {
+			return nil, notFound("No Experiment with id=%s exists", experimentId)
+		}
+		return nil, err
+	}
+	e := &mlflow.Experiment{
+		ExperimentId:     meta.ExperimentId,
+		Name:             meta.Name,
+		ArtifactLocation: meta.ArtifactLocation,
+		LifecycleStage:   meta.LifecycleStage,
+		CreationTime:     meta.CreationTime,
+		LastUpdateTime:   meta.LastUpdateTime,
+	}
+	tags, err := readKeys(filepath.Join(dir, "tags"))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(tags) {
+		e.Tags = append(e.Tags, mlflow.ExperimentTag{Key: key, Value: tags[key]})
+	}
+	return e, nil
+}
+
+// experimentIds returns the ids of the experiments under root, in
+// numerical order.
+func (s *Store) experimentIds() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.root)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(s.root, entry.Name(), "meta.yaml")); err == nil {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.ParseInt(ids[i], 10, 64)
+		b, errB := strconv.ParseInt(ids[j], 10, 64)
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+	return ids, nil
+}
+
+func (s *Store) experiments() ([]*mlflow.Experiment, error) {
+	ids, err := s.experimentIds()
+	if err != nil {
+		return nil, err
+	}
+	var experiments []*mlflow.Experiment
+	for _, id := range ids {
+		e, err := s.readExperiment(id)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, e)
+	}
+	return experiments, nil
+}
+
+func (s *Store) GetExperiment(ctx context.Context, experimentId string) (*mlflow.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readExperiment(experimentId)
+}
+
+func (s *Store) experimentByName(name string) (*mlflow.Experiment, error) {
+	experiments, err := s.experiments()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range experiments {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return nil, notFound("Could not find experiment with name '%s'", name)
+}
+
+func (s *Store) GetExperimentsByName(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.experimentByName(name)
+}
+
+func (s *Store) GetOrCreateExperiment(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	experiment, err := s.GetExperimentsByName(ctx, name)
+	if !mlflow.IsNotFound(err) {
+		return experiment, err
+	}
+	experimentId, err := s.CreateExperiment(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExperiment(ctx, *experimentId)
+}
+
+func (s *Store) SearchExperiments(ctx context.Context, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchExperiments, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	experiments, err := s.experiments()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var matched []mlflow.Experiment
+	for _, e := range experiments {
+		if store.InView(e.LifecycleStage, viewType) && f.Match(store.ExperimentLookup(e)) {
+			matched = append(matched, *e)
+		}
+	}
+	store.SortExperiments(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchExperiments{Experiments: page, NextPageToken: next}, nil
+}
+
+// CreateExperiment creates an experiment with the next free numeric id,
+// counting deleted experiments moved to .trash by the Python client.
+func (s *Store) CreateExperiment(ctx context.Context, name string) (*string, error) {
+	if name == "" {
+		return nil, invalidParameter("Invalid experiment name: ''")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.experimentByName(name); err == nil {
+		return nil, alreadyExists("Experiment '%s' already exists.", name)
+	} else if !mlflow.IsNotFound(err) {
+		return nil, err
+	}
+	next := int64(0)
+	for _, dir := range []string{s.root, filepath.Join(s.root, ".trash")} {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if id, err := strconv.ParseInt(entry.Name(), 10, 64); err == nil && id >= next {
+				next = id + 1
+			}
+		}
+	}
+	id := strconv.FormatInt(next, 10)
+	if err := s.createExperiment(id, name); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (s *Store) SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.readExperiment(experimentId); err != nil {
+		return err
+	}
+	path, err := keyPath(filepath.Join(s.root, experimentId, "tags"), key)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, []byte(value))
+}
+
+func (s *Store) SetExperimentNote(ctx context.Context, experimentId string, note string) error {
+	return s.SetExperimentTag(ctx, experimentId, mlflow.TagNote, note)
+}
+
+// DefaultExperimentId resolves MLFLOW_EXPERIMENT_NAME and
+// MLFLOW_EXPERIMENT_ID as mlflow.Client does.
+func (s *Store) DefaultExperimentId(ctx context.Context) (string, error) {
+	if name := os.Getenv("MLFLOW_EXPERIMENT_NAME"); name != "" {
+		experiment, err := s.GetOrCreateExperiment(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return experiment.ExperimentId, nil
+	}
+	if experimentId := os.Getenv("MLFLOW_EXPERIMENT_ID"); experimentId != "" {
+		return experimentId, nil
+	}
+	return "0", nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,297 @@
+package mlflowfile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func TestStore(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "mlruns")
+	s, err := New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	experiment, err := s.GetOrCreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if experiment.ExperimentId != "1" {
+		t.Errorf("expected the experiment after Default, got %s", experiment.ExperimentId)
+	}
+	run, err := s.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("train"), mlflow.WithTags(map[string]string{"team": "ml"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := s.LogParams(ctx, runId, map[string]string{"lr": "0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	for step, loss := range []float64{0.5, 0.25} {
+		if err := s.LogMetric(ctx, runId, "train/loss", loss, int64(1000+step), int64(step)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.LogText(ctx, runId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateRunWithEndTime(ctx, runId, mlflow.Finished, 2000); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "1", runId)
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "metrics", "train", "loss")); err != nil || string(b) != "1000 0.5 0\n1001 0.25 1\n" {
+		t.Errorf("unexpected metric file %q, %v", b, err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "meta.yaml")); err != nil || !strings.Contains(string(b), "status: 3\n") || !strings.Contains(string(b), "end_time: 2000\n") {
+		t.Errorf("unexpected meta.yaml %s, %v", b, err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "artifacts", "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected artifact %q, %v", b, err)
+	}
+
+	// A new store on the same directory sees everything.
+	s, err = New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err = s.GetRun(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.RunName != "train" || run.Info.Status != string(mlflow.Finished) || run.Info.EndTime != 2000 {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if loss, _ := run.Metric("train/loss"); loss != 0.25 {
+		t.Errorf("expected the latest loss, got %v", loss)
+	}
+	err = s.LogParam(ctx, runId, "lr", "0.2")
+	if !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected changing a param to fail, got %v", err)
+	}
+	if err := s.LogParam(ctx, runId, "../escape", "x"); !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected an escaping key to fail, got %v", err)
+	}
+
+	filter, err := mlflow.Filter().Metric("train/loss").Lt(0.3).Tag("team").Eq("ml").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := s.SearchRuns(ctx, []string{experiment.ExperimentId}, filter, mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 1 || response.Runs[0].Info.RunId != runId {
+		t.Errorf("unexpected search result %+v", response.Runs)
+	}
+	files, err := s.ListArtifacts(ctx, runId, "notes")
+	if err != nil || len(files) != 1 || files[0].Path != "notes/hello.txt" || files[0].FileSize != 5 {
+		t.Errorf("unexpected artifacts %+v, %v", files, err)
+	}
+	local := t.TempDir()
+	if err := s.DownloadArtifacts(ctx, runId, "", local); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(local, "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+
+	if err := s.DeleteRun(ctx, runId); err != nil {
+		t.Fatal(err)
+	}
+	response, err = s.SearchRuns(ctx, []string{experiment.ExperimentId}, "", mlflow.DeletedOnly, 0, nil, "")
+	if err != nil || len(response.Runs) != 1 {
+		t.Errorf("expected the deleted run, got %+v, %v", response, err)
+	}
+	if _, err := s.GetRun(ctx, "missing"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+// TestPythonLayout reads an mlruns directory as the Python client writes
+// it, with a metric from before steps were logged.
+func TestPythonLayout(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"0/meta.yaml": "artifact_location: file:///tmp/mlruns/0\nexperiment_id: '0'\nlifecycle_stage: active\nname: Default\n",
+		"0/0123456789abcdef0123456789abcdef/meta.yaml": "artifact_uri: file:///tmp/mlruns/0/0123456789abcdef0123456789abcdef/artifacts\n" +
+			"end_time: null\nentry_point_name: ''\nexperiment_id: '0'\nlifecycle_stage: active\n" +
+			"run_id: 0123456789abcdef0123456789abcdef\nrun_name: python\nrun_uuid: 0123456789abcdef0123456789abcdef\n" +
+			"source_name: ''\nsource_type: 4\nsource_version: ''\nstart_time: 1700000000000\nstatus: 1\ntags: []\nuser_id: alice\n",
+		"0/0123456789abcdef0123456789abcdef/metrics/acc":      "1700000000001 0.9\n",
+		"0/0123456789abcdef0123456789abcdef/params/epochs":    "10",
+		"0/0123456789abcdef0123456789abcdef/tags/mlflow.user": "alice",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api, err := Open("file://" + filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := api.GetRun(context.Background(), "0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.Status != string(mlflow.Running) || run.Info.EndTime != 0 || run.Info.UserId != "alice" || !run.Info.StartedAt().Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if acc, _ := run.Metric("acc"); acc != 0.9 {
+		t.Errorf("unexpected metric %v", acc)
+	}
+	if epochs, _ := run.Param("epochs"); epochs != "10" {
+		t.Errorf("unexpected param %q", epochs)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	api, err := Open("http://localhost:5000/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client, ok := api.(*mlflow.Client); !ok || client.BaseUrl != "http://localhost:5000" {
+		t.Errorf("expected a client, got %#v", api)
+	}
+	api, err = Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := api.(*Store); !ok {
+		t.Errorf("expected a file store, got %#v", api)
+	}
+	if _, err := Open("ftp://host/mlruns"); err == nil {
+		t.Error("expected an unsupported scheme to fail")
+	}
+}
+
+func TestGC(t *testing.T) {
+	root := t.TempDir()
+	s, err := New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var runIds []string
+	for i := 0; i < 3; i++ {
+		run, err := s.CreateRun(ctx, "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.LogText(ctx, run.Info.RunId, "x", "x.txt"); err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, run.Info.RunId)
+	}
+	old, recent, kept := runIds[0], runIds[1], runIds[2]
+	for _, runId := range []string{old, recent} {
+		if err := s.DeleteRun(ctx, runId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := filepath.Join(root, "0", old)
+	meta, err := readMeta(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekAgo := mlflow.Millis(time.Now().Add(-7 * 24 * time.Hour))
+	meta.DeletedTime = &weekAgo
+	if err := writeYaml(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+		t.Fatal(err)
+	}
+	// An experiment deleted by the Python client a week ago.
+	trashed := filepath.Join(root, ".trash", "1")
+	trashedRun := filepath.Join(trashed, "0123456789abcdef0123456789abcdef")
+	files := map[string]string{
+		filepath.Join(trashed, "meta.yaml"):                 "experiment_id: '1'\nlifecycle_stage: deleted\nlast_update_time: " + strconv.FormatInt(weekAgo, 10) + "\nname: trashed\n",
+		filepath.Join(trashedRun, "meta.yaml"):              "artifact_uri: " + fileUri(filepath.Join(trashedRun, "artifacts")) + "\nrun_id: 0123456789abcdef0123456789abcdef\nlifecycle_stage: active\n",
+		filepath.Join(trashedRun, "artifacts", "model.bin"): "weights",
+	}
+	for path, content := range files {
+		if err := writeFile(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := s.GC(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{old, "0123456789abcdef0123456789abcdef"}
+	sort.Strings(expected)
+	if !reflect.DeepEqual(result.RunIds, expected) || !reflect.DeepEqual(result.ExperimentIds, []string{"1"}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	for _, path := range []string{dir, trashed} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", path, err)
+		}
+	}
+	for _, runId := range []string{recent, kept} {
+		if _, err := s.GetRun(ctx, runId); err != nil {
+			t.Errorf("expected run %s to be kept, got %v", runId, err)
+		}
+	}
+
+	result, err = s.GC(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.RunIds, []string{recent}) {
+		t.Errorf("expected everything deleted to be removed, got %+v", result)
+	}
+}
+
+func TestGCArtifactClient(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.Method+" "+r.URL.Path)
+	}))
+	defer server.Close()
+	root := t.TempDir()
+	s, err := NewWithOptions(root, Options{ArtifactClient: mlflow.New(server.URL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	run, err := s.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "0", run.Info.RunId)
+	meta, err := readMeta(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta.ArtifactUri = "mlflow-artifacts:/0/" + run.Info.RunId + "/artifacts"
+	if err := writeYaml(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteRun(ctx, run.Info.RunId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GC(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"DELETE /api/2.0/mlflow-artifacts/artifacts/0/" + run.Info.RunId + "/artifacts"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected the artifacts to be deleted through the server, got %v", deleted)
+	}
+}
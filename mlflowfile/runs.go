@@ -0,0 +1,402 @@
+package mlflowfile
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+)
+
+// runMeta is the meta.yaml of a run. Status is stored as the number of the
+// RunStatus enum of MLflow's protos.
+type runMeta struct {
+	ArtifactUri    string `yaml:"artifact_uri"`
+	DeletedTime    *int64 `yaml:"deleted_time,omitempty"`
+	EndTime        *int64 `yaml:"end_time"`
+	EntryPointName string `yaml:"entry_point_name"`
+	ExperimentId   string `yaml:"experiment_id"`
+	LifecycleStage string `yaml:"lifecycle_stage"`
+	RunId          string `yaml:"run_id"`
+	RunName        string `yaml:"run_name"`
+	RunUuid        string `yaml:"run_uuid"`
+	SourceName     string `yaml:"source_name"`
+	SourceType     int    `yaml:"source_type"`
+	SourceVersion  string `yaml:"source_version"`
+	StartTime      int64  `yaml:"start_time"`
+	Status         int    `yaml:"status"`
+	Tags           []int  `yaml:"tags"`
+	UserId         string `yaml:"user_id"`
+}
+
+var runStatuses = []mlflow.RunStatus{1: mlflow.Running, 2: mlflow.Scheduled, 3: mlflow.Finished, 4: mlflow.Failed, 5: mlflow.Killed}
+
+func statusNumber(status mlflow.RunStatus) int {
+	for i, s := range runStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *runMeta) info() mlflow.RunInfo {
+	info := mlflow.RunInfo{
+		RunUUid:        m.RunUuid,
+		RunId:          m.RunId,
+		RunName:        m.RunName,
+		ExperimentId:   m.ExperimentId,
+		UserId:         m.UserId,
+		Status:         string(mlflow.Uninitialized),
+		StartTime:      m.StartTime,
+		ArtifactUri:    m.ArtifactUri,
+		LifecycleStage: m.LifecycleStage,
+	}
+	if m.EndTime != nil {
+		info.EndTime = *m.EndTime
+	}
+	if m.Status > 0 && m.Status < len(runStatuses) {
+		info.Status = string(runStatuses[m.Status])
+	}
+	return info
+}
+
+// runDir returns the directory of a run, looking for it in every
+// experiment.
+func (s *Store) runDir(runId string) (string, error) {
+	if runId != "" && !strings.ContainsAny(runId, `/\.`) {
+		ids, err := s.experimentIds()
+		if err != nil {
+			return "", err
+		}
+		for _, experimentId := range ids {
+			dir := filepath.Join(s.root, experimentId, runId)
+			if _, err := os.Stat(filepath.Join(dir, "meta.yaml")); err == nil {
+				return dir, nil
+			}
+		}
+	}
+	return "", notFound("Run with id=%s not found", runId)
+}
+
+func readMeta(dir string) (*runMeta, error) {
+	var meta runMeta
+	if err := readYaml(filepath.Join(dir, "meta.yaml"), &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// activeRunDir returns the directory of a run that can still be logged to.
+func (s *Store) activeRunDir(runId string) (string, error) {
+	dir, err := s.runDir(runId)
+	if err != nil {
+		return "", err
+	}
+	meta, err := readMeta(dir)
+	if err != nil {
+		return "", err
+	}
+	if meta.LifecycleStage == "deleted" {
+		return "", invalidParameter("The run %s must be in the 'active' state. Current state is deleted.", runId)
+	}
+	return dir, nil
+}
+
+func (s *Store) readRun(dir string) (*mlflow.Run, error) {
+	meta, err := readMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+	run := &mlflow.Run{Info: meta.info()}
+	params, err := readKeys(filepath.Join(dir, "params"))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(params) {
+		run.Data.Params = append(run.Data.Params, mlflow.Param{Key: key, Value: params[key]})
+	}
+	tags, err := readKeys(filepath.Join(dir, "tags"))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(tags) {
+		run.Data.Tags = append(run.Data.Tags, mlflow.RunTag{Key: key, Value: tags[key]})
+	}
+	histories, err := readKeys(filepath.Join(dir, "metrics"))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(histories) {
+		history, err := parseHistory(key, histories[key])
+		if err != nil {
+			return nil, err
+		}
+		if len(history) == 0 {
+			continue
+		}
+		latest := history[0]
+		for _, metric := range history[1:] {
+			if metric.Step > latest.Step || metric.Step == latest.Step && metric.Timestamp >= latest.Timestamp {
+				latest = metric
+			}
+		}
+		run.Data.Metrics = append(run.Data.Metrics, latest)
+	}
+	return run, nil
+}
+
+func newRunId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *Store) CreateRun(ctx context.Context, experimentId string, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	spec := mlflow.NewRunSpec(opts...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	experiment, err := s.readExperiment(experimentId)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.LifecycleStage == "deleted" {
+		return nil, invalidParameter("The experiment %s must be in the 'active' state.", experimentId)
+	}
+	runId := newRunId()
+	dir := filepath.Join(s.root, experimentId, runId)
+	meta := runMeta{
+		ArtifactUri:    strings.TrimSuffix(experiment.ArtifactLocation, "/") + "/" + runId + "/artifacts",
+		ExperimentId:   experimentId,
+		LifecycleStage: "active",
+		RunId:          runId,
+		RunName:        spec.Name,
+		RunUuid:        runId,
+		SourceType:     4,
+		StartTime:      mlflow.Millis(spec.StartTime),
+		Status:         statusNumber(mlflow.Running),
+		Tags:           []int{},
+	}
+	tags := spec.Tags
+	if spec.Name != "" {
+		tags = append(tags, mlflow.RunTag{Key: mlflow.TagRunName, Value: spec.Name})
+	}
+	for _, tag := range tags {
+		if tag.Key == mlflow.TagUser {
+			meta.UserId = tag.Value
+		}
+		path, err := keyPath(filepath.Join(dir, "tags"), tag.Key)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFile(path, []byte(tag.Value)); err != nil {
+			return nil, err
+		}
+	}
+	for _, sub := range []string{"metrics", "params", "artifacts"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeYaml(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+		return nil, err
+	}
+	return s.readRun(dir)
+}
+
+func (s *Store) CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	return s.CreateRun(ctx, experimentId, append(opts, mlflow.WithStartTime(startTime))...)
+}
+
+func (s *Store) updateRun(runId string, status mlflow.RunStatus, runName string, endTime int64) (*mlflow.RunInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir, err := s.activeRunDir(runId)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := readMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+	meta.Status = statusNumber(status)
+	if status != mlflow.Running && status != mlflow.Scheduled {
+		meta.EndTime = &endTime
+	}
+	if runName != "" {
+		meta.RunName = runName
+		if err := writeFile(filepath.Join(dir, "tags", mlflow.TagRunName), []byte(runName)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeYaml(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+		return nil, err
+	}
+	info := meta.info()
+	return &info, nil
+}
+
+func (s *Store) UpdateRun(ctx context.Context, runId string, status mlflow.RunStatus) (*mlflow.RunInfo, error) {
+	return s.UpdateRunAt(ctx, runId, status, time.Now())
+}
+
+func (s *Store) UpdateRunAt(ctx context.Context, runId string, status mlflow.RunStatus, endTime time.Time) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, "", mlflow.Millis(endTime))
+}
+
+func (s *Store) UpdateRunWithEndTime(ctx context.Context, runId string, status mlflow.RunStatus, endTime int64) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, "", endTime)
+}
+
+func (s *Store) UpdateRunWithName(ctx context.Context, runId string, status mlflow.RunStatus, runName string) (*mlflow.RunInfo, error) {
+	return s.updateRun(runId, status, runName, mlflow.Millis(time.Now()))
+}
+
+// DeleteRun marks a run deleted in its meta.yaml and records when, as the
+// Python client does. Its files are kept until GC.
+func (s *Store) DeleteRun(ctx context.Context, runId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir, err := s.runDir(runId)
+	if err != nil {
+		return err
+	}
+	meta, err := readMeta(dir)
+	if err != nil {
+		return err
+	}
+	deletedTime := mlflow.Millis(time.Now())
+	meta.LifecycleStage, meta.DeletedTime = "deleted", &deletedTime
+	return writeYaml(filepath.Join(dir, "meta.yaml"), meta)
+}
+
+func (s *Store) GetRun(ctx context.Context, runId string) (*mlflow.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir, err := s.runDir(runId)
+	if err != nil {
+		return nil, err
+	}
+	return s.readRun(dir)
+}
+
+func (s *Store) SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchRuns, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	s.mu.Lock()
+	var matched []mlflow.Run
+	for _, experimentId := range experimentIds {
+		if strings.ContainsAny(experimentId, `/\.`) {
+			continue
+		}
+		entries, err := ioutil.ReadDir(filepath.Join(s.root, experimentId))
+		if err != nil && !os.IsNotExist(err) {
+			s.mu.Unlock()
+			return nil, err
+		}
+		for _, entry := range entries {
+			dir := filepath.Join(s.root, experimentId, entry.Name())
+			if _, err := os.Stat(filepath.Join(dir, "meta.yaml")); !entry.IsDir() || err != nil {
+				continue
+			}
+			run, err := s.readRun(dir)
+			if err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
+			if store.InView(run.Info.LifecycleStage, viewType) && f.Match(store.RunLookup(*run)) {
+				matched = append(matched, *run)
+			}
+		}
+	}
+	s.mu.Unlock()
+	store.SortRuns(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchRuns{Runs: page, NextPageToken: next}, nil
+}
+
+func (s *Store) ListChildRuns(ctx context.Context, parentRunId string) ([]mlflow.Run, error) {
+	parent, err := s.GetRun(ctx, parentRunId)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := mlflow.Filter().Tag(mlflow.TagParentRunId).Eq(parentRunId).Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.SearchRuns(ctx, []string{parent.Info.ExperimentId}, filter, mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return response.Runs, nil
+}
+
+func (s *Store) SetTag(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: key, Value: value}})
+}
+
+func (s *Store) SetTags(ctx context.Context, runId string, tags map[string]string) error {
+	var runTags []mlflow.RunTag
+	for _, key := range sortedKeys(tags) {
+		runTags = append(runTags, mlflow.RunTag{Key: key, Value: tags[key]})
+	}
+	return s.LogBatch(ctx, runId, nil, nil, runTags)
+}
+
+func (s *Store) SetRunNote(ctx context.Context, runId string, note string) error {
+	return s.SetTag(ctx, runId, mlflow.TagNote, note)
+}
+
+func (s *Store) SetRunSource(ctx context.Context, runId string, name string, sourceType mlflow.SourceType) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: mlflow.TagSourceName, Value: name}, {Key: mlflow.TagSourceType, Value: string(sourceType)}})
+}
+
+func (s *Store) SetRunUser(ctx context.Context, runId string, user string) error {
+	return s.SetTag(ctx, runId, mlflow.TagUser, user)
+}
+
+// parseHistory parses a metric file, one "timestamp value step" line per
+// point. Files written by old versions of MLflow have no step.
+func parseHistory(key string, data string) ([]mlflow.Metric, error) {
+	var history []mlflow.Metric
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("mlflowfile: invalid line %q in metric %s", scanner.Text(), key)
+		}
+		metric := mlflow.Metric{Key: key}
+		var err error
+		if metric.Timestamp, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("mlflowfile: invalid timestamp in metric %s: %w", key, err)
+		}
+		if metric.Value, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return nil, fmt.Errorf("mlflowfile: invalid value in metric %s: %w", key, err)
+		}
+		if len(fields) == 3 {
+			if metric.Step, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+				return nil, fmt.Errorf("mlflowfile: invalid step in metric %s: %w", key, err)
+			}
+		}
+		history = append(history, metric)
+	}
+	return history, nil
+}
@@ -0,0 +1,30 @@
+// Package mlflowoauth2 adapts golang.org/x/oauth2 token sources for use with
+// mlflow.WithTokenSource.
+package mlflowoauth2
+
+import (
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"golang.org/x/oauth2"
+)
+
+type tokenSource struct {
+	source oauth2.TokenSource
+}
+
+// TokenSource returns an mlflow.TokenSource backed by source, such as the
+// result of oauth2.Config.TokenSource or clientcredentials.Config.TokenSource.
+func TokenSource(source oauth2.TokenSource) mlflow.TokenSource {
+	return tokenSource{source: source}
+}
+
+func (s tokenSource) Token() (*mlflow.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	return &mlflow.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.Type(),
+		Expiry:      token.Expiry,
+	}, nil
+}
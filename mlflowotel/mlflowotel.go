@@ -0,0 +1,103 @@
+// Package mlflowotel records an OpenTelemetry span for every call a
+// mlflow.Client makes to the tracking server.
+package mlflowotel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/neka-nat/go-mlflow.git/mlflowotel"
+
+// WithTracerProvider records client spans with provider and propagates the
+// trace context to the server. Given before mlflow.WithRetry, the span
+// covers all attempts of a call; given after it, each attempt gets a span.
+func WithTracerProvider(provider trace.TracerProvider) mlflow.Option {
+	return mlflow.WithMiddleware(Middleware(provider))
+}
+
+// Middleware returns the mlflow.Middleware used by WithTracerProvider.
+func Middleware(provider trace.TracerProvider) mlflow.Middleware {
+	tracer := provider.Tracer(instrumentationName)
+	return func(next mlflow.RoundTripFunc) mlflow.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			operation := mlflow.Operation(req)
+			attrs := []attribute.KeyValue{
+				attribute.String("mlflow.operation", operation),
+				attribute.String("http.method", req.Method),
+				attribute.String("http.url", req.URL.Redacted()),
+			}
+			attrs = append(attrs, idAttributes(req)...)
+			ctx, span := tracer.Start(req.Context(), "mlflow "+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, fmt.Sprintf("status %d", resp.StatusCode))
+			}
+			return resp, nil
+		}
+	}
+}
+
+var idKeys = map[string]string{
+	"run_id":        "mlflow.run_id",
+	"experiment_id": "mlflow.experiment_id",
+	"name":          "mlflow.model_name",
+	"request_id":    "mlflow.request_id",
+}
+
+// idAttributes picks run, experiment and model ids from the query or the
+// JSON body of req.
+func idAttributes(req *http.Request) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	query := req.URL.Query()
+	for key, attr := range idKeys {
+		if value := query.Get(key); value != "" {
+			attrs = append(attrs, attribute.String(attr, value))
+		}
+	}
+	if req.GetBody == nil || req.Header.Get("Content-Type") != "application/json" || req.Header.Get("Content-Encoding") != "" {
+		return attrs
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return attrs
+	}
+	defer body.Close()
+	var fields map[string]interface{}
+	if json.NewDecoder(body).Decode(&fields) != nil {
+		return attrs
+	}
+	for key, attr := range idKeys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			attrs = append(attrs, attribute.String(attr, value))
+		}
+	}
+	if ids, ok := fields["experiment_ids"].([]interface{}); ok {
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if s, ok := id.(string); ok {
+				values = append(values, s)
+			}
+		}
+		attrs = append(attrs, attribute.StringSlice("mlflow.experiment_ids", values))
+	}
+	return attrs
+}
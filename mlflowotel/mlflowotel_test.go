@@ -0,0 +1,74 @@
+package mlflowotel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/2.0/mlflow/runs/get" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no run"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := mlflow.New(server.URL, WithTracerProvider(provider))
+	ctx := context.Background()
+
+	if err := client.LogMetric(ctx, "run1", "loss", 0.5, 1000, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRun(ctx, "missing"); !mlflow.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	attrs := func(i int) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range spans[i].Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+	if spans[0].Name() != "mlflow runs/log-metric" || attrs(0)["mlflow.run_id"].AsString() != "run1" || attrs(0)["http.status_code"].AsInt64() != 200 {
+		t.Errorf("unexpected span %s %v", spans[0].Name(), attrs(0))
+	}
+	if spans[1].Name() != "mlflow runs/get" || attrs(1)["mlflow.run_id"].AsString() != "missing" || spans[1].Status().Code != codes.Error {
+		t.Errorf("unexpected span %s %v %v", spans[1].Name(), attrs(1), spans[1].Status())
+	}
+}
+
+// TestIdAttributesBadBody checks that the ids of the query are kept when the
+// body cannot be read back or decoded.
+func TestIdAttributesBadBody(t *testing.T) {
+	for name, getBody := range map[string]func() (io.ReadCloser, error){
+		"unreadable": func() (io.ReadCloser, error) { return nil, errors.New("consumed") },
+		"not json":   func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("{")), nil },
+	} {
+		req, _ := http.NewRequest("POST", "http://localhost/api/2.0/mlflow/runs/log-metric?run_id=run1", nil)
+		req.Header.Set("Content-Type", "application/json")
+		req.GetBody = getBody
+		attrs := idAttributes(req)
+		if len(attrs) != 1 || attrs[0] != attribute.String("mlflow.run_id", "run1") {
+			t.Errorf("%s: unexpected attributes %v", name, attrs)
+		}
+	}
+}
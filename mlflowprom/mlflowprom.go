@@ -0,0 +1,154 @@
+// Package mlflowprom exposes Prometheus metrics about the calls a
+// mlflow.Client makes to the tracking server and about the queues of
+// asynchronous loggers.
+package mlflowprom
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Queue is something holding entries not yet sent to the tracking server,
+// such as a *mlflow.AsyncLogger or a *mlflow.Journal.
+type Queue interface {
+	Pending() int
+}
+
+// Metrics is a prometheus.Collector of
+//
+//	mlflow_client_requests_total{operation, code}
+//	mlflow_client_errors_total{operation, error_code}
+//	mlflow_client_request_duration_seconds{operation}
+//	mlflow_client_retries_total{operation}
+//	mlflow_client_queue_depth{queue}
+//
+// Requests that fail without a response are counted with the code "error"
+// and the error code "NETWORK_ERROR".
+type Metrics struct {
+	requests   *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	retries    *prometheus.CounterVec
+	queueDepth *prometheus.Desc
+
+	mu     sync.Mutex
+	queues map[string]Queue
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mlflow_client_requests_total",
+			Help: "Requests sent to the MLflow tracking server, by operation and HTTP status code.",
+		}, []string{"operation", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mlflow_client_errors_total",
+			Help: "Failed requests to the MLflow tracking server, by operation and MLflow error code.",
+		}, []string{"operation", "error_code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mlflow_client_request_duration_seconds",
+			Help:    "Latency of requests to the MLflow tracking server.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mlflow_client_retries_total",
+			Help: "Retried requests to the MLflow tracking server.",
+		}, []string{"operation"}),
+		queueDepth: prometheus.NewDesc("mlflow_client_queue_depth",
+			"Entries buffered by an asynchronous logger or journal and not yet sent.",
+			[]string{"queue"}, nil),
+		queues: map[string]Queue{},
+	}
+}
+
+// WithMetrics records the requests of the client in m. Given after
+// mlflow.WithRetry, every attempt is counted and retries are seen; given
+// before it, a call is counted once whatever the number of attempts.
+func WithMetrics(m *Metrics) mlflow.Option {
+	return mlflow.WithMiddleware(m.Middleware())
+}
+
+// Middleware returns the mlflow.Middleware used by WithMetrics.
+func (m *Metrics) Middleware() mlflow.Middleware {
+	return func(next mlflow.RoundTripFunc) mlflow.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			operation := mlflow.Operation(req)
+			if mlflow.Attempt(req) > 1 {
+				m.retries.WithLabelValues(operation).Inc()
+			}
+			start := time.Now()
+			resp, err := next(req)
+			m.latency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+			if err != nil {
+				m.requests.WithLabelValues(operation, "error").Inc()
+				m.errors.WithLabelValues(operation, "NETWORK_ERROR").Inc()
+				return resp, err
+			}
+			m.requests.WithLabelValues(operation, strconv.Itoa(resp.StatusCode)).Inc()
+			if resp.StatusCode >= 400 {
+				m.errors.WithLabelValues(operation, errorCode(resp)).Inc()
+			}
+			return resp, nil
+		}
+	}
+}
+
+// errorCode reads the MLflow error code from the body of resp, leaving the
+// body in place, and falls back to the status code.
+func errorCode(resp *http.Response) string {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		var failure struct {
+			ErrorCode string `json:"error_code"`
+		}
+		if err == nil && json.Unmarshal(b, &failure) == nil && failure.ErrorCode != "" {
+			return failure.ErrorCode
+		}
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
+
+// WatchQueue reports the number of entries pending in queue under name
+// until UnwatchQueue is called.
+func (m *Metrics) WatchQueue(name string, queue Queue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues[name] = queue
+}
+
+func (m *Metrics) UnwatchQueue(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.queues, name)
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.latency.Describe(ch)
+	m.retries.Describe(ch)
+	ch <- m.queueDepth
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.latency.Collect(ch)
+	m.retries.Collect(ch)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, queue := range m.queues {
+		ch <- prometheus.MustNewConstMetric(m.queueDepth, prometheus.GaugeValue, float64(queue.Pending()), name)
+	}
+}
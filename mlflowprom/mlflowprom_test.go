@@ -0,0 +1,104 @@
+package mlflowprom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWithMetrics(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/get":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no run"}`))
+		case "/api/2.0/mlflow/runs/log-metric":
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	metrics := NewMetrics()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(metrics)
+	policy := mlflow.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	client := mlflow.New(server.URL, mlflow.WithRetry(policy), WithMetrics(metrics))
+	ctx := context.Background()
+
+	if err := client.LogMetric(ctx, "run1", "loss", 0.5, 1000, 0); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.GetRun(ctx, "missing")
+	if !mlflow.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if e, ok := err.(*mlflow.Error); !ok || e.Message != "no run" {
+		t.Errorf("the error body was not kept: %v", err)
+	}
+
+	expected := `
+# HELP mlflow_client_requests_total Requests sent to the MLflow tracking server, by operation and HTTP status code.
+# TYPE mlflow_client_requests_total counter
+mlflow_client_requests_total{code="200",operation="runs/log-metric"} 1
+mlflow_client_requests_total{code="503",operation="runs/log-metric"} 1
+mlflow_client_requests_total{code="404",operation="runs/get"} 1
+# HELP mlflow_client_errors_total Failed requests to the MLflow tracking server, by operation and MLflow error code.
+# TYPE mlflow_client_errors_total counter
+mlflow_client_errors_total{error_code="503",operation="runs/log-metric"} 1
+mlflow_client_errors_total{error_code="RESOURCE_DOES_NOT_EXIST",operation="runs/get"} 1
+# HELP mlflow_client_retries_total Retried requests to the MLflow tracking server.
+# TYPE mlflow_client_retries_total counter
+mlflow_client_retries_total{operation="runs/log-metric"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "mlflow_client_requests_total", "mlflow_client_errors_total", "mlflow_client_retries_total"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(metrics, "mlflow_client_request_duration_seconds"); count != 2 {
+		t.Errorf("expected latency of 2 operations, got %d", count)
+	}
+}
+
+func TestWatchQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	metrics := NewMetrics()
+	client := mlflow.New(server.URL, WithMetrics(metrics))
+	logger := client.NewAsyncLogger(context.Background(), "run1", mlflow.AsyncLoggerOptions{FlushInterval: time.Hour})
+	defer logger.Close(context.Background())
+	metrics.WatchQueue("training", logger)
+	logger.LogMetric("loss", 0.5, 0)
+	logger.LogParam("lr", "0.1")
+
+	expected := `
+# HELP mlflow_client_queue_depth Entries buffered by an asynchronous logger or journal and not yet sent.
+# TYPE mlflow_client_queue_depth gauge
+mlflow_client_queue_depth{queue="training"} 2
+`
+	if err := testutil.CollectAndCompare(metrics, strings.NewReader(expected), "mlflow_client_queue_depth"); err != nil {
+		t.Error(err)
+	}
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	metrics.UnwatchQueue("training")
+	if count := testutil.CollectAndCount(metrics, "mlflow_client_queue_depth"); count != 0 {
+		t.Errorf("expected no queue depth, got %d", count)
+	}
+}
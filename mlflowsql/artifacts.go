@@ -0,0 +1,169 @@
+package mlflowsql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"gopkg.in/yaml.v3"
+)
+
+// artifactRepository returns the repository of a run's artifact_uri. Runs
+// that are written to must be active.
+func (s *Store) artifactRepository(ctx context.Context, runId string, active bool) (mlflow.ArtifactRepository, error) {
+	info, err := s.runInfo(ctx, s.db, runId)
+	if err == nil && active {
+		info, err = s.activeRunInfo(ctx, s.db, runId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.artifacts.ArtifactRepository(info.ArtifactUri)
+}
+
+func (s *Store) LogArtifact(ctx context.Context, runId string, localPath string, artifactPath string) error {
+	repo, err := s.artifactRepository(ctx, runId, true)
+	if err != nil {
+		return err
+	}
+	return repo.Upload(ctx, localPath, path.Join(artifactPath, filepath.Base(localPath)))
+}
+
+func (s *Store) LogArtifacts(ctx context.Context, runId string, localDir string, artifactPath string) error {
+	repo, err := s.artifactRepository(ctx, runId, true)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(localDir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		return repo.Upload(ctx, localPath, path.Join(artifactPath, filepath.ToSlash(rel)))
+	})
+}
+
+// logArtifactBytes uploads data through a temporary file, as repositories
+// upload local files.
+func (s *Store) logArtifactBytes(ctx context.Context, runId string, data []byte, artifactFile string) error {
+	repo, err := s.artifactRepository(ctx, runId, true)
+	if err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir("", "mlflowsql")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	localPath := filepath.Join(dir, path.Base(artifactFile))
+	if err := ioutil.WriteFile(localPath, data, 0644); err != nil {
+		return err
+	}
+	return repo.Upload(ctx, localPath, artifactFile)
+}
+
+// LogDict serializes obj as mlflow.Client does: YAML for .yaml and .yml
+// files, indented JSON otherwise.
+func (s *Store) LogDict(ctx context.Context, runId string, obj interface{}, artifactFile string) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path.Ext(artifactFile)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+	}
+	return s.logArtifactBytes(ctx, runId, data, artifactFile)
+}
+
+func (s *Store) LogText(ctx context.Context, runId string, text string, artifactFile string) error {
+	return s.logArtifactBytes(ctx, runId, []byte(text), artifactFile)
+}
+
+func (s *Store) ListArtifacts(ctx context.Context, runId string, dir string) ([]mlflow.FileInfo, error) {
+	repo, err := s.artifactRepository(ctx, runId, false)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, dir)
+}
+
+// OpenArtifact downloads the artifact to a temporary file, removed when
+// the returned reader is closed.
+func (s *Store) OpenArtifact(ctx context.Context, runId string, artifactPath string) (io.ReadCloser, error) {
+	repo, err := s.artifactRepository(ctx, runId, false)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir("", "mlflowsql")
+	if err != nil {
+		return nil, err
+	}
+	localPath := filepath.Join(dir, path.Base(artifactPath))
+	if err := repo.Download(ctx, artifactPath, localPath); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &tempFile{File: f, dir: dir}, nil
+}
+
+type tempFile struct {
+	*os.File
+	dir string
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	os.RemoveAll(f.dir)
+	return err
+}
+
+// DownloadArtifacts copies the file at remotePath, or every file under it,
+// to the same relative path under localDir. Download options are ignored.
+func (s *Store) DownloadArtifacts(ctx context.Context, runId string, remotePath string, localDir string, opts ...mlflow.DownloadOption) error {
+	repo, err := s.artifactRepository(ctx, runId, false)
+	if err != nil {
+		return err
+	}
+	files, err := repo.List(ctx, remotePath)
+	if err != nil || len(files) == 0 {
+		// Repositories list a file as nothing or fail to list it.
+		if remotePath == "" {
+			return err
+		}
+		return repo.Download(ctx, remotePath, filepath.Join(localDir, filepath.FromSlash(remotePath)))
+	}
+	for _, file := range files {
+		if file.IsDir {
+			if err := s.DownloadArtifacts(ctx, runId, file.Path, localDir); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := repo.Download(ctx, file.Path, filepath.Join(localDir, filepath.FromSlash(file.Path))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
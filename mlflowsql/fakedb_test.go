@@ -0,0 +1,259 @@
+package mlflowsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// fakeDriver is a database/sql driver keeping tables in memory, understanding
+// just the statements Store sends: single table SELECT, INSERT, UPDATE and
+// DELETE with conditions of the form column = ? joined by AND. Placeholders
+// may be ? or $n, and identifiers may be quoted with " or `.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+type fakeRow map[string]driver.Value
+
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string][]fakeRow
+}
+
+var fakeSQL = &fakeDriver{dbs: map[string]*fakeDB{}}
+
+func init() {
+	sql.Register("mlflowsqlfake", fakeSQL)
+}
+
+// openFake opens an empty database named name.
+func openFake(name string) (*sql.DB, error) {
+	fakeSQL.mu.Lock()
+	fakeSQL.dbs[name] = &fakeDB{tables: map[string][]fakeRow{}}
+	fakeSQL.mu.Unlock()
+	return sql.Open("mlflowsqlfake", name)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		return nil, fmt.Errorf("fakedb: no database %q", name)
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db       *fakeDB
+	snapshot map[string][]fakeRow
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = map[string][]fakeRow{}
+	for name, rows := range c.db.tables {
+		for _, row := range rows {
+			copied := fakeRow{}
+			for k, v := range row {
+				copied[k] = v
+			}
+			c.snapshot[name] = append(c.snapshot[name], copied)
+		}
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.tables, c.snapshot = c.snapshot, nil
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+type fakeResult struct {
+	lastInsertId, rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastInsertId, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+var (
+	fakePlaceholder = regexp.MustCompile(`\$\d+`)
+	fakeSelect      = regexp.MustCompile(`^SELECT (.+) FROM (\w+)(?: WHERE (.+))?$`)
+	fakeInsert      = regexp.MustCompile(`^INSERT INTO (\w+) \((.+)\) VALUES \((.+)\)(?: RETURNING (\w+))?$`)
+	fakeUpdate      = regexp.MustCompile(`^UPDATE (\w+) SET (.+) WHERE (.+)$`)
+	fakeDelete      = regexp.MustCompile(`^DELETE FROM (\w+) WHERE (.+)$`)
+)
+
+func splitList(s string, sep string) []string {
+	parts := strings.Split(s, sep)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// assignments parses "a = ?, b = ?" or "a = ? AND b = ?", consuming args.
+func assignments(s string, sep string, args []driver.Value) (map[string]driver.Value, []driver.Value, error) {
+	values := map[string]driver.Value{}
+	for _, part := range splitList(s, sep) {
+		column := strings.TrimSuffix(part, " = ?")
+		if column == part || len(args) == 0 {
+			return nil, nil, fmt.Errorf("fakedb: cannot parse %q", part)
+		}
+		values[column] = args[0]
+		args = args[1:]
+	}
+	return values, args, nil
+}
+
+func (r fakeRow) matches(where map[string]driver.Value) bool {
+	for column, value := range where {
+		if fmt.Sprint(r[column]) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, _, err := s.run(args)
+	return result, err
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	_, rows, err := s.run(args)
+	return rows, err
+}
+
+func (s *fakeStmt) run(args []driver.Value) (driver.Result, driver.Rows, error) {
+	query := strings.NewReplacer(`"`, "", "`", "").Replace(fakePlaceholder.ReplaceAllString(s.query, "?"))
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if m := fakeSelect.FindStringSubmatch(query); m != nil {
+		where := map[string]driver.Value{}
+		if m[3] != "" {
+			var err error
+			if where, _, err = assignments(m[3], " AND ", args); err != nil {
+				return nil, nil, err
+			}
+		}
+		rows := &fakeRows{columns: splitList(m[1], ",")}
+		for _, row := range db.tables[m[2]] {
+			if row.matches(where) {
+				values := make([]driver.Value, len(rows.columns))
+				for i, column := range rows.columns {
+					values[i] = row[column]
+				}
+				rows.rows = append(rows.rows, values)
+			}
+		}
+		return nil, rows, nil
+	}
+	if m := fakeInsert.FindStringSubmatch(query); m != nil {
+		columns := splitList(m[2], ",")
+		if len(columns) != len(args) {
+			return nil, nil, fmt.Errorf("fakedb: %d columns but %d values", len(columns), len(args))
+		}
+		row := fakeRow{}
+		for i, column := range columns {
+			row[column] = args[i]
+		}
+		var id int64
+		if m[1] == "experiments" {
+			for _, existing := range db.tables[m[1]] {
+				if n := existing["experiment_id"].(int64); n >= id {
+					id = n + 1
+				}
+			}
+			row["experiment_id"] = id
+		}
+		db.tables[m[1]] = append(db.tables[m[1]], row)
+		rows := &fakeRows{}
+		if m[4] != "" {
+			rows.columns, rows.rows = []string{m[4]}, [][]driver.Value{{row[m[4]]}}
+		}
+		return fakeResult{lastInsertId: id, rowsAffected: 1}, rows, nil
+	}
+	if m := fakeUpdate.FindStringSubmatch(query); m != nil {
+		set, rest, err := assignments(m[2], ",", args)
+		if err != nil {
+			return nil, nil, err
+		}
+		where, _, err := assignments(m[3], " AND ", rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		var n int64
+		for _, row := range db.tables[m[1]] {
+			if row.matches(where) {
+				for column, value := range set {
+					row[column] = value
+				}
+				n++
+			}
+		}
+		return fakeResult{rowsAffected: n}, &fakeRows{}, nil
+	}
+	if m := fakeDelete.FindStringSubmatch(query); m != nil {
+		where, _, err := assignments(m[2], " AND ", args)
+		if err != nil {
+			return nil, nil, err
+		}
+		var kept []fakeRow
+		for _, row := range db.tables[m[1]] {
+			if !row.matches(where) {
+				kept = append(kept, row)
+			}
+		}
+		n := int64(len(db.tables[m[1]]) - len(kept))
+		db.tables[m[1]] = kept
+		return fakeResult{rowsAffected: n}, &fakeRows{}, nil
+	}
+	return nil, nil, fmt.Errorf("fakedb: unsupported query %q", s.query)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,190 @@
+package mlflowsql
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+var _ mlflow.GarbageCollector = (*Store)(nil)
+
+// runTables are the tables holding rows of a run, deleted before the run.
+var runTables = []string{"metrics", "latest_metrics", "params", "tags"}
+
+// traceTables are the tables holding rows of a trace, deleted before its
+// trace_info row.
+var traceTables = []string{"trace_tags", "trace_request_metadata"}
+
+// GC permanently removes the runs whose deleted_time is older than
+// olderThan and the deleted experiments whose last_update_time is, with all
+// their runs, as `mlflow gc` does. Runs deleted without a deleted_time are
+// only removed with olderThan 0. The inputs of the runs and the datasets and
+// traces of the experiments are removed too. Artifacts are deleted through
+// mlflow.ArtifactRepository before the rows.
+func (s *Store) GC(ctx context.Context, olderThan time.Duration) (*mlflow.GCResult, error) {
+	threshold := mlflow.Millis(time.Now().Add(-olderThan))
+	expired := func(t sql.NullInt64) bool {
+		return olderThan <= 0 || t.Valid && t.Int64 <= threshold
+	}
+	result := &mlflow.GCResult{}
+
+	rows, err := s.query(ctx, s.db, `SELECT run_uuid, deleted_time FROM runs WHERE lifecycle_stage = ?`, "deleted")
+	if err != nil {
+		return nil, err
+	}
+	var runIds []string
+	for rows.Next() {
+		var runId string
+		var deletedTime sql.NullInt64
+		if err := rows.Scan(&runId, &deletedTime); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if expired(deletedTime) {
+			runIds = append(runIds, runId)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	experiments, err := s.scanExperiments(ctx, s.db, ` WHERE lifecycle_stage = ?`, "deleted")
+	if err != nil {
+		return nil, err
+	}
+	var experimentIds []int64
+	for _, e := range experiments {
+		if !expired(sql.NullInt64{Int64: e.LastUpdateTime, Valid: true}) {
+			continue
+		}
+		n, _ := experimentNumber(e.ExperimentId)
+		infos, err := s.scanRunInfos(ctx, s.db, ` WHERE experiment_id = ?`, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			runIds = append(runIds, info.RunId)
+		}
+		experimentIds = append(experimentIds, n)
+		result.ExperimentIds = append(result.ExperimentIds, e.ExperimentId)
+	}
+
+	sort.Strings(runIds)
+	for i, runId := range runIds {
+		if i > 0 && runIds[i-1] == runId {
+			continue
+		}
+		if err := s.removeRun(ctx, runId); err != nil {
+			return nil, err
+		}
+		result.RunIds = append(result.RunIds, runId)
+	}
+	for _, n := range experimentIds {
+		if err := s.inTx(ctx, func(tx *sql.Tx) error { return s.removeExperiment(ctx, tx, n) }); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// removeExperiment deletes the rows of an experiment whose runs are removed,
+// children before the rows they refer to.
+func (s *Store) removeExperiment(ctx context.Context, tx *sql.Tx, n int64) error {
+	requestIds, err := s.column(ctx, tx, `SELECT request_id FROM trace_info WHERE experiment_id = ?`, n)
+	if err != nil {
+		return err
+	}
+	for _, requestId := range requestIds {
+		for _, table := range traceTables {
+			if _, err := s.exec(ctx, tx, `DELETE FROM `+table+` WHERE request_id = ?`, requestId); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.exec(ctx, tx, `DELETE FROM trace_info WHERE experiment_id = ?`, n); err != nil {
+		return err
+	}
+	datasetIds, err := s.column(ctx, tx, `SELECT dataset_uuid FROM datasets WHERE experiment_id = ?`, n)
+	if err != nil {
+		return err
+	}
+	for _, datasetId := range datasetIds {
+		if err := s.removeInputs(ctx, tx, `source_type = ? AND source_id = ?`, "DATASET", datasetId); err != nil {
+			return err
+		}
+	}
+	if _, err := s.exec(ctx, tx, `DELETE FROM datasets WHERE experiment_id = ?`, n); err != nil {
+		return err
+	}
+	if _, err := s.exec(ctx, tx, `DELETE FROM experiment_tags WHERE experiment_id = ?`, n); err != nil {
+		return err
+	}
+	_, err = s.exec(ctx, tx, `DELETE FROM experiments WHERE experiment_id = ?`, n)
+	return err
+}
+
+// removeInputs deletes the inputs rows matching where and their input_tags.
+func (s *Store) removeInputs(ctx context.Context, tx *sql.Tx, where string, args ...interface{}) error {
+	inputIds, err := s.column(ctx, tx, `SELECT input_uuid FROM inputs WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	for _, inputId := range inputIds {
+		if _, err := s.exec(ctx, tx, `DELETE FROM input_tags WHERE input_uuid = ?`, inputId); err != nil {
+			return err
+		}
+	}
+	_, err = s.exec(ctx, tx, `DELETE FROM inputs WHERE `+where, args...)
+	return err
+}
+
+// column returns the first column of the rows of query.
+func (s *Store) column(ctx context.Context, q querier, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.query(ctx, q, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// removeRun deletes the artifacts of a run and then its rows.
+func (s *Store) removeRun(ctx context.Context, runId string) error {
+	info, err := s.runInfo(ctx, s.db, runId)
+	if err != nil {
+		return err
+	}
+	if info.ArtifactUri != "" {
+		repo, err := s.artifacts.ArtifactRepository(info.ArtifactUri)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, ""); err != nil {
+			return err
+		}
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.removeInputs(ctx, tx, `destination_type = ? AND destination_id = ?`, "RUN", runId); err != nil {
+			return err
+		}
+		for _, table := range runTables {
+			if _, err := s.exec(ctx, tx, `DELETE FROM `+table+` WHERE run_uuid = ?`, runId); err != nil {
+				return err
+			}
+		}
+		_, err := s.exec(ctx, tx, `DELETE FROM runs WHERE run_uuid = ?`, runId)
+		return err
+	})
+}
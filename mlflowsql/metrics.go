@@ -0,0 +1,190 @@
+package mlflowsql
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+func (s *Store) LogMetric(ctx context.Context, runId string, key string, value float64, timestamp int64, step int64) error {
+	return s.LogBatch(ctx, runId, []mlflow.Metric{{Key: key, Value: value, Timestamp: timestamp, Step: step}}, nil, nil)
+}
+
+func (s *Store) LogMetricAt(ctx context.Context, runId string, key string, value float64, t time.Time, step int64) error {
+	return s.LogMetric(ctx, runId, key, value, mlflow.Millis(t), step)
+}
+
+func (s *Store) LogMetrics(ctx context.Context, runId string, metrics map[string]float64, step int64) error {
+	timestamp := mlflow.Millis(time.Now())
+	var batch []mlflow.Metric
+	for _, key := range sortedKeys(metrics) {
+		batch = append(batch, mlflow.Metric{Key: key, Value: metrics[key], Timestamp: timestamp, Step: step})
+	}
+	return s.LogBatch(ctx, runId, batch, nil, nil)
+}
+
+func (s *Store) LogParam(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, []mlflow.Param{{Key: key, Value: value}}, nil)
+}
+
+func (s *Store) LogParams(ctx context.Context, runId string, params map[string]string) error {
+	var batch []mlflow.Param
+	for _, key := range sortedKeys(params) {
+		batch = append(batch, mlflow.Param{Key: key, Value: params[key]})
+	}
+	return s.LogBatch(ctx, runId, nil, batch, nil)
+}
+
+func (s *Store) LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error {
+	params, err := mlflow.FlattenParams(cfg)
+	if err != nil {
+		return err
+	}
+	return s.LogBatch(ctx, runId, nil, params, nil)
+}
+
+// LogBatch writes the batch in one transaction: when a param would change
+// the value it was logged with, nothing is logged and
+// INVALID_PARAMETER_VALUE is returned, as the tracking server does.
+func (s *Store) LogBatch(ctx context.Context, runId string, metrics []mlflow.Metric, params []mlflow.Param, tags []mlflow.RunTag) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.activeRunInfo(ctx, tx, runId); err != nil {
+			return err
+		}
+		for _, param := range params {
+			if err := s.logParam(ctx, tx, runId, param); err != nil {
+				return err
+			}
+		}
+		for _, metric := range metrics {
+			if err := s.logMetric(ctx, tx, runId, metric); err != nil {
+				return err
+			}
+		}
+		for _, tag := range tags {
+			if err := s.setTag(ctx, tx, runId, tag.Key, tag.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) logParam(ctx context.Context, tx *sql.Tx, runId string, param mlflow.Param) error {
+	existing, err := s.keyValues(ctx, tx, `SELECT "key", "value" FROM params WHERE run_uuid = ? AND "key" = ?`, runId, param.Key)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		if existing[0][1] != param.Value {
+			return invalidParameter("Changing param values is not allowed. Param with key='%s' was already logged with value='%s' for run ID='%s'. Attempted logging new value '%s'.", param.Key, existing[0][1], runId, param.Value)
+		}
+		return nil
+	}
+	_, err = s.exec(ctx, tx, `INSERT INTO params ("key", "value", run_uuid) VALUES (?, ?, ?)`, param.Key, param.Value, runId)
+	return err
+}
+
+// metricValue returns the value and is_nan columns of a metric value. The
+// schema stores NaN as 0 with is_nan set, and infinities as the largest
+// finite floats, as the tracking server does.
+func metricValue(value float64) (float64, bool) {
+	switch {
+	case math.IsNaN(value):
+		return 0, true
+	case math.IsInf(value, 1):
+		return math.MaxFloat64, false
+	case math.IsInf(value, -1):
+		return -math.MaxFloat64, false
+	}
+	return value, false
+}
+
+// logMetric inserts a metric point and replaces the run's latest value of
+// the metric when the point has a later step, or the same step and a later
+// timestamp.
+func (s *Store) logMetric(ctx context.Context, tx *sql.Tx, runId string, metric mlflow.Metric) error {
+	value, isNan := metricValue(metric.Value)
+	_, err := s.exec(ctx, tx, `INSERT INTO metrics ("key", "value", timestamp, run_uuid, step, is_nan) VALUES (?, ?, ?, ?, ?, ?)`, metric.Key, value, metric.Timestamp, runId, metric.Step, isNan)
+	if err != nil {
+		return err
+	}
+	rows, err := s.query(ctx, tx, `SELECT "key", "value", timestamp, step, is_nan FROM latest_metrics WHERE run_uuid = ? AND "key" = ?`, runId, metric.Key)
+	if err != nil {
+		return err
+	}
+	latest, err := scanMetrics(rows)
+	if err != nil {
+		return err
+	}
+	if len(latest) > 0 {
+		if l := latest[0]; metric.Step < l.Step || metric.Step == l.Step && metric.Timestamp < l.Timestamp {
+			return nil
+		}
+		if _, err := s.exec(ctx, tx, `DELETE FROM latest_metrics WHERE run_uuid = ? AND "key" = ?`, runId, metric.Key); err != nil {
+			return err
+		}
+	}
+	_, err = s.exec(ctx, tx, `INSERT INTO latest_metrics ("key", "value", timestamp, step, is_nan, run_uuid) VALUES (?, ?, ?, ?, ?, ?)`, metric.Key, value, metric.Timestamp, metric.Step, isNan, runId)
+	return err
+}
+
+// scanMetrics reads and closes rows of key, value, timestamp, step and
+// is_nan.
+func scanMetrics(rows *sql.Rows) ([]mlflow.Metric, error) {
+	defer rows.Close()
+	var metrics []mlflow.Metric
+	for rows.Next() {
+		var metric mlflow.Metric
+		var timestamp, step sql.NullInt64
+		var isNan sql.NullBool
+		if err := rows.Scan(&metric.Key, &metric.Value, &timestamp, &step, &isNan); err != nil {
+			return nil, err
+		}
+		metric.Timestamp, metric.Step = timestamp.Int64, step.Int64
+		if isNan.Bool {
+			metric.Value = math.NaN()
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics, rows.Err()
+}
+
+// sortMetrics orders metrics by key, then step and timestamp.
+func sortMetrics(metrics []mlflow.Metric) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		a, b := metrics[i], metrics[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Step != b.Step {
+			return a.Step < b.Step
+		}
+		return a.Timestamp < b.Timestamp
+	})
+}
+
+func (s *Store) GetMetricHistory(ctx context.Context, runId string, metricKey string, maxResults int, pageToken string) (*mlflow.ResponseGetMetricHistory, error) {
+	if _, err := s.runInfo(ctx, s.db, runId); err != nil {
+		return nil, err
+	}
+	rows, err := s.query(ctx, s.db, `SELECT "key", "value", timestamp, step, is_nan FROM metrics WHERE run_uuid = ? AND "key" = ?`, runId, metricKey)
+	if err != nil {
+		return nil, err
+	}
+	history, err := scanMetrics(rows)
+	if err != nil {
+		return nil, err
+	}
+	sortMetrics(history)
+	page, next, err := search.Page(history, maxResults, pageToken, 25000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseGetMetricHistory{Metrics: page, NextPageToken: next}, nil
+}
@@ -0,0 +1,381 @@
+// Package mlflowsql implements mlflow.API directly against the backend
+// database of a tracking server, in the schema MLflow's migrations create,
+// for jobs that can reach the database but not a tracking server. The
+// database/sql driver is chosen by the caller, who opens the *sql.DB:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	...
+//	store := mlflowsql.New(db, mlflowsql.Options{Dialect: mlflowsql.PostgreSQL, DefaultArtifactRoot: "s3://bucket/mlflow"})
+//
+// The schema must already exist, as created by `mlflow server` or `mlflow db
+// upgrade`. Experiments, runs, metrics, params, tags and artifacts are
+// implemented, artifacts through mlflow.ArtifactRepository; the model
+// registry, traces and webhooks are not. Filters are evaluated in Go on the
+// runs of the searched experiments, not translated to SQL.
+package mlflowsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+)
+
+// Dialect is the SQL dialect of the database.
+type Dialect string
+
+const (
+	SQLite     Dialect = "sqlite"
+	PostgreSQL Dialect = "postgresql"
+	MySQL      Dialect = "mysql"
+)
+
+// Options configures a Store. Zero values select the defaults.
+type Options struct {
+	// Dialect defaults to SQLite.
+	Dialect Dialect
+	// DefaultArtifactRoot is the uri under which new experiments store
+	// their artifacts, in a directory named by their id. Defaults to
+	// ./mlruns, as for `mlflow server`.
+	DefaultArtifactRoot string
+	// ArtifactClient resolves the artifact repositories of runs. Defaults to
+	// a client without a tracking server, which supports every artifact
+	// uri but mlflow-artifacts.
+	ArtifactClient *mlflow.Client
+}
+
+// Store is an MLflow backend database. Create it with New.
+type Store struct {
+	mlflow.UnimplementedAPI
+
+	db        *sql.DB
+	opts      Options
+	artifacts *mlflow.Client
+}
+
+var _ mlflow.API = (*Store)(nil)
+
+func New(db *sql.DB, opts Options) *Store {
+	if opts.Dialect == "" {
+		opts.Dialect = SQLite
+	}
+	if opts.DefaultArtifactRoot == "" {
+		root, err := filepath.Abs("mlruns")
+		if err != nil {
+			root = "mlruns"
+		}
+		opts.DefaultArtifactRoot = (&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()
+	}
+	artifacts := opts.ArtifactClient
+	if artifacts == nil {
+		artifacts = mlflow.New("")
+	}
+	return &Store{db: db, opts: opts, artifacts: artifacts}
+}
+
+func newError(statusCode int, errorCode string, format string, args ...interface{}) error {
+	return &mlflow.Error{StatusCode: statusCode, ErrorCode: errorCode, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return newError(http.StatusNotFound, mlflow.ErrorCodeResourceDoesNotExist, format, args...)
+}
+
+func invalidParameter(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeInvalidParameterValue, format, args...)
+}
+
+func alreadyExists(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, mlflow.ErrorCodeResourceAlreadyExists, format, args...)
+}
+
+// querier is a *sql.DB or a *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rebind converts a query written with ? placeholders and double quoted
+// identifiers, such as the "key" and "value" columns, to the dialect.
+func (s *Store) rebind(query string) string {
+	switch s.opts.Dialect {
+	case PostgreSQL:
+		var b strings.Builder
+		n := 0
+		for _, c := range query {
+			if c == '?' {
+				n++
+				fmt.Fprintf(&b, "$%d", n)
+				continue
+			}
+			b.WriteRune(c)
+		}
+		return b.String()
+	case MySQL:
+		return strings.ReplaceAll(query, `"`, "`")
+	}
+	return query
+}
+
+func (s *Store) exec(ctx context.Context, q querier, query string, args ...interface{}) (sql.Result, error) {
+	return q.ExecContext(ctx, s.rebind(query), args...)
+}
+
+func (s *Store) query(ctx context.Context, q querier, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.QueryContext(ctx, s.rebind(query), args...)
+}
+
+// inTx runs fn in a transaction, committed when it returns nil.
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// experimentNumber parses an experiment id, which the schema stores as an
+// integer.
+func experimentNumber(experimentId string) (int64, error) {
+	n, err := strconv.ParseInt(experimentId, 10, 64)
+	if err != nil {
+		return 0, notFound("No Experiment with id=%s exists", experimentId)
+	}
+	return n, nil
+}
+
+const experimentColumns = `experiment_id, name, artifact_location, lifecycle_stage, creation_time, last_update_time`
+
+func (s *Store) scanExperiments(ctx context.Context, q querier, where string, args ...interface{}) ([]*mlflow.Experiment, error) {
+	rows, err := s.query(ctx, q, `SELECT `+experimentColumns+` FROM experiments`+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	var experiments []*mlflow.Experiment
+	for rows.Next() {
+		var id int64
+		var artifactLocation, lifecycleStage sql.NullString
+		var creationTime, lastUpdateTime sql.NullInt64
+		e := &mlflow.Experiment{}
+		if err := rows.Scan(&id, &e.Name, &artifactLocation, &lifecycleStage, &creationTime, &lastUpdateTime); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.ExperimentId = strconv.FormatInt(id, 10)
+		e.ArtifactLocation, e.LifecycleStage = artifactLocation.String, lifecycleStage.String
+		e.CreationTime, e.LastUpdateTime = creationTime.Int64, lastUpdateTime.Int64
+		experiments = append(experiments, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, e := range experiments {
+		n, _ := experimentNumber(e.ExperimentId)
+		tags, err := s.keyValues(ctx, q, `SELECT "key", "value" FROM experiment_tags WHERE experiment_id = ?`, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			e.Tags = append(e.Tags, mlflow.ExperimentTag{Key: tag[0], Value: tag[1]})
+		}
+	}
+	return experiments, nil
+}
+
+// keyValues returns the key and value pairs a query selects, sorted by key.
+func (s *Store) keyValues(ctx context.Context, q querier, query string, args ...interface{}) ([][2]string, error) {
+	rows, err := s.query(ctx, q, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := map[string]string{}
+	for rows.Next() {
+		var key string
+		var value sql.NullString
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var pairs [][2]string
+	for _, key := range sortedKeys(values) {
+		pairs = append(pairs, [2]string{key, values[key]})
+	}
+	return pairs, nil
+}
+
+func (s *Store) experiment(ctx context.Context, q querier, experimentId string) (*mlflow.Experiment, error) {
+	n, err := experimentNumber(experimentId)
+	if err != nil {
+		return nil, err
+	}
+	experiments, err := s.scanExperiments(ctx, q, ` WHERE experiment_id = ?`, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(experiments) == 0 {
+		return nil, notFound("No Experiment with id=%s exists", experimentId)
+	}
+	return experiments[0], nil
+}
+
+func (s *Store) GetExperiment(ctx context.Context, experimentId string) (*mlflow.Experiment, error) {
+	return s.experiment(ctx, s.db, experimentId)
+}
+
+func (s *Store) GetExperimentsByName(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	experiments, err := s.scanExperiments(ctx, s.db, ` WHERE name = ?`, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(experiments) == 0 {
+		return nil, notFound("Could not find experiment with name '%s'", name)
+	}
+	return experiments[0], nil
+}
+
+func (s *Store) GetOrCreateExperiment(ctx context.Context, name string) (*mlflow.Experiment, error) {
+	experiment, err := s.GetExperimentsByName(ctx, name)
+	if !mlflow.IsNotFound(err) {
+		return experiment, err
+	}
+	experimentId, err := s.CreateExperiment(ctx, name)
+	if mlflow.IsAlreadyExists(err) {
+		return s.GetExperimentsByName(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExperiment(ctx, *experimentId)
+}
+
+func (s *Store) SearchExperiments(ctx context.Context, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchExperiments, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	experiments, err := s.scanExperiments(ctx, s.db, "")
+	if err != nil {
+		return nil, err
+	}
+	var matched []mlflow.Experiment
+	for _, e := range experiments {
+		if store.InView(e.LifecycleStage, viewType) && f.Match(store.ExperimentLookup(e)) {
+			matched = append(matched, *e)
+		}
+	}
+	store.SortExperiments(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchExperiments{Experiments: page, NextPageToken: next}, nil
+}
+
+// CreateExperiment inserts an experiment, whose id the database assigns,
+// with its artifacts under DefaultArtifactRoot.
+func (s *Store) CreateExperiment(ctx context.Context, name string) (*string, error) {
+	if name == "" {
+		return nil, invalidParameter("Invalid experiment name: ''")
+	}
+	var experimentId string
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		existing, err := s.scanExperiments(ctx, tx, ` WHERE name = ?`, name)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return alreadyExists("Experiment '%s' already exists.", name)
+		}
+		now := mlflow.Millis(time.Now())
+		insert := `INSERT INTO experiments (name, lifecycle_stage, creation_time, last_update_time) VALUES (?, ?, ?, ?)`
+		var id int64
+		if s.opts.Dialect == PostgreSQL {
+			err = tx.QueryRowContext(ctx, s.rebind(insert+` RETURNING experiment_id`), name, "active", now, now).Scan(&id)
+		} else {
+			var result sql.Result
+			result, err = s.exec(ctx, tx, insert, name, "active", now, now)
+			if err == nil {
+				id, err = result.LastInsertId()
+			}
+		}
+		if err != nil {
+			return err
+		}
+		experimentId = strconv.FormatInt(id, 10)
+		location := strings.TrimSuffix(s.opts.DefaultArtifactRoot, "/") + "/" + experimentId
+		_, err = s.exec(ctx, tx, `UPDATE experiments SET artifact_location = ? WHERE experiment_id = ?`, location, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &experimentId, nil
+}
+
+func (s *Store) SetExperimentTag(ctx context.Context, experimentId string, key string, value string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		n, err := experimentNumber(experimentId)
+		if err != nil {
+			return err
+		}
+		if _, err := s.experiment(ctx, tx, experimentId); err != nil {
+			return err
+		}
+		if _, err := s.exec(ctx, tx, `DELETE FROM experiment_tags WHERE experiment_id = ? AND "key" = ?`, n, key); err != nil {
+			return err
+		}
+		_, err = s.exec(ctx, tx, `INSERT INTO experiment_tags ("key", "value", experiment_id) VALUES (?, ?, ?)`, key, value, n)
+		return err
+	})
+}
+
+func (s *Store) SetExperimentNote(ctx context.Context, experimentId string, note string) error {
+	return s.SetExperimentTag(ctx, experimentId, mlflow.TagNote, note)
+}
+
+// DefaultExperimentId resolves MLFLOW_EXPERIMENT_NAME and
+// MLFLOW_EXPERIMENT_ID as mlflow.Client does.
+func (s *Store) DefaultExperimentId(ctx context.Context) (string, error) {
+	if name := os.Getenv("MLFLOW_EXPERIMENT_NAME"); name != "" {
+		experiment, err := s.GetOrCreateExperiment(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return experiment.ExperimentId, nil
+	}
+	if experimentId := os.Getenv("MLFLOW_EXPERIMENT_ID"); experimentId != "" {
+		return experimentId, nil
+	}
+	return "0", nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
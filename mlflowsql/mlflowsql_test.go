@@ -0,0 +1,330 @@
+package mlflowsql
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func TestStore(t *testing.T) {
+	for _, dialect := range []Dialect{SQLite, PostgreSQL, MySQL} {
+		t.Run(string(dialect), func(t *testing.T) {
+			db, err := openFake(t.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			testStore(t, db, dialect)
+		})
+	}
+}
+
+// testStore runs the tracking methods of a Store on db, a fresh database.
+func testStore(t *testing.T, db *sql.DB, dialect Dialect) {
+	root := t.TempDir()
+	s := New(db, Options{Dialect: dialect, DefaultArtifactRoot: root})
+	ctx := context.Background()
+
+	experiment, err := s.GetOrCreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if experiment.ArtifactLocation != root+"/"+experiment.ExperimentId || experiment.LifecycleStage != "active" {
+		t.Errorf("unexpected experiment %+v", experiment)
+	}
+	if _, err := s.CreateExperiment(ctx, "exp"); !mlflow.IsAlreadyExists(err) {
+		t.Errorf("expected a duplicate name to fail, got %v", err)
+	}
+	if err := s.SetExperimentTag(ctx, experiment.ExperimentId, "team", "ml"); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := s.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("train"), mlflow.WithTags(map[string]string{mlflow.TagUser: "alice"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if run.Info.RunName != "train" || run.Info.UserId != "alice" || run.Info.Status != string(mlflow.Running) {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if err := s.LogParams(ctx, runId, map[string]string{"lr": "0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	for step, loss := range []float64{0.5, 0.25, math.NaN()} {
+		if err := s.LogMetric(ctx, runId, "loss", loss, int64(1000+step), int64(step)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.LogMetric(ctx, runId, "acc", 0.9, 1000, 0); err != nil {
+		t.Fatal(err)
+	}
+	err = s.LogBatch(ctx, runId, nil, []mlflow.Param{{Key: "epochs", Value: "3"}, {Key: "lr", Value: "0.2"}}, nil)
+	if !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected changing a param to fail, got %v", err)
+	}
+	if _, err := s.UpdateRunWithEndTime(ctx, runId, mlflow.Finished, 2000); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err = s.GetRun(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.Status != string(mlflow.Finished) || run.Info.EndTime != 2000 {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if len(run.Data.Params) != 1 {
+		t.Errorf("expected the failed batch to log nothing, got %+v", run.Data.Params)
+	}
+	if loss, _ := run.Metric("loss"); !math.IsNaN(loss) {
+		t.Errorf("expected the latest loss to be NaN, got %v", loss)
+	}
+	history, err := s.GetMetricHistory(ctx, runId, "loss", 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Metrics) != 3 || history.Metrics[1].Value != 0.25 || !math.IsNaN(history.Metrics[2].Value) {
+		t.Errorf("unexpected history %+v", history.Metrics)
+	}
+
+	other, err := s.CreateRun(ctx, experiment.ExperimentId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LogMetric(ctx, other.Info.RunId, "acc", 0.5, 1000, 0); err != nil {
+		t.Fatal(err)
+	}
+	response, err := s.SearchRuns(ctx, []string{experiment.ExperimentId}, "metrics.acc > 0.6", mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 1 || response.Runs[0].Info.RunId != runId {
+		t.Errorf("unexpected search result %+v", response.Runs)
+	}
+	if err := s.DeleteRun(ctx, other.Info.RunId); err != nil {
+		t.Fatal(err)
+	}
+	response, err = s.SearchRuns(ctx, []string{experiment.ExperimentId}, "", mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 1 {
+		t.Errorf("expected the deleted run to be hidden, got %d runs", len(response.Runs))
+	}
+	if err := s.LogParam(ctx, other.Info.RunId, "a", "b"); !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected logging to a deleted run to fail, got %v", err)
+	}
+
+	experiments, err := s.SearchExperiments(ctx, "tags.team = 'ml'", mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(experiments.Experiments) != 1 || experiments.Experiments[0].Name != "exp" {
+		t.Errorf("unexpected experiments %+v", experiments.Experiments)
+	}
+	if _, err := s.GetExperiment(ctx, "42"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected a missing experiment, got %v", err)
+	}
+	if _, err := s.GetRun(ctx, "missing"); !mlflow.IsNotFound(err) {
+		t.Errorf("expected a missing run, got %v", err)
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	db, err := openFake(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := New(db, Options{DefaultArtifactRoot: t.TempDir()})
+	ctx := context.Background()
+	experimentId, err := s.CreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := s.CreateRun(ctx, *experimentId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := s.LogText(ctx, runId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LogDict(ctx, runId, map[string]int{"a": 1}, "config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := s.ListArtifacts(ctx, runId, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "config.yaml" || !files[1].IsDir {
+		t.Errorf("unexpected files %+v", files)
+	}
+	r, err := s.OpenArtifact(ctx, runId, "notes/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil || string(b) != "hello" {
+		t.Errorf("unexpected artifact %q, %v", b, err)
+	}
+	dir := t.TempDir()
+	if err := s.DownloadArtifacts(ctx, runId, "", dir); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "config.yaml")); err != nil || string(b) != "a: 1\n" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	query := `SELECT "key" FROM tags WHERE run_uuid = ? AND "key" = ?`
+	for dialect, expected := range map[Dialect]string{
+		SQLite:     query,
+		PostgreSQL: `SELECT "key" FROM tags WHERE run_uuid = $1 AND "key" = $2`,
+		MySQL:      "SELECT `key` FROM tags WHERE run_uuid = ? AND `key` = ?",
+	} {
+		s := New(nil, Options{Dialect: dialect})
+		if got := s.rebind(query); got != expected {
+			t.Errorf("%s: expected %s, got %s", dialect, expected, got)
+		}
+	}
+}
+
+func TestGC(t *testing.T) {
+	db, err := openFake(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testGC(t, db)
+}
+
+// testGC runs GC on db, a fresh database, after filling it with deleted
+// runs and experiments and the rows that refer to them.
+func testGC(t *testing.T, db *sql.DB) {
+	root := t.TempDir()
+	s := New(db, Options{DefaultArtifactRoot: root})
+	ctx := context.Background()
+	experimentId, err := s.CreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashedId, err := s.CreateExperiment(ctx, "trashed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var runIds []string
+	for _, id := range []string{*experimentId, *experimentId, *experimentId, *trashedId} {
+		run, err := s.CreateRun(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.LogText(ctx, run.Info.RunId, "x", "x.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.LogMetric(ctx, run.Info.RunId, "loss", 1, 1000, 0); err != nil {
+			t.Fatal(err)
+		}
+		runIds = append(runIds, run.Info.RunId)
+	}
+	old, recent, kept, trashed := runIds[0], runIds[1], runIds[2], runIds[3]
+	for _, runId := range []string{old, recent} {
+		if err := s.DeleteRun(ctx, runId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	weekAgo := mlflow.Millis(time.Now().Add(-7 * 24 * time.Hour))
+	if _, err := db.Exec(`UPDATE runs SET deleted_time = ? WHERE run_uuid = ?`, weekAgo, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE experiments SET lifecycle_stage = ?, last_update_time = ? WHERE experiment_id = ?`, "deleted", weekAgo, *trashedId); err != nil {
+		t.Fatal(err)
+	}
+	trashedNumber, _ := experimentNumber(*trashedId)
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO datasets (dataset_uuid, experiment_id, name, digest, dataset_source_type, dataset_source) VALUES (?, ?, ?, ?, ?, ?)`, []interface{}{"d1", trashedNumber, "wine", "abc", "local", `{"uri": "wine.csv"}`}},
+		{`INSERT INTO inputs (input_uuid, source_type, source_id, destination_type, destination_id) VALUES (?, ?, ?, ?, ?)`, []interface{}{"i1", "DATASET", "d1", "RUN", trashed}},
+		{`INSERT INTO inputs (input_uuid, source_type, source_id, destination_type, destination_id) VALUES (?, ?, ?, ?, ?)`, []interface{}{"i2", "DATASET", "d2", "RUN", old}},
+		{`INSERT INTO inputs (input_uuid, source_type, source_id, destination_type, destination_id) VALUES (?, ?, ?, ?, ?)`, []interface{}{"i3", "DATASET", "d2", "RUN", kept}},
+		{`INSERT INTO input_tags (input_uuid, name, value) VALUES (?, ?, ?)`, []interface{}{"i1", "mlflow.data.context", "training"}},
+		{`INSERT INTO input_tags (input_uuid, name, value) VALUES (?, ?, ?)`, []interface{}{"i3", "mlflow.data.context", "training"}},
+		{`INSERT INTO trace_info (request_id, experiment_id, timestamp_ms, status) VALUES (?, ?, ?, ?)`, []interface{}{"tr-1", trashedNumber, weekAgo, "OK"}},
+		{`INSERT INTO trace_tags (request_id, "key", "value") VALUES (?, ?, ?)`, []interface{}{"tr-1", "k", "v"}},
+	} {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := s.GC(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{old, trashed}
+	sort.Strings(expected)
+	if !reflect.DeepEqual(result.RunIds, expected) || !reflect.DeepEqual(result.ExperimentIds, []string{*trashedId}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	for _, runId := range []string{old, trashed} {
+		if _, err := s.GetRun(ctx, runId); !mlflow.IsNotFound(err) {
+			t.Errorf("expected run %s to be removed, got %v", runId, err)
+		}
+		var metricRunId string
+		if err := db.QueryRow(`SELECT run_uuid FROM metrics WHERE run_uuid = ?`, runId).Scan(&metricRunId); err != sql.ErrNoRows {
+			t.Errorf("expected the metrics of run %s to be removed, got %v", runId, err)
+		}
+	}
+	if _, err := s.GetExperiment(ctx, *trashedId); !mlflow.IsNotFound(err) {
+		t.Errorf("expected the experiment to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, *experimentId, old, "artifacts")); !os.IsNotExist(err) {
+		t.Errorf("expected the artifacts to be removed, got %v", err)
+	}
+	for _, runId := range []string{recent, kept} {
+		if _, err := s.GetRun(ctx, runId); err != nil {
+			t.Errorf("expected run %s to be kept, got %v", runId, err)
+		}
+	}
+	for query, expected := range map[string]string{
+		`SELECT input_uuid FROM inputs`:     "i3",
+		`SELECT input_uuid FROM input_tags`: "i3",
+		`SELECT dataset_uuid FROM datasets`: "",
+		`SELECT request_id FROM trace_info`: "",
+		`SELECT request_id FROM trace_tags`: "",
+	} {
+		left, err := s.column(ctx, db, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(left, ",") != expected {
+			t.Errorf("%s: expected %q to be left, got %v", query, expected, left)
+		}
+	}
+
+	result, err = s.GC(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.RunIds, []string{recent}) {
+		t.Errorf("expected everything deleted to be removed, got %+v", result)
+	}
+}
@@ -0,0 +1,305 @@
+package mlflowsql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+	"github.com/neka-nat/go-mlflow.git/internal/store"
+)
+
+const runColumns = `run_uuid, name, user_id, status, start_time, end_time, lifecycle_stage, artifact_uri, experiment_id`
+
+func (s *Store) scanRunInfos(ctx context.Context, q querier, where string, args ...interface{}) ([]mlflow.RunInfo, error) {
+	rows, err := s.query(ctx, q, `SELECT `+runColumns+` FROM runs`+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var infos []mlflow.RunInfo
+	for rows.Next() {
+		var name, userId, status, lifecycleStage, artifactUri sql.NullString
+		var startTime, endTime sql.NullInt64
+		var experimentId int64
+		info := mlflow.RunInfo{}
+		if err := rows.Scan(&info.RunId, &name, &userId, &status, &startTime, &endTime, &lifecycleStage, &artifactUri, &experimentId); err != nil {
+			return nil, err
+		}
+		info.RunUUid = info.RunId
+		info.RunName, info.UserId, info.Status = name.String, userId.String, status.String
+		info.StartTime, info.EndTime = startTime.Int64, endTime.Int64
+		info.LifecycleStage, info.ArtifactUri = lifecycleStage.String, artifactUri.String
+		info.ExperimentId = strconv.FormatInt(experimentId, 10)
+		infos = append(infos, info)
+	}
+	return infos, rows.Err()
+}
+
+func (s *Store) runInfo(ctx context.Context, q querier, runId string) (*mlflow.RunInfo, error) {
+	infos, err := s.scanRunInfos(ctx, q, ` WHERE run_uuid = ?`, runId)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, notFound("Run with id=%s not found", runId)
+	}
+	return &infos[0], nil
+}
+
+// activeRunInfo returns a run that can still be logged to.
+func (s *Store) activeRunInfo(ctx context.Context, q querier, runId string) (*mlflow.RunInfo, error) {
+	info, err := s.runInfo(ctx, q, runId)
+	if err != nil {
+		return nil, err
+	}
+	if info.LifecycleStage == "deleted" {
+		return nil, invalidParameter("The run %s must be in the 'active' state. Current state is deleted.", runId)
+	}
+	return info, nil
+}
+
+func (s *Store) readRun(ctx context.Context, q querier, info mlflow.RunInfo) (*mlflow.Run, error) {
+	run := &mlflow.Run{Info: info}
+	params, err := s.keyValues(ctx, q, `SELECT "key", "value" FROM params WHERE run_uuid = ?`, info.RunId)
+	if err != nil {
+		return nil, err
+	}
+	for _, param := range params {
+		run.Data.Params = append(run.Data.Params, mlflow.Param{Key: param[0], Value: param[1]})
+	}
+	tags, err := s.keyValues(ctx, q, `SELECT "key", "value" FROM tags WHERE run_uuid = ?`, info.RunId)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		run.Data.Tags = append(run.Data.Tags, mlflow.RunTag{Key: tag[0], Value: tag[1]})
+	}
+	rows, err := s.query(ctx, q, `SELECT "key", "value", timestamp, step, is_nan FROM latest_metrics WHERE run_uuid = ?`, info.RunId)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := scanMetrics(rows)
+	if err != nil {
+		return nil, err
+	}
+	sortMetrics(metrics)
+	run.Data.Metrics = metrics
+	return run, nil
+}
+
+func newRunId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *Store) CreateRun(ctx context.Context, experimentId string, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	spec := mlflow.NewRunSpec(opts...)
+	runId := newRunId()
+	var info *mlflow.RunInfo
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		experiment, err := s.experiment(ctx, tx, experimentId)
+		if err != nil {
+			return err
+		}
+		n, _ := experimentNumber(experiment.ExperimentId)
+		if experiment.LifecycleStage == "deleted" {
+			return invalidParameter("The experiment %s must be in the 'active' state.", experimentId)
+		}
+		userId := ""
+		tags := spec.Tags
+		if spec.Name != "" {
+			tags = append(tags, mlflow.RunTag{Key: mlflow.TagRunName, Value: spec.Name})
+		}
+		for _, tag := range tags {
+			if tag.Key == mlflow.TagUser {
+				userId = tag.Value
+			}
+		}
+		artifactUri := strings.TrimSuffix(experiment.ArtifactLocation, "/") + "/" + runId + "/artifacts"
+		_, err = s.exec(ctx, tx, `INSERT INTO runs (run_uuid, name, source_type, source_name, entry_point_name, user_id, status, start_time, source_version, lifecycle_stage, artifact_uri, experiment_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			runId, spec.Name, "UNKNOWN", "", "", userId, string(mlflow.Running), mlflow.Millis(spec.StartTime), "", "active", artifactUri, n)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if err := s.setTag(ctx, tx, runId, tag.Key, tag.Value); err != nil {
+				return err
+			}
+		}
+		info, err = s.runInfo(ctx, tx, runId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.readRun(ctx, s.db, *info)
+}
+
+func (s *Store) CreateRunAt(ctx context.Context, experimentId string, startTime time.Time, opts ...mlflow.RunOption) (*mlflow.Run, error) {
+	return s.CreateRun(ctx, experimentId, append(opts, mlflow.WithStartTime(startTime))...)
+}
+
+func (s *Store) updateRun(ctx context.Context, runId string, status mlflow.RunStatus, runName string, endTime int64) (*mlflow.RunInfo, error) {
+	var info *mlflow.RunInfo
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.activeRunInfo(ctx, tx, runId); err != nil {
+			return err
+		}
+		if _, err := s.exec(ctx, tx, `UPDATE runs SET status = ? WHERE run_uuid = ?`, string(status), runId); err != nil {
+			return err
+		}
+		if status != mlflow.Running && status != mlflow.Scheduled {
+			if _, err := s.exec(ctx, tx, `UPDATE runs SET end_time = ? WHERE run_uuid = ?`, endTime, runId); err != nil {
+				return err
+			}
+		}
+		if runName != "" {
+			if err := s.setTag(ctx, tx, runId, mlflow.TagRunName, runName); err != nil {
+				return err
+			}
+		}
+		var err error
+		info, err = s.runInfo(ctx, tx, runId)
+		return err
+	})
+	return info, err
+}
+
+func (s *Store) UpdateRun(ctx context.Context, runId string, status mlflow.RunStatus) (*mlflow.RunInfo, error) {
+	return s.UpdateRunAt(ctx, runId, status, time.Now())
+}
+
+func (s *Store) UpdateRunAt(ctx context.Context, runId string, status mlflow.RunStatus, endTime time.Time) (*mlflow.RunInfo, error) {
+	return s.updateRun(ctx, runId, status, "", mlflow.Millis(endTime))
+}
+
+func (s *Store) UpdateRunWithEndTime(ctx context.Context, runId string, status mlflow.RunStatus, endTime int64) (*mlflow.RunInfo, error) {
+	return s.updateRun(ctx, runId, status, "", endTime)
+}
+
+func (s *Store) UpdateRunWithName(ctx context.Context, runId string, status mlflow.RunStatus, runName string) (*mlflow.RunInfo, error) {
+	return s.updateRun(ctx, runId, status, runName, mlflow.Millis(time.Now()))
+}
+
+// DeleteRun marks a run deleted and records when, as the tracking server
+// does. Its rows and artifacts are kept until `mlflow gc`.
+func (s *Store) DeleteRun(ctx context.Context, runId string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.runInfo(ctx, tx, runId); err != nil {
+			return err
+		}
+		_, err := s.exec(ctx, tx, `UPDATE runs SET lifecycle_stage = ?, deleted_time = ? WHERE run_uuid = ?`, "deleted", mlflow.Millis(time.Now()), runId)
+		return err
+	})
+}
+
+func (s *Store) GetRun(ctx context.Context, runId string) (*mlflow.Run, error) {
+	info, err := s.runInfo(ctx, s.db, runId)
+	if err != nil {
+		return nil, err
+	}
+	return s.readRun(ctx, s.db, *info)
+}
+
+func (s *Store) SearchRuns(ctx context.Context, experimentIds []string, filter string, viewType mlflow.ViewType, maxResults int, orderBy []string, pageToken string) (*mlflow.ResponseSearchRuns, error) {
+	f, err := search.Parse(filter)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	var matched []mlflow.Run
+	for _, experimentId := range experimentIds {
+		n, err := experimentNumber(experimentId)
+		if err != nil {
+			continue
+		}
+		infos, err := s.scanRunInfos(ctx, s.db, ` WHERE experiment_id = ?`, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			if !store.InView(info.LifecycleStage, viewType) {
+				continue
+			}
+			run, err := s.readRun(ctx, s.db, info)
+			if err != nil {
+				return nil, err
+			}
+			if f.Match(store.RunLookup(*run)) {
+				matched = append(matched, *run)
+			}
+		}
+	}
+	store.SortRuns(matched, orderBy)
+	page, next, err := search.Page(matched, maxResults, pageToken, 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return &mlflow.ResponseSearchRuns{Runs: page, NextPageToken: next}, nil
+}
+
+func (s *Store) ListChildRuns(ctx context.Context, parentRunId string) ([]mlflow.Run, error) {
+	parent, err := s.GetRun(ctx, parentRunId)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := mlflow.Filter().Tag(mlflow.TagParentRunId).Eq(parentRunId).Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.SearchRuns(ctx, []string{parent.Info.ExperimentId}, filter, mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return response.Runs, nil
+}
+
+// setTag replaces a tag of a run, keeping the name and user_id columns of
+// runs in sync with the mlflow.runName and mlflow.user tags.
+func (s *Store) setTag(ctx context.Context, q querier, runId string, key string, value string) error {
+	if _, err := s.exec(ctx, q, `DELETE FROM tags WHERE run_uuid = ? AND "key" = ?`, runId, key); err != nil {
+		return err
+	}
+	if _, err := s.exec(ctx, q, `INSERT INTO tags ("key", "value", run_uuid) VALUES (?, ?, ?)`, key, value, runId); err != nil {
+		return err
+	}
+	switch key {
+	case mlflow.TagRunName:
+		_, err := s.exec(ctx, q, `UPDATE runs SET name = ? WHERE run_uuid = ?`, value, runId)
+		return err
+	case mlflow.TagUser:
+		_, err := s.exec(ctx, q, `UPDATE runs SET user_id = ? WHERE run_uuid = ?`, value, runId)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) SetTag(ctx context.Context, runId string, key string, value string) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: key, Value: value}})
+}
+
+func (s *Store) SetTags(ctx context.Context, runId string, tags map[string]string) error {
+	var runTags []mlflow.RunTag
+	for _, key := range sortedKeys(tags) {
+		runTags = append(runTags, mlflow.RunTag{Key: key, Value: tags[key]})
+	}
+	return s.LogBatch(ctx, runId, nil, nil, runTags)
+}
+
+func (s *Store) SetRunNote(ctx context.Context, runId string, note string) error {
+	return s.SetTag(ctx, runId, mlflow.TagNote, note)
+}
+
+func (s *Store) SetRunSource(ctx context.Context, runId string, name string, sourceType mlflow.SourceType) error {
+	return s.LogBatch(ctx, runId, nil, nil, []mlflow.RunTag{{Key: mlflow.TagSourceName, Value: name}, {Key: mlflow.TagSourceType, Value: string(sourceType)}})
+}
+
+func (s *Store) SetRunUser(ctx context.Context, runId string, user string) error {
+	return s.SetTag(ctx, runId, mlflow.TagUser, user)
+}
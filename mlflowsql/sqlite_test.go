@@ -0,0 +1,43 @@
+//go:build sqlite
+
+package mlflowsql
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The tests of this file run the Store on SQLite databases created from
+// MLflow's schema, with foreign keys enforced, catching queries the fake
+// driver accepts but a real database rejects:
+//
+//	go test -tags sqlite ./mlflowsql
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	schema, err := ioutil.ReadFile(filepath.Join("testdata", "sqlite_schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "mlflow.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestStoreSQLite(t *testing.T) {
+	testStore(t, openSQLite(t), SQLite)
+}
+
+func TestGCSQLite(t *testing.T) {
+	testGC(t, openSQLite(t))
+}
@@ -0,0 +1,140 @@
+package mlflowtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/internal/search"
+)
+
+const proxyPrefix = "/api/2.0/mlflow-artifacts/artifacts"
+
+type fileInfo struct {
+	Path     string `json:"path"`
+	IsDir    bool   `json:"is_dir"`
+	FileSize int64  `json:"file_size,omitempty"`
+}
+
+// Artifact returns the content of a run's artifact, stored by the client
+// through the artifact proxy.
+func (s *Server) Artifact(runId string, artifactPath string) ([]byte, bool) {
+	run, err := s.store.GetRun(context.Background(), runId)
+	if err != nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.artifacts[runArtifactPath(run, artifactPath)]
+	return data, ok
+}
+
+// runArtifactPath returns the proxy path of artifactPath under the artifact
+// root of a run.
+func runArtifactPath(run *mlflow.Run, artifactPath string) string {
+	root := strings.TrimPrefix(run.Info.ArtifactUri, "mlflow-artifacts:")
+	return cleanPath(path.Join(root, artifactPath))
+}
+
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
+
+// list returns the entries directly under dir, named relative to it.
+func (s *Server) list(dir string) []fileInfo {
+	prefix := cleanPath(dir)
+	if prefix != "" {
+		prefix += "/"
+	}
+	entries := map[string]fileInfo{}
+	for p, data := range s.artifacts {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(p, prefix)
+		if i := strings.Index(name, "/"); i >= 0 {
+			entries[name[:i]] = fileInfo{Path: name[:i], IsDir: true}
+			continue
+		}
+		entries[name] = fileInfo{Path: name, FileSize: int64(len(data))}
+	}
+	files := []fileInfo{}
+	for _, entry := range entries {
+		files = append(files, entry)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+func (s *Server) listArtifacts(r *request) (interface{}, error) {
+	run, err := s.store.GetRun(r.ctx, r.str("run_id"))
+	if err != nil {
+		return nil, err
+	}
+	dir := cleanPath(r.str("path"))
+	s.mu.Lock()
+	files := s.list(runArtifactPath(run, dir))
+	s.mu.Unlock()
+	for i := range files {
+		files[i].Path = path.Join(dir, files[i].Path)
+	}
+	page, next, err := search.Page(files, 0, r.str("page_token"), 1000)
+	if err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return map[string]interface{}{"root_uri": run.Info.ArtifactUri, "files": page, "next_page_token": next}, nil
+}
+
+// serveArtifact serves artifact contents, which aren't JSON: the
+// mlflow-artifacts proxy and /get-artifact.
+func (s *Server) serveArtifact(w http.ResponseWriter, req *http.Request, body []byte) {
+	if req.URL.Path == "/get-artifact" {
+		run, err := s.store.GetRun(req.Context(), req.URL.Query().Get("run_id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.writeArtifact(w, runArtifactPath(run, req.URL.Query().Get("path")))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := cleanPath(strings.TrimPrefix(req.URL.Path, proxyPrefix))
+	switch {
+	case p == "" && req.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"files": s.list(req.URL.Query().Get("path"))})
+	case req.Method == http.MethodPut:
+		s.artifacts[p] = body
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	case req.Method == http.MethodGet:
+		s.writeArtifact(w, p)
+	case req.Method == http.MethodDelete:
+		for key := range s.artifacts {
+			if key == p || strings.HasPrefix(key, p+"/") {
+				delete(s.artifacts, key)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) writeArtifact(w http.ResponseWriter, p string) {
+	data, ok := s.artifacts[p]
+	if !ok {
+		writeError(w, notFound("Artifact %s not found", p))
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
@@ -0,0 +1,402 @@
+// Package mlflowtest runs an in-process tracking server for tests. Server
+// serves the REST endpoints used by the mlflow package for experiments,
+// runs, metrics, artifacts and the model registry on top of an
+// mlflowfake.Store, so both fakes keep the same search, pagination and
+// validation semantics. Artifacts go through the mlflow-artifacts proxy,
+// whose files the server keeps itself. It records every request it receives
+// and can answer with canned errors, so that retries and error handling can
+// be tested too:
+//
+//	server := mlflowtest.NewServer()
+//	defer server.Close()
+//	server.InjectFault(mlflowtest.Fault{Path: "/api/2.0/mlflow/runs/create", StatusCode: 503, Times: 1})
+//	client := mlflow.New(server.URL)
+package mlflowtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowfake"
+)
+
+// Version is the MLflow version the server reports at /version.
+const Version = "2.13.0"
+
+// Request is a request received by the server. Body is decompressed when
+// it was sent gzipped.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode unmarshals the JSON body of the request into v.
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Fault is a canned error response.
+type Fault struct {
+	// Method and Path select the requests the fault applies to. Empty values
+	// match every request.
+	Method string
+	Path   string
+	// StatusCode defaults to 500.
+	StatusCode int
+	// ErrorCode and Message make up the JSON error body.
+	ErrorCode string
+	Message   string
+	// Times is the number of requests that fail, or zero for every request
+	// until ClearFaults.
+	Times int
+}
+
+// Server is a fake tracking server.
+type Server struct {
+	*httptest.Server
+
+	store *mlflowfake.Store
+
+	mu        sync.Mutex
+	requests  []Request
+	faults    []*Fault
+	handlers  map[string]http.Handler
+	artifacts map[string][]byte
+}
+
+type endpoint func(s *Server, r *request) (interface{}, error)
+
+// endpoints maps the method and path of the emulated endpoints to their
+// implementation.
+var endpoints = map[string]endpoint{
+	"POST /api/2.0/mlflow/experiments/create":             (*Server).createExperiment,
+	"GET /api/2.0/mlflow/experiments/get":                 (*Server).getExperiment,
+	"GET /api/2.0/mlflow/experiments/get-by-name":         (*Server).getExperimentByName,
+	"POST /api/2.0/mlflow/experiments/search":             (*Server).searchExperiments,
+	"POST /api/2.0/mlflow/experiments/set-experiment-tag": (*Server).setExperimentTag,
+
+	"POST /api/2.0/mlflow/runs/create":        (*Server).createRun,
+	"GET /api/2.0/mlflow/runs/get":            (*Server).getRun,
+	"POST /api/2.0/mlflow/runs/update":        (*Server).updateRun,
+	"POST /api/2.0/mlflow/runs/delete":        (*Server).deleteRun,
+	"POST /api/2.0/mlflow/runs/search":        (*Server).searchRuns,
+	"POST /api/2.0/mlflow/runs/set-tag":       (*Server).setTag,
+	"POST /api/2.0/mlflow/runs/log-metric":    (*Server).logMetric,
+	"POST /api/2.0/mlflow/runs/log-parameter": (*Server).logParam,
+	"POST /api/2.0/mlflow/runs/log-batch":     (*Server).logBatch,
+	"POST /api/2.0/mlflow/runs/log-inputs":    (*Server).logInputs,
+	"GET /api/2.0/mlflow/metrics/get-history": (*Server).getMetricHistory,
+	"GET /api/2.0/mlflow/artifacts/list":      (*Server).listArtifacts,
+
+	"POST /api/2.0/mlflow/registered-models/create":              (*Server).createRegisteredModel,
+	"GET /api/2.0/mlflow/registered-models/get":                  (*Server).getRegisteredModel,
+	"PATCH /api/2.0/mlflow/registered-models/update":             (*Server).updateRegisteredModel,
+	"POST /api/2.0/mlflow/registered-models/rename":              (*Server).renameRegisteredModel,
+	"DELETE /api/2.0/mlflow/registered-models/delete":            (*Server).deleteRegisteredModel,
+	"GET /api/2.0/mlflow/registered-models/search":               (*Server).searchRegisteredModels,
+	"POST /api/2.0/mlflow/registered-models/set-tag":             (*Server).setRegisteredModelTag,
+	"DELETE /api/2.0/mlflow/registered-models/delete-tag":        (*Server).deleteRegisteredModelTag,
+	"POST /api/2.0/mlflow/registered-models/alias":               (*Server).setAlias,
+	"DELETE /api/2.0/mlflow/registered-models/alias":             (*Server).deleteAlias,
+	"GET /api/2.0/mlflow/registered-models/alias":                (*Server).getModelVersionByAlias,
+	"POST /api/2.0/mlflow/registered-models/get-latest-versions": (*Server).getLatestVersions,
+	"GET /api/2.0/mlflow/registered-models/get-latest-versions":  (*Server).getLatestVersions,
+
+	"POST /api/2.0/mlflow/model-versions/create":           (*Server).createModelVersion,
+	"GET /api/2.0/mlflow/model-versions/get":               (*Server).getModelVersion,
+	"PATCH /api/2.0/mlflow/model-versions/update":          (*Server).updateModelVersion,
+	"DELETE /api/2.0/mlflow/model-versions/delete":         (*Server).deleteModelVersion,
+	"GET /api/2.0/mlflow/model-versions/search":            (*Server).searchModelVersions,
+	"POST /api/2.0/mlflow/model-versions/transition-stage": (*Server).transitionModelVersionStage,
+	"POST /api/2.0/mlflow/model-versions/set-tag":          (*Server).setModelVersionTag,
+	"DELETE /api/2.0/mlflow/model-versions/delete-tag":     (*Server).deleteModelVersionTag,
+	"GET /api/2.0/mlflow/model-versions/get-download-uri":  (*Server).getModelVersionDownloadUri,
+}
+
+// NewServer starts a server holding only the Default experiment with id
+// "0". Close it when done.
+func NewServer() *Server {
+	s := &Server{store: mlflowfake.New(), handlers: map[string]http.Handler{}, artifacts: map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Store returns the store behind the server, to set up or check its data
+// without going through HTTP.
+func (s *Server) Store() *mlflowfake.Store {
+	return s.store
+}
+
+// Handle serves the requests for path with handler instead of the
+// emulated endpoint, for endpoints the server doesn't know or canned
+// responses. Requests are still recorded and faults still apply.
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[path] = handler
+}
+
+// InjectFault makes matching requests fail with f until it is used up.
+// Faults are tried in the order they were injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received for path.
+func (s *Server) RequestsTo(path string) []Request {
+	var requests []Request
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (s *Server) ClearRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := readBody(req)
+	if err != nil {
+		writeError(w, invalidParameter("%v", err))
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: req.Method, Path: req.URL.Path, Query: req.URL.Query(), Header: req.Header.Clone(), Body: body})
+	fault := s.fault(req)
+	handler := s.handlers[req.URL.Path]
+	s.mu.Unlock()
+
+	if fault != nil {
+		statusCode := fault.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		writeError(w, &mlflow.Error{StatusCode: statusCode, ErrorCode: fault.ErrorCode, Message: fault.Message})
+		return
+	}
+	if handler != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.Header.Del("Content-Encoding")
+		handler.ServeHTTP(w, req)
+		return
+	}
+	switch req.URL.Path {
+	case "/health":
+		w.Write([]byte("OK"))
+		return
+	case "/version":
+		w.Write([]byte(Version))
+		return
+	}
+	if strings.HasPrefix(req.URL.Path, proxyPrefix) || req.URL.Path == "/get-artifact" {
+		s.serveArtifact(w, req, body)
+		return
+	}
+	e, ok := endpoints[req.Method+" "+req.URL.Path]
+	if !ok {
+		writeError(w, &mlflow.Error{StatusCode: http.StatusNotFound, ErrorCode: "ENDPOINT_NOT_FOUND", Message: fmt.Sprintf("%s %s is not emulated", req.Method, req.URL.Path)})
+		return
+	}
+	r, err := newRequest(req, body)
+	if err != nil {
+		writeError(w, invalidParameter("%v", err))
+		return
+	}
+	response, err := e(s, r)
+	var b []byte
+	if err == nil {
+		b, err = json.Marshal(response)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// fault returns the first fault matching req, using it up.
+func (s *Server) fault(req *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != req.Method || f.Path != "" && f.Path != req.URL.Path {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil || req.Header.Get("Content-Encoding") != "gzip" {
+		return body, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+// writeError answers with err, which the store returns as an *mlflow.Error
+// carrying the status code and error code of the tracking server.
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*mlflow.Error)
+	if !ok {
+		e = &mlflow.Error{StatusCode: http.StatusInternalServerError, ErrorCode: "INTERNAL_ERROR", Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.StatusCode)
+	json.NewEncoder(w).Encode(e)
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &mlflow.Error{StatusCode: http.StatusNotFound, ErrorCode: mlflow.ErrorCodeResourceDoesNotExist, Message: fmt.Sprintf(format, args...)}
+}
+
+func invalidParameter(format string, args ...interface{}) error {
+	return &mlflow.Error{StatusCode: http.StatusBadRequest, ErrorCode: mlflow.ErrorCodeInvalidParameterValue, Message: fmt.Sprintf(format, args...)}
+}
+
+// request holds the parameters of a request, from the query string of GET
+// requests and from the JSON body otherwise.
+type request struct {
+	ctx    context.Context
+	params map[string]interface{}
+	body   []byte
+}
+
+func newRequest(req *http.Request, body []byte) (*request, error) {
+	r := &request{ctx: req.Context(), params: map[string]interface{}{}, body: body}
+	if req.Method == http.MethodGet {
+		for key, values := range req.URL.Query() {
+			if len(values) == 1 {
+				r.params[key] = values[0]
+				continue
+			}
+			var list []interface{}
+			for _, v := range values {
+				list = append(list, v)
+			}
+			r.params[key] = list
+		}
+		return r, nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return r, nil
+	}
+	return r, json.Unmarshal(body, &r.params)
+}
+
+func (r *request) str(key string) string {
+	switch v := r.params[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+func (r *request) strs(key string) []string {
+	switch v := r.params[key].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	}
+	return nil
+}
+
+func (r *request) int(key string) int64 {
+	switch v := r.params[key].(type) {
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+func (r *request) bool(key string) bool {
+	switch v := r.params[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+// decode unmarshals the parameter key into v.
+func (r *request) decode(key string, v interface{}) error {
+	value, ok := r.params[key]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return invalidParameter("invalid %s: %v", key, err)
+	}
+	return nil
+}
+
+// required returns the string parameter key, failing when it is missing.
+func (r *request) required(key string) (string, error) {
+	v := r.str(key)
+	if v == "" {
+		return "", invalidParameter("Missing value for required parameter '%s'.", key)
+	}
+	return v, nil
+}
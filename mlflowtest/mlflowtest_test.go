@@ -0,0 +1,227 @@
+package mlflowtest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestTracking(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	experiment, err := client.GetOrCreateExperiment(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := client.CreateRun(ctx, experiment.ExperimentId, mlflow.WithRunName("train"), mlflow.WithTags(map[string]string{"team": "ml"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := client.LogParams(ctx, runId, map[string]string{"lr": "0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	for step, loss := range []float64{0.5, 0.25} {
+		if err := client.LogMetric(ctx, runId, "loss", loss, int64(1000+step), int64(step)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.UpdateRunAt(ctx, runId, mlflow.Finished, time.UnixMilli(2000)); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err = client.GetRun(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Info.RunName != "train" || run.Info.Status != string(mlflow.Finished) || run.Info.EndTime != 2000 {
+		t.Errorf("unexpected run info %+v", run.Info)
+	}
+	if loss, _ := run.Metric("loss"); loss != 0.25 {
+		t.Errorf("expected the latest loss, got %v", loss)
+	}
+	history, err := client.IterMetricHistory(ctx, runId, "loss").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Errorf("unexpected history %+v", history)
+	}
+	err = client.LogParam(ctx, runId, "lr", "0.2")
+	if !mlflow.IsInvalidParameter(err) {
+		t.Errorf("expected changing a param to fail, got %v", err)
+	}
+
+	filter, err := mlflow.Filter().Metric("loss").Lt(0.3).Tag("team").Eq("ml").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.SearchRuns(ctx, []string{experiment.ExperimentId}, filter, mlflow.ActiveOnly, 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Runs) != 1 || response.Runs[0].Info.RunId != runId {
+		t.Errorf("unexpected search result %+v", response.Runs)
+	}
+	_, err = client.GetRun(ctx, "missing")
+	if !mlflow.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	run, err := client.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	if err := client.LogText(ctx, runId, "hello", "notes/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := server.Artifact(runId, "notes/hello.txt"); !ok || string(data) != "hello" {
+		t.Errorf("unexpected stored artifact %q", data)
+	}
+	files, err := client.ListArtifacts(ctx, runId, "notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "notes/hello.txt" || files[0].FileSize != 5 {
+		t.Errorf("unexpected artifacts %+v", files)
+	}
+	dir := t.TempDir()
+	if err := client.DownloadArtifacts(ctx, runId, "", dir); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "notes", "hello.txt")); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected download %q, %v", b, err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	if _, err := client.CreateRegisteredModel(ctx, "model", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range []string{"runs:/a/model", "runs:/b/model"} {
+		if _, err := client.CreateModelVersion(ctx, "model", source, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.TransitionModelVersionStage(ctx, "model", "2", mlflow.StageProduction, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetRegisteredModelAlias(ctx, "model", "champion", "1"); err != nil {
+		t.Fatal(err)
+	}
+	for uri, expected := range map[string]string{
+		"models:/model@champion":   "1",
+		"models:/model/Production": "2",
+		"models:/model/latest":     "2",
+	} {
+		version, err := client.ResolveModelUri(ctx, uri)
+		if err != nil {
+			t.Errorf("%s: %v", uri, err)
+			continue
+		}
+		if version.Version != expected {
+			t.Errorf("%s resolved to version %s, expected %s", uri, version.Version, expected)
+		}
+	}
+	versions, err := client.SearchModelVersions(ctx, "name = 'model'", 1, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions.ModelVersions) != 1 || versions.ModelVersions[0].Version != "2" || versions.NextPageToken == "" {
+		t.Errorf("unexpected versions %+v", versions)
+	}
+	_, err = client.CreateRegisteredModel(ctx, "model", "")
+	if !mlflow.IsAlreadyExists(err) {
+		t.Errorf("expected already exists, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	server.InjectFault(mlflowtest.Fault{Path: "/api/2.0/mlflow/experiments/create", StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+	client := mlflow.New(server.URL, mlflow.WithRetry(mlflow.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}))
+	if _, err := client.CreateExperiment(ctx, "exp"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(server.RequestsTo("/api/2.0/mlflow/experiments/create")); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := server.Requests()[2].Decode(&body); err != nil || body.Name != "exp" {
+		t.Errorf("unexpected recorded body %+v, %v", body, err)
+	}
+
+	server.InjectFault(mlflowtest.Fault{Method: http.MethodGet, StatusCode: http.StatusForbidden, ErrorCode: mlflow.ErrorCodePermissionDenied, Message: "denied"})
+	_, err := mlflow.New(server.URL).GetExperiment(ctx, "0")
+	if !mlflow.IsPermissionDenied(err) {
+		t.Errorf("expected permission denied, got %v", err)
+	}
+	server.ClearFaults()
+	if _, err := mlflow.New(server.URL).GetExperiment(ctx, "0"); err != nil {
+		t.Errorf("expected the fault to be cleared, got %v", err)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	server.Handle("/api/2.0/mlflow/experiments/get", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"experiment": {"experiment_id": "42", "name": "canned"}}`))
+	}))
+	experiment, err := mlflow.New(server.URL).GetExperiment(context.Background(), "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if experiment.Name != "canned" {
+		t.Errorf("expected the canned response, got %+v", experiment)
+	}
+}
+
+func TestStore(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	run, err := server.Store().CreateRun(ctx, "0", mlflow.WithRunName("seeded"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateRunWithName(ctx, run.Info.RunId, mlflow.Finished, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := server.Store().GetRun(ctx, run.Info.RunId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Info.RunName != "renamed" || stored.Info.Status != string(mlflow.Finished) {
+		t.Errorf("expected the update to reach the store, got %+v", stored.Info)
+	}
+}
@@ -0,0 +1,135 @@
+package mlflowtest
+
+import (
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func registeredModel(model *mlflow.RegisteredModel, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseRegisteredModel{RegisteredModel: *model}, nil
+}
+
+func modelVersion(version *mlflow.ModelVersion, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseModelVersion{ModelVersion: *version}, nil
+}
+
+func (s *Server) createRegisteredModel(r *request) (interface{}, error) {
+	return registeredModel(s.store.CreateRegisteredModel(r.ctx, r.str("name"), r.str("description")))
+}
+
+func (s *Server) getRegisteredModel(r *request) (interface{}, error) {
+	return registeredModel(s.store.GetRegisteredModel(r.ctx, r.str("name")))
+}
+
+func (s *Server) updateRegisteredModel(r *request) (interface{}, error) {
+	return registeredModel(s.store.UpdateRegisteredModel(r.ctx, r.str("name"), r.str("description")))
+}
+
+func (s *Server) renameRegisteredModel(r *request) (interface{}, error) {
+	newName, err := r.required("new_name")
+	if err != nil {
+		return nil, err
+	}
+	return registeredModel(s.store.RenameRegisteredModel(r.ctx, r.str("name"), newName))
+}
+
+func (s *Server) deleteRegisteredModel(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteRegisteredModel(r.ctx, r.str("name"))
+}
+
+func (s *Server) searchRegisteredModels(r *request) (interface{}, error) {
+	return s.store.SearchRegisteredModels(r.ctx, r.str("filter"), int(r.int("max_results")), r.strs("order_by"), r.str("page_token"))
+}
+
+func (s *Server) setRegisteredModelTag(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.SetRegisteredModelTag(r.ctx, r.str("name"), key, r.str("value"))
+}
+
+func (s *Server) deleteRegisteredModelTag(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteRegisteredModelTag(r.ctx, r.str("name"), r.str("key"))
+}
+
+func (s *Server) setAlias(r *request) (interface{}, error) {
+	alias, err := r.required("alias")
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.SetRegisteredModelAlias(r.ctx, r.str("name"), alias, r.str("version"))
+}
+
+func (s *Server) deleteAlias(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteRegisteredModelAlias(r.ctx, r.str("name"), r.str("alias"))
+}
+
+func (s *Server) getModelVersionByAlias(r *request) (interface{}, error) {
+	return modelVersion(s.store.GetModelVersionByAlias(r.ctx, r.str("name"), r.str("alias")))
+}
+
+func (s *Server) getLatestVersions(r *request) (interface{}, error) {
+	versions, err := s.store.GetLatestVersions(r.ctx, r.str("name"), r.strs("stages"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseModelVersions{ModelVersions: versions}, nil
+}
+
+func (s *Server) createModelVersion(r *request) (interface{}, error) {
+	var tags []mlflow.ModelVersionTag
+	if err := r.decode("tags", &tags); err != nil {
+		return nil, err
+	}
+	version, err := s.store.CreateModelVersion(r.ctx, r.str("name"), r.str("source"), r.str("run_id"), tags...)
+	if err == nil && r.str("description") != "" {
+		version, err = s.store.UpdateModelVersion(r.ctx, version.Name, version.Version, r.str("description"))
+	}
+	return modelVersion(version, err)
+}
+
+func (s *Server) getModelVersion(r *request) (interface{}, error) {
+	return modelVersion(s.store.GetModelVersion(r.ctx, r.str("name"), r.str("version")))
+}
+
+func (s *Server) updateModelVersion(r *request) (interface{}, error) {
+	return modelVersion(s.store.UpdateModelVersion(r.ctx, r.str("name"), r.str("version"), r.str("description")))
+}
+
+func (s *Server) deleteModelVersion(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteModelVersion(r.ctx, r.str("name"), r.str("version"))
+}
+
+func (s *Server) searchModelVersions(r *request) (interface{}, error) {
+	return s.store.SearchModelVersions(r.ctx, r.str("filter"), int(r.int("max_results")), r.strs("order_by"), r.str("page_token"))
+}
+
+func (s *Server) transitionModelVersionStage(r *request) (interface{}, error) {
+	return modelVersion(s.store.TransitionModelVersionStage(r.ctx, r.str("name"), r.str("version"), r.str("stage"), r.bool("archive_existing_versions")))
+}
+
+func (s *Server) setModelVersionTag(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.SetModelVersionTag(r.ctx, r.str("name"), r.str("version"), key, r.str("value"))
+}
+
+func (s *Server) deleteModelVersionTag(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteModelVersionTag(r.ctx, r.str("name"), r.str("version"), r.str("key"))
+}
+
+func (s *Server) getModelVersionDownloadUri(r *request) (interface{}, error) {
+	uri, err := s.store.GetModelVersionDownloadUri(r.ctx, r.str("name"), r.str("version"))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"artifact_uri": uri}, nil
+}
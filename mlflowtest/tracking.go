@@ -0,0 +1,149 @@
+package mlflowtest
+
+import (
+	"encoding/json"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+)
+
+func (s *Server) createExperiment(r *request) (interface{}, error) {
+	experimentId, err := s.store.CreateExperiment(r.ctx, r.str("name"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseCreateExperiment{ExperimentId: *experimentId}, nil
+}
+
+func (s *Server) getExperiment(r *request) (interface{}, error) {
+	experiment, err := s.store.GetExperiment(r.ctx, r.str("experiment_id"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseExperiment{Experiment: *experiment}, nil
+}
+
+func (s *Server) getExperimentByName(r *request) (interface{}, error) {
+	experiment, err := s.store.GetExperimentsByName(r.ctx, r.str("experiment_name"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseExperiment{Experiment: *experiment}, nil
+}
+
+func (s *Server) searchExperiments(r *request) (interface{}, error) {
+	return s.store.SearchExperiments(r.ctx, r.str("filter"), mlflow.ViewType(r.str("view_type")), int(r.int("max_results")), r.strs("order_by"), r.str("page_token"))
+}
+
+func (s *Server) setExperimentTag(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.SetExperimentTag(r.ctx, r.str("experiment_id"), key, r.str("value"))
+}
+
+func (s *Server) createRun(r *request) (interface{}, error) {
+	var tags []mlflow.RunTag
+	if err := r.decode("tags", &tags); err != nil {
+		return nil, err
+	}
+	opts := []mlflow.RunOption{mlflow.WithRunName(r.str("run_name"))}
+	if startTime := r.int("start_time"); startTime != 0 {
+		opts = append(opts, mlflow.WithStartTime(mlflow.FromMillis(startTime)))
+	}
+	run, err := s.store.CreateRun(r.ctx, r.str("experiment_id"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		if err := s.store.LogBatch(r.ctx, run.Info.RunId, nil, nil, tags); err != nil {
+			return nil, err
+		}
+		if run, err = s.store.GetRun(r.ctx, run.Info.RunId); err != nil {
+			return nil, err
+		}
+	}
+	return mlflow.ResponseRun{Run: *run}, nil
+}
+
+func (s *Server) getRun(r *request) (interface{}, error) {
+	run, err := s.store.GetRun(r.ctx, r.str("run_id"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseRun{Run: *run}, nil
+}
+
+func (s *Server) updateRun(r *request) (interface{}, error) {
+	runId := r.str("run_id")
+	status := mlflow.RunStatus(r.str("status"))
+	if status == "" {
+		run, err := s.store.GetRun(r.ctx, runId)
+		if err != nil {
+			return nil, err
+		}
+		status = mlflow.RunStatus(run.Info.Status)
+	}
+	if name := r.str("run_name"); name != "" {
+		if err := s.store.SetTag(r.ctx, runId, mlflow.TagRunName, name); err != nil {
+			return nil, err
+		}
+	}
+	info, err := s.store.UpdateRunWithEndTime(r.ctx, runId, status, r.int("end_time"))
+	if err != nil {
+		return nil, err
+	}
+	return mlflow.ResponseRunUpdate{Info: *info}, nil
+}
+
+func (s *Server) deleteRun(r *request) (interface{}, error) {
+	return struct{}{}, s.store.DeleteRun(r.ctx, r.str("run_id"))
+}
+
+func (s *Server) searchRuns(r *request) (interface{}, error) {
+	return s.store.SearchRuns(r.ctx, r.strs("experiment_ids"), r.str("filter"), mlflow.ViewType(r.str("run_view_type")), int(r.int("max_results")), r.strs("order_by"), r.str("page_token"))
+}
+
+func (s *Server) setTag(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.SetTag(r.ctx, r.str("run_id"), key, r.str("value"))
+}
+
+func (s *Server) logMetric(r *request) (interface{}, error) {
+	var metric mlflow.Metric
+	if err := json.Unmarshal(r.body, &metric); err != nil {
+		return nil, invalidParameter("%v", err)
+	}
+	return struct{}{}, s.store.LogBatch(r.ctx, r.str("run_id"), []mlflow.Metric{metric}, nil, nil)
+}
+
+func (s *Server) logParam(r *request) (interface{}, error) {
+	return struct{}{}, s.store.LogParam(r.ctx, r.str("run_id"), r.str("key"), r.str("value"))
+}
+
+func (s *Server) logBatch(r *request) (interface{}, error) {
+	var metrics []mlflow.Metric
+	var params []mlflow.Param
+	var tags []mlflow.RunTag
+	for key, v := range map[string]interface{}{"metrics": &metrics, "params": &params, "tags": &tags} {
+		if err := r.decode(key, v); err != nil {
+			return nil, err
+		}
+	}
+	return struct{}{}, s.store.LogBatch(r.ctx, r.str("run_id"), metrics, params, tags)
+}
+
+func (s *Server) logInputs(r *request) (interface{}, error) {
+	var datasets []mlflow.DatasetInput
+	if err := r.decode("datasets", &datasets); err != nil {
+		return nil, err
+	}
+	return struct{}{}, s.store.LogInputs(r.ctx, r.str("run_id"), datasets)
+}
+
+func (s *Server) getMetricHistory(r *request) (interface{}, error) {
+	return s.store.GetMetricHistory(r.ctx, r.str("run_id"), r.str("metric_key"), int(r.int("max_results")), r.str("page_token"))
+}
@@ -0,0 +1,146 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelSpec describes a model for LogModel.
+type ModelSpec struct {
+	// Flavors maps flavor names, such as "onnx" or "python_function", to
+	// their configuration in the MLmodel file.
+	Flavors map[string]map[string]interface{}
+	// Files maps paths in the model directory to the local files or
+	// directories copied there, such as saved weights.
+	Files map[string]string
+	// Contents maps paths in the model directory to generated files, such
+	// as environment specifications.
+	Contents  map[string][]byte
+	Signature *Signature
+	// Metadata is free-form metadata stored with the model.
+	Metadata map[string]interface{}
+	// RegisteredModelName, when set, registers the logged model as a new
+	// version of that registered model, which is created if needed.
+	RegisteredModelName string
+}
+
+// ModelInfo describes a model logged by LogModel.
+type ModelInfo struct {
+	RunId          string                            `json:"run_id" yaml:"run_id"`
+	ArtifactPath   string                            `json:"artifact_path" yaml:"artifact_path"`
+	UtcTimeCreated string                            `json:"utc_time_created" yaml:"utc_time_created"`
+	ModelUuid      string                            `json:"model_uuid" yaml:"model_uuid"`
+	Flavors        map[string]map[string]interface{} `json:"flavors" yaml:"flavors"`
+	// ModelUri is the runs:/ uri of the model.
+	ModelUri string `json:"-" yaml:"-"`
+	// ModelVersion is the registered version, when the spec named a
+	// registered model.
+	ModelVersion *ModelVersion `json:"-" yaml:"-"`
+}
+
+// mlModel is the content of an MLmodel file.
+type mlModel struct {
+	ModelInfo `yaml:",inline"`
+	Signature *Signature             `yaml:"signature,omitempty"`
+	Metadata  map[string]interface{} `yaml:"metadata,omitempty"`
+}
+
+// LogModel writes model as an MLflow model under artifactPath: an MLmodel
+// file describing its flavors and signature, next to its files. The model
+// is added to the run's mlflow.log-model.history tag, as the Python client
+// does, so that the UI lists it, and registered when the spec names a
+// registered model.
+func (p *Client) LogModel(ctx context.Context, runId string, artifactPath string, model ModelSpec) (*ModelInfo, error) {
+	if len(model.Flavors) == 0 {
+		return nil, fmt.Errorf("mlflow: model has no flavor")
+	}
+	run, err := p.GetRun(ctx, runId)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := p.ArtifactRepository(run.Info.ArtifactUri)
+	if err != nil {
+		return nil, err
+	}
+	for _, dst := range sortedKeys(model.Files) {
+		if err := uploadTree(ctx, repo, model.Files[dst], path.Join(artifactPath, dst)); err != nil {
+			return nil, err
+		}
+	}
+	for _, dst := range sortedKeys(model.Contents) {
+		if err := uploadBytes(ctx, repo, model.Contents[dst], path.Join(artifactPath, dst)); err != nil {
+			return nil, err
+		}
+	}
+
+	info := ModelInfo{
+		RunId:          runId,
+		ArtifactPath:   artifactPath,
+		UtcTimeCreated: time.Now().UTC().Format("2006-01-02 15:04:05.000000"),
+		ModelUuid:      strings.TrimPrefix(randomId(16), "0x"),
+		Flavors:        model.Flavors,
+		ModelUri:       "runs:/" + runId + "/" + artifactPath,
+	}
+	data, err := yaml.Marshal(mlModel{ModelInfo: info, Signature: model.Signature, Metadata: model.Metadata})
+	if err != nil {
+		return nil, err
+	}
+	if err := uploadBytes(ctx, repo, data, path.Join(artifactPath, "MLmodel")); err != nil {
+		return nil, err
+	}
+
+	var history []ModelInfo
+	if value, ok := run.Tag(TagLoggedModels); ok {
+		json.Unmarshal([]byte(value), &history)
+	}
+	b, err := json.Marshal(append(history, info))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetTag(ctx, runId, TagLoggedModels, string(b)); err != nil {
+		return nil, err
+	}
+
+	if model.RegisteredModelName != "" {
+		if _, err := p.GetRegisteredModel(ctx, model.RegisteredModelName); IsNotFound(err) {
+			_, err = p.CreateRegisteredModel(ctx, model.RegisteredModelName, "")
+			if err != nil && !IsAlreadyExists(err) {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		source := strings.TrimSuffix(run.Info.ArtifactUri, "/") + "/" + artifactPath
+		version, err := p.CreateModelVersion(ctx, model.RegisteredModelName, source, runId)
+		if err != nil {
+			return nil, err
+		}
+		info.ModelVersion, err = p.WaitForModelVersion(ctx, version.Name, version.Version, time.Second)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &info, nil
+}
+
+// uploadTree uploads the local file or directory localPath as artifactPath.
+func uploadTree(ctx context.Context, repo ArtifactRepository, localPath string, artifactPath string) error {
+	return filepath.Walk(localPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, file)
+		if err != nil {
+			return err
+		}
+		return repo.Upload(ctx, file, path.Join(artifactPath, filepath.ToSlash(rel)))
+	})
+}
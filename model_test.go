@@ -0,0 +1,88 @@
+package mlflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLogModel(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	run, err := client.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	dir := t.TempDir()
+	weights := filepath.Join(dir, "weights.bin")
+	if err := os.WriteFile(weights, []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "vocab"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vocab", "en.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := client.LogModel(ctx, runId, "model", mlflow.ModelSpec{
+		Flavors:             map[string]map[string]interface{}{"go": {"data": "data/weights.bin"}},
+		Files:               map[string]string{"data/weights.bin": weights, "data/vocab": filepath.Join(dir, "vocab")},
+		Signature:           &mlflow.Signature{Inputs: mlflow.Schema{Columns: []mlflow.ColSpec{{Name: "x", Type: mlflow.Double}}}},
+		RegisteredModelName: "regressor",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModelUri != "runs:/"+runId+"/model" || len(info.ModelUuid) != 32 {
+		t.Errorf("unexpected model info %+v", info)
+	}
+	if info.ModelVersion == nil || info.ModelVersion.Version != "1" || info.ModelVersion.Source != run.Info.ArtifactUri+"/model" {
+		t.Errorf("unexpected model version %+v", info.ModelVersion)
+	}
+	for artifactPath, content := range map[string]string{"model/data/weights.bin": "weights", "model/data/vocab/en.txt": "hello"} {
+		if b, ok := server.Artifact(runId, artifactPath); !ok || string(b) != content {
+			t.Errorf("unexpected artifact %s: %q", artifactPath, b)
+		}
+	}
+	b, ok := server.Artifact(runId, "model/MLmodel")
+	if !ok {
+		t.Fatal("expected an MLmodel file")
+	}
+	var mlModel map[string]interface{}
+	if err := yaml.Unmarshal(b, &mlModel); err != nil {
+		t.Fatal(err)
+	}
+	signature, _ := mlModel["signature"].(map[string]interface{})
+	if mlModel["artifact_path"] != "model" || mlModel["run_id"] != runId || signature["inputs"] != `[{"name":"x","type":"double","required":true}]` {
+		t.Errorf("unexpected MLmodel:\n%s", b)
+	}
+
+	if _, err := client.LogModel(ctx, runId, "other", mlflow.ModelSpec{Flavors: map[string]map[string]interface{}{"go": {}}}); err != nil {
+		t.Fatal(err)
+	}
+	run, err = client.GetRun(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ := run.Tag(mlflow.TagLoggedModels)
+	var history []mlflow.ModelInfo
+	if err := json.Unmarshal([]byte(value), &history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].ArtifactPath != "model" || history[1].ArtifactPath != "other" {
+		t.Errorf("unexpected history %s", value)
+	}
+	if _, err := client.LogModel(ctx, runId, "empty", mlflow.ModelSpec{}); err == nil {
+		t.Error("expected a model without flavors to fail")
+	}
+}
@@ -0,0 +1,121 @@
+package mlflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultOnnxVersion   = "1.16.0"
+	DefaultPythonVersion = "3.10"
+)
+
+// onnxModelFile is the name of the model in the model directory, as the
+// Python onnx flavor saves it.
+const onnxModelFile = "model.onnx"
+
+// OnnxOptions configures LogOnnxModel.
+type OnnxOptions struct {
+	Signature *Signature
+	Metadata  map[string]interface{}
+	// RegisteredModelName, when set, registers the model as a new version of
+	// that registered model.
+	RegisteredModelName string
+	// OnnxVersion is the onnx package version the model was exported with,
+	// DefaultOnnxVersion when empty.
+	OnnxVersion string
+	// Providers are the onnxruntime execution providers tried in order when
+	// serving, CUDA then CPU when empty.
+	Providers []string
+	// PythonVersion is the Python version of the serving environment,
+	// DefaultPythonVersion when empty.
+	PythonVersion string
+	// PipRequirements replaces the default requirements of the serving
+	// environment: mlflow, onnx and onnxruntime.
+	PipRequirements []string
+}
+
+// LogOnnxModel logs the exported ONNX model at onnxPath under artifactPath
+// as the Python onnx flavor does: with an MLmodel file declaring the onnx
+// and python_function flavors, and conda.yaml, python_env.yaml and
+// requirements.txt files describing the environment, so that
+// `mlflow models serve` can serve it.
+func (p *Client) LogOnnxModel(ctx context.Context, runId string, artifactPath string, onnxPath string, opts OnnxOptions) (*ModelInfo, error) {
+	info, err := os.Stat(onnxPath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("mlflow: %s is a directory, not an ONNX model", onnxPath)
+	}
+	if opts.OnnxVersion == "" {
+		opts.OnnxVersion = DefaultOnnxVersion
+	}
+	if opts.PythonVersion == "" {
+		opts.PythonVersion = DefaultPythonVersion
+	}
+	if opts.Providers == nil {
+		opts.Providers = []string{"CUDAExecutionProvider", "CPUExecutionProvider"}
+	}
+	if opts.PipRequirements == nil {
+		opts.PipRequirements = []string{"mlflow", "onnx==" + opts.OnnxVersion, "onnxruntime"}
+	}
+	contents, err := onnxEnvironment(opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.LogModel(ctx, runId, artifactPath, ModelSpec{
+		Flavors: map[string]map[string]interface{}{
+			"onnx": {
+				"onnx_version": opts.OnnxVersion,
+				"data":         onnxModelFile,
+				"providers":    opts.Providers,
+				"code":         nil,
+			},
+			"python_function": {
+				"loader_module":  "mlflow.onnx",
+				"python_version": opts.PythonVersion,
+				"data":           onnxModelFile,
+				"env":            map[string]interface{}{"conda": "conda.yaml", "virtualenv": "python_env.yaml"},
+			},
+		},
+		Files:               map[string]string{onnxModelFile: onnxPath},
+		Contents:            contents,
+		Signature:           opts.Signature,
+		Metadata:            opts.Metadata,
+		RegisteredModelName: opts.RegisteredModelName,
+	})
+}
+
+// onnxEnvironment returns the environment files of an ONNX model.
+func onnxEnvironment(opts OnnxOptions) (map[string][]byte, error) {
+	conda, err := yaml.Marshal(map[string]interface{}{
+		"name":     "mlflow-env",
+		"channels": []string{"conda-forge"},
+		"dependencies": []interface{}{
+			"python=" + opts.PythonVersion,
+			"pip",
+			map[string][]string{"pip": opts.PipRequirements},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	pythonEnv, err := yaml.Marshal(map[string]interface{}{
+		"python":             opts.PythonVersion,
+		"build_dependencies": []string{"pip", "setuptools", "wheel"},
+		"dependencies":       []string{"-r requirements.txt"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"conda.yaml":       conda,
+		"python_env.yaml":  pythonEnv,
+		"requirements.txt": []byte(strings.Join(opts.PipRequirements, "\n") + "\n"),
+	}, nil
+}
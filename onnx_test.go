@@ -0,0 +1,71 @@
+package mlflow_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLogOnnxModel(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+	run, err := client.CreateRun(ctx, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId := run.Info.RunId
+	onnxPath := filepath.Join(t.TempDir(), "exported.onnx")
+	if err := os.WriteFile(onnxPath, []byte("onnx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := client.LogOnnxModel(ctx, runId, "model", onnxPath, mlflow.OnnxOptions{RegisteredModelName: "classifier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModelVersion == nil || info.ModelVersion.Name != "classifier" {
+		t.Errorf("unexpected model version %+v", info.ModelVersion)
+	}
+	if b, ok := server.Artifact(runId, "model/model.onnx"); !ok || string(b) != "onnx" {
+		t.Errorf("unexpected model file %q", b)
+	}
+	if b, ok := server.Artifact(runId, "model/requirements.txt"); !ok || string(b) != "mlflow\nonnx=="+mlflow.DefaultOnnxVersion+"\nonnxruntime\n" {
+		t.Errorf("unexpected requirements %q", b)
+	}
+	for _, file := range []string{"conda.yaml", "python_env.yaml"} {
+		if _, ok := server.Artifact(runId, "model/"+file); !ok {
+			t.Errorf("expected %s", file)
+		}
+	}
+	b, _ := server.Artifact(runId, "model/MLmodel")
+	var mlModel struct {
+		Flavors struct {
+			Onnx struct {
+				Data      string   `yaml:"data"`
+				Providers []string `yaml:"providers"`
+			} `yaml:"onnx"`
+			PythonFunction struct {
+				LoaderModule string            `yaml:"loader_module"`
+				Env          map[string]string `yaml:"env"`
+			} `yaml:"python_function"`
+		} `yaml:"flavors"`
+	}
+	if err := yaml.Unmarshal(b, &mlModel); err != nil {
+		t.Fatal(err)
+	}
+	flavors := mlModel.Flavors
+	if flavors.Onnx.Data != "model.onnx" || len(flavors.Onnx.Providers) != 2 || flavors.PythonFunction.LoaderModule != "mlflow.onnx" || flavors.PythonFunction.Env["conda"] != "conda.yaml" {
+		t.Errorf("unexpected MLmodel:\n%s", b)
+	}
+
+	if _, err := client.LogOnnxModel(ctx, runId, "missing", filepath.Join(t.TempDir(), "missing.onnx"), mlflow.OnnxOptions{}); err == nil {
+		t.Error("expected a missing model to fail")
+	}
+}
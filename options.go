@@ -0,0 +1,177 @@
+package mlflow
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created with New.
+type Option func(*Client)
+
+// WithBasicAuth authenticates every request with HTTP basic auth.
+func WithBasicAuth(username string, password string) Option {
+	return func(p *Client) {
+		p.username = username
+		p.password = password
+		p.token = ""
+	}
+}
+
+// WithToken sends token as a bearer token with every request.
+func WithToken(token string) Option {
+	return func(p *Client) {
+		p.token = token
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return func(p *Client) {
+		p.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with every request, such as a request id or
+// a tenant header required by a gateway.
+func WithHeader(key string, value string) Option {
+	return func(p *Client) {
+		if p.headers == nil {
+			p.headers = http.Header{}
+		}
+		p.headers.Add(key, value)
+	}
+}
+
+// transport returns the client's own transport, first replacing the http
+// client and its transport with copies so that options never change
+// http.DefaultClient or a client shared with other code.
+func (p *Client) transport() *http.Transport {
+	if !p.ownTransport {
+		client := *p.Client
+		if t, ok := client.Transport.(*http.Transport); ok {
+			client.Transport = t.Clone()
+		} else {
+			client.Transport = http.DefaultTransport.(*http.Transport).Clone()
+		}
+		p.Client = &client
+		p.ownTransport = true
+	}
+	return p.Client.Transport.(*http.Transport)
+}
+
+// DefaultMaxIdleConnsPerHost is the number of idle connections to the
+// tracking server a Client keeps, so that many goroutines logging at once
+// reuse connections. http.DefaultTransport keeps only two.
+const DefaultMaxIdleConnsPerHost = 64
+
+// WithMaxIdleConnsPerHost sets how many idle connections to the server are
+// kept for reuse.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(p *Client) {
+		t := p.transport()
+		t.MaxIdleConnsPerHost = n
+		if t.MaxIdleConns != 0 && t.MaxIdleConns < n {
+			t.MaxIdleConns = n
+		}
+	}
+}
+
+// WithMaxConnsPerHost limits the number of connections to the server,
+// including those in use. Zero means no limit.
+func WithMaxConnsPerHost(n int) Option {
+	return func(p *Client) {
+		p.transport().MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept open.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(p *Client) {
+		p.transport().IdleConnTimeout = timeout
+	}
+}
+
+// WithHttp2 enables or disables HTTP/2. It is enabled by default for https
+// servers.
+func WithHttp2(enabled bool) Option {
+	return func(p *Client) {
+		t := p.transport()
+		t.ForceAttemptHTTP2 = enabled
+		if enabled {
+			t.TLSNextProto = nil
+		} else {
+			// A non-nil empty map turns HTTP/2 off. The TLS config may
+			// already offer h2 if it was copied from a transport in use.
+			t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+			if t.TLSClientConfig != nil {
+				t.TLSClientConfig = t.TLSClientConfig.Clone()
+				var protos []string
+				for _, proto := range t.TLSClientConfig.NextProtos {
+					if proto != "h2" {
+						protos = append(protos, proto)
+					}
+				}
+				t.TLSClientConfig.NextProtos = protos
+			}
+		}
+	}
+}
+
+// WithTlsConfig sets the TLS configuration used to connect to the server,
+// for example to trust a private CA or to present a client certificate.
+func WithTlsConfig(config *tls.Config) Option {
+	return func(p *Client) {
+		p.transport().TLSClientConfig = config.Clone()
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate.
+// It should only be used against test servers.
+func WithInsecureSkipVerify() Option {
+	return func(p *Client) {
+		t := p.transport()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+}
+
+// WithProxy routes requests through proxy, which may be an http, https or
+// socks5 url. A nil proxy disables proxying, including from the environment.
+func WithProxy(proxy *url.URL) Option {
+	return func(p *Client) {
+		if proxy == nil {
+			p.transport().Proxy = nil
+			return
+		}
+		p.transport().Proxy = http.ProxyURL(proxy)
+	}
+}
+
+// WithProxyFromEnvironment picks the proxy from HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY, as http.DefaultTransport does.
+func WithProxyFromEnvironment() Option {
+	return func(p *Client) {
+		p.transport().Proxy = http.ProxyFromEnvironment
+	}
+}
+
+// WithRequestSigner signs every request with signer, for example a
+// SigV4Signer.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(p *Client) {
+		p.signer = signer
+	}
+}
+
+// WithTokenSource authenticates every request with a token from source, so
+// short lived tokens are refreshed as they expire. It takes precedence over
+// WithToken and WithBasicAuth.
+func WithTokenSource(source TokenSource) Option {
+	return func(p *Client) {
+		p.tokenSource = &reuseTokenSource{source: source}
+	}
+}
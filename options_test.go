@@ -0,0 +1,196 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAuthentication(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("MLFLOW_TRACKING_USERNAME", "")
+	t.Setenv("MLFLOW_TRACKING_PASSWORD", "")
+	t.Setenv("MLFLOW_TRACKING_TOKEN", "")
+	cases := []struct {
+		name   string
+		env    map[string]string
+		opts   []Option
+		header string
+	}{
+		{"none", nil, nil, ""},
+		{"basic", nil, []Option{WithBasicAuth("user", "pass")}, "Basic dXNlcjpwYXNz"},
+		{"token", nil, []Option{WithToken("secret")}, "Bearer secret"},
+		{"env basic", map[string]string{"MLFLOW_TRACKING_USERNAME": "user", "MLFLOW_TRACKING_PASSWORD": "pass"}, nil, "Basic dXNlcjpwYXNz"},
+		{"env token", map[string]string{"MLFLOW_TRACKING_TOKEN": "secret"}, nil, "Bearer secret"},
+		{"option overrides env", map[string]string{"MLFLOW_TRACKING_TOKEN": "secret"}, []Option{WithBasicAuth("user", "pass")}, "Basic dXNlcjpwYXNz"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for key, value := range c.env {
+				t.Setenv(key, value)
+			}
+			client := New(server.URL, c.opts...)
+			if _, err := client.HandlePost(context.Background(), server.URL+"/api/2.0/mlflow/runs/set-tag", map[string]interface{}{}); err != nil {
+				t.Fatal(err)
+			}
+			if authorization != c.header {
+				t.Errorf("got Authorization %q, want %q", authorization, c.header)
+			}
+		})
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithUserAgent("trainer/1.0"), WithHeader("X-Tenant", "research"), WithHeader("X-Request-Id", "42"))
+	if _, err := client.HandleGet(context.Background(), server.URL+"/api/2.0/mlflow/runs/get", nil); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("User-Agent") != "trainer/1.0" || header.Get("X-Tenant") != "research" || header.Get("X-Request-Id") != "42" {
+		t.Errorf("unexpected headers %v", header)
+	}
+	if _, err := client.HandlePost(context.Background(), server.URL+"/api/2.0/mlflow/runs/set-tag", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Tenant") != "research" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers %v", header)
+	}
+}
+
+func TestTlsOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	url := server.URL + "/api/2.0/mlflow/runs/get"
+
+	if _, err := New(server.URL).HandleGet(context.Background(), url, nil); err == nil {
+		t.Error("expected an unknown authority error")
+	}
+	if _, err := New(server.URL, WithInsecureSkipVerify()).HandleGet(context.Background(), url, nil); err != nil {
+		t.Error(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	if _, err := New(server.URL, WithTlsConfig(&tls.Config{RootCAs: pool})).HandleGet(context.Background(), url, nil); err != nil {
+		t.Error(err)
+	}
+	config := http.DefaultTransport.(*http.Transport).TLSClientConfig
+	if http.DefaultClient.Transport != nil || config != nil && (config.InsecureSkipVerify || config.RootCAs != nil) {
+		t.Error("options changed the default client")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+	proxyUrl, _ := url.Parse(proxy.URL)
+
+	client := New("http://mlflow.internal", WithProxy(proxyUrl))
+	if _, err := client.HandleGet(context.Background(), client.BaseUrl+"/api/2.0/mlflow/runs/get", map[string]interface{}{"run_id": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if requested != "http://mlflow.internal/api/2.0/mlflow/runs/get?run_id=1" {
+		t.Errorf("unexpected proxied request %s", requested)
+	}
+}
+
+type countingTokenSource struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (s *countingTokenSource) Token() (*Token, error) {
+	s.calls++
+	return &Token{AccessToken: fmt.Sprintf("token-%d", s.calls), Expiry: time.Now().Add(s.expiry)}, nil
+}
+
+func TestWithTokenSource(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	source := &countingTokenSource{expiry: time.Hour}
+	client := New(server.URL, WithToken("static"), WithTokenSource(source))
+	for i := 0; i < 2; i++ {
+		if _, err := client.HandleGet(context.Background(), server.URL+"/api/2.0/mlflow/runs/get", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if authorization != "Bearer token-1" || source.calls != 1 {
+		t.Errorf("got %q after %d calls", authorization, source.calls)
+	}
+
+	// Tokens about to expire are refreshed before each request.
+	source = &countingTokenSource{expiry: time.Second}
+	client = New(server.URL, WithTokenSource(source))
+	for i := 0; i < 2; i++ {
+		if _, err := client.HandleGet(context.Background(), server.URL+"/api/2.0/mlflow/runs/get", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if authorization != "Bearer token-2" {
+		t.Errorf("got %q after %d calls", authorization, source.calls)
+	}
+}
+
+func TestTransportTuning(t *testing.T) {
+	var protocol string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protocol = r.Proto
+		w.Write([]byte(`{}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	url := server.URL + "/api/2.0/mlflow/runs/get"
+	ctx := context.Background()
+
+	client := New(server.URL, WithInsecureSkipVerify(), WithIdleConnTimeout(time.Minute))
+	if _, err := client.HandleGet(ctx, url, nil); err != nil {
+		t.Fatal(err)
+	}
+	if protocol != "HTTP/2.0" {
+		t.Errorf("expected HTTP/2, got %s", protocol)
+	}
+	transport := client.Client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("unexpected transport settings %d %s", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+
+	client = New(server.URL, WithInsecureSkipVerify(), WithHttp2(false), WithMaxIdleConnsPerHost(256))
+	if _, err := client.HandleGet(ctx, url, nil); err != nil {
+		t.Fatal(err)
+	}
+	if protocol != "HTTP/1.1" {
+		t.Errorf("expected HTTP/1.1, got %s", protocol)
+	}
+	if transport := client.Client.Transport.(*http.Transport); transport.MaxIdleConnsPerHost != 256 || transport.MaxIdleConns < 256 {
+		t.Errorf("unexpected transport settings %d %d", transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+	}
+}
@@ -0,0 +1,183 @@
+package mlflow
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LogParamsFromStruct logs the fields of cfg, a struct, a map or a pointer
+// to one, as params. See FlattenParams for the keys.
+func (p *Client) LogParamsFromStruct(ctx context.Context, runId string, cfg interface{}) error {
+	params, err := FlattenParams(cfg)
+	if err != nil {
+		return err
+	}
+	return p.LogBatch(ctx, runId, nil, params, nil)
+}
+
+// FlattenParams flattens cfg into params with dotted keys. Nested structs
+// and maps add their field name or key as a prefix, while embedded structs
+// don't. A field is named by its `mlflow` tag, or else its `json` tag, or
+// else its Go name; a field tagged `mlflow:"-"` is left out, as is an empty
+// field tagged omitempty. Values that implement encoding.TextMarshaler or
+// fmt.Stringer are logged as such, slices as JSON, and nil pointers and
+// slices not at all.
+func FlattenParams(cfg interface{}) ([]Param, error) {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("mlflow: cannot flatten %T into params", cfg)
+	}
+	var params []Param
+	if err := flattenValue("", v, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func flattenValue(key string, v reflect.Value, params *[]Param) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if value, ok := formatParam(v); ok {
+		*params = append(*params, Param{Key: key, Value: value})
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			embedded := field.Anonymous && indirect(field.Type).Kind() == reflect.Struct
+			if !field.IsExported() && !embedded {
+				continue
+			}
+			name, omitEmpty, ok := paramName(field)
+			if !ok || omitEmpty && v.Field(i).IsZero() {
+				continue
+			}
+			if embedded && name == "" {
+				if err := flattenValue(key, v.Field(i), params); err != nil {
+					return err
+				}
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if err := flattenValue(joinKey(key, name), v.Field(i), params); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]reflect.Value{}
+		iter := v.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, k)
+			values[k] = iter.Value()
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := flattenValue(joinKey(key, k), values[k], params); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		*params = append(*params, Param{Key: key, Value: string(b)})
+		return nil
+	}
+	return fmt.Errorf("mlflow: cannot log %s of type %s as a param", key, v.Type())
+}
+
+// paramName returns the name a struct tag gives field, whether it has the
+// omitempty option, and false when the field is left out.
+func paramName(field reflect.StructField) (string, bool, bool) {
+	for _, key := range []string{"mlflow", "json"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if parts[0] == "-" {
+			return "", false, false
+		}
+		omitEmpty := false
+		for _, option := range parts[1:] {
+			omitEmpty = omitEmpty || option == "omitempty"
+		}
+		return parts[0], omitEmpty, true
+	}
+	return "", false, true
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// formatParam formats scalar values and values that format themselves.
+func formatParam(v reflect.Value) (string, bool) {
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(b), true
+		}
+	}
+	if v.Type().Implements(stringerType) {
+		return v.Interface().(fmt.Stringer).String(), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	}
+	return "", false
+}
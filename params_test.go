@@ -0,0 +1,91 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type optimizerConfig struct {
+	Name         string  `mlflow:"name"`
+	LearningRate float64 `json:"lr,omitempty"`
+	Betas        []float64
+}
+
+type Common struct {
+	Seed int64
+}
+
+type trainConfig struct {
+	Common
+	Epochs    int             `mlflow:"epochs"`
+	Timeout   time.Duration   `mlflow:"timeout"`
+	Optimizer optimizerConfig `mlflow:"optimizer"`
+	Scheduler *optimizerConfig
+	Secret    string `mlflow:"-"`
+	Labels    map[string]interface{}
+	debug     bool
+}
+
+func TestFlattenParams(t *testing.T) {
+	cfg := trainConfig{
+		Common:    Common{Seed: 42},
+		Epochs:    10,
+		Timeout:   90 * time.Second,
+		Optimizer: optimizerConfig{Name: "adam", LearningRate: 0.001, Betas: []float64{0.9, 0.999}},
+		Secret:    "hunter2",
+		Labels:    map[string]interface{}{"team": "nlp", "tier": map[string]int{"gpu": 2}},
+		debug:     true,
+	}
+	params, err := FlattenParams(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Param{
+		{Key: "Seed", Value: "42"},
+		{Key: "epochs", Value: "10"},
+		{Key: "timeout", Value: "1m30s"},
+		{Key: "optimizer.name", Value: "adam"},
+		{Key: "optimizer.lr", Value: "0.001"},
+		{Key: "optimizer.Betas", Value: "[0.9,0.999]"},
+		{Key: "Labels.team", Value: "nlp"},
+		{Key: "Labels.tier.gpu", Value: "2"},
+	}
+	if len(params) != len(expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+	for i := range expected {
+		if params[i] != expected[i] {
+			t.Errorf("param %d: got %v, want %v", i, params[i], expected[i])
+		}
+	}
+	if _, err := FlattenParams(3); err == nil {
+		t.Error("expected an error for a non struct config")
+	}
+	if _, err := FlattenParams(struct{ Fn func() }{}); err == nil {
+		t.Error("expected an error for a func field")
+	}
+}
+
+func TestLogParamsFromStruct(t *testing.T) {
+	var request struct {
+		RunId  string  `json:"run_id"`
+		Params []Param `json:"params"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	if err := client.LogParamsFromStruct(context.Background(), "run1", map[string]interface{}{"batch_size": 32, "model": optimizerConfig{Name: "sgd"}}); err != nil {
+		t.Fatal(err)
+	}
+	if request.RunId != "run1" || len(request.Params) != 2 || request.Params[0] != (Param{Key: "batch_size", Value: "32"}) || request.Params[1] != (Param{Key: "model.name", Value: "sgd"}) {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
@@ -0,0 +1,238 @@
+package mlflow
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prompts are stored in the model registry, as the Python client does: a
+// prompt is a registered model and its versions are model versions, marked
+// by TagIsPrompt, whose templates are in the TagPromptText tag.
+const (
+	TagIsPrompt   = "mlflow.prompt.is_prompt"
+	TagPromptText = "mlflow.prompt.text"
+)
+
+// promptSource is the source of the model versions of prompts, which have
+// no artifacts.
+const promptSource = "dummy-source"
+
+// Prompt is a prompt of the prompt registry.
+type Prompt struct {
+	Name                 string
+	Description          string
+	CreationTimestamp    int64
+	LastUpdatedTimestamp int64
+	Tags                 map[string]string
+	// Aliases maps aliases to versions.
+	Aliases map[string]string
+}
+
+// PromptVersion is a version of a prompt. Its template refers to variables
+// as {{name}}.
+type PromptVersion struct {
+	Name                 string
+	Version              string
+	Template             string
+	CommitMessage        string
+	CreationTimestamp    int64
+	LastUpdatedTimestamp int64
+	UserId               string
+	Tags                 map[string]string
+	Aliases              []string
+}
+
+var promptVariable = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// Uri returns the prompts:/ uri of the version.
+func (v *PromptVersion) Uri() string {
+	return "prompts:/" + v.Name + "/" + v.Version
+}
+
+// Variables returns the variables of the template, in order of first use.
+func (v *PromptVersion) Variables() []string {
+	var variables []string
+	seen := map[string]bool{}
+	for _, match := range promptVariable.FindAllStringSubmatch(v.Template, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+// Format replaces the variables of the template with values, failing when
+// one of them has no value.
+func (v *PromptVersion) Format(values map[string]string) (string, error) {
+	var missing []string
+	text := promptVariable.ReplaceAllStringFunc(v.Template, func(s string) string {
+		name := promptVariable.FindStringSubmatch(s)[1]
+		value, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			return s
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("mlflow: no value for %s in prompt %s", strings.Join(missing, ", "), v.Uri())
+	}
+	return text, nil
+}
+
+func newPrompt(model *RegisteredModel) *Prompt {
+	prompt := &Prompt{
+		Name:                 model.Name,
+		Description:          model.Description,
+		CreationTimestamp:    model.CreationTimestamp,
+		LastUpdatedTimestamp: model.LastUpdatedTimestamp,
+		Tags:                 map[string]string{},
+		Aliases:              map[string]string{},
+	}
+	for _, tag := range model.Tags {
+		if tag.Key != TagIsPrompt {
+			prompt.Tags[tag.Key] = tag.Value
+		}
+	}
+	for _, alias := range model.Aliases {
+		prompt.Aliases[alias.Alias] = alias.Version
+	}
+	return prompt
+}
+
+func newPromptVersion(version *ModelVersion) (*PromptVersion, error) {
+	prompt := &PromptVersion{
+		Name:                 version.Name,
+		Version:              version.Version,
+		CommitMessage:        version.Description,
+		CreationTimestamp:    version.CreationTimestamp,
+		LastUpdatedTimestamp: version.LastUpdatedTimestamp,
+		UserId:               version.UserId,
+		Tags:                 map[string]string{},
+		Aliases:              version.Aliases,
+	}
+	marked := false
+	for _, tag := range version.Tags {
+		switch tag.Key {
+		case TagIsPrompt:
+			marked = tag.Value == "true"
+		case TagPromptText:
+			prompt.Template = tag.Value
+		default:
+			prompt.Tags[tag.Key] = tag.Value
+		}
+	}
+	if !marked {
+		return nil, fmt.Errorf("mlflow: %s version %s is a model version, not a prompt", version.Name, version.Version)
+	}
+	return prompt, nil
+}
+
+// RegisterPrompt creates a version of the prompt name with template, creating
+// the prompt if needed. commitMessage describes the changes of the version
+// and may be empty.
+func (p *Client) RegisterPrompt(ctx context.Context, name string, template string, commitMessage string, tags map[string]string) (*PromptVersion, error) {
+	model, err := p.GetRegisteredModel(ctx, name)
+	if IsNotFound(err) {
+		if _, err = p.CreateRegisteredModel(ctx, name, ""); err != nil && !IsAlreadyExists(err) {
+			return nil, err
+		}
+		if err := p.SetRegisteredModelTag(ctx, name, TagIsPrompt, "true"); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !isPrompt(model) {
+		return nil, fmt.Errorf("mlflow: %s is a registered model, not a prompt", name)
+	}
+
+	versionTags := []ModelVersionTag{{Key: TagIsPrompt, Value: "true"}, {Key: TagPromptText, Value: template}}
+	for _, key := range sortedKeys(tags) {
+		versionTags = append(versionTags, ModelVersionTag{Key: key, Value: tags[key]})
+	}
+	version, err := p.CreateModelVersion(ctx, name, promptSource, "", versionTags...)
+	if err != nil {
+		return nil, err
+	}
+	if commitMessage != "" {
+		if version, err = p.UpdateModelVersion(ctx, name, version.Version, commitMessage); err != nil {
+			return nil, err
+		}
+	}
+	return newPromptVersion(version)
+}
+
+func isPrompt(model *RegisteredModel) bool {
+	for _, tag := range model.Tags {
+		if tag.Key == TagIsPrompt {
+			return tag.Value == "true"
+		}
+	}
+	return false
+}
+
+func (p *Client) GetPrompt(ctx context.Context, name string) (*Prompt, error) {
+	model, err := p.GetRegisteredModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !isPrompt(model) {
+		return nil, fmt.Errorf("mlflow: %s is a registered model, not a prompt", name)
+	}
+	return newPrompt(model), nil
+}
+
+func (p *Client) GetPromptVersion(ctx context.Context, name string, version string) (*PromptVersion, error) {
+	modelVersion, err := p.GetModelVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	return newPromptVersion(modelVersion)
+}
+
+// LoadPrompt returns the prompt version uri refers to, as
+// prompts:/<name>/<version> or prompts:/<name>@<alias>.
+func (p *Client) LoadPrompt(ctx context.Context, uri string) (*PromptVersion, error) {
+	ref := strings.TrimPrefix(uri, "prompts:/")
+	if ref == uri {
+		return nil, fmt.Errorf("mlflow: %s is not a prompts:/ uri", uri)
+	}
+	version, err := p.ResolveModelUri(ctx, "models:/"+ref)
+	if err != nil {
+		return nil, err
+	}
+	return newPromptVersion(version)
+}
+
+// SearchPrompts lists the prompts matching filter, a registered model filter
+// such as "name LIKE 'qa-%'", which may be empty.
+func (p *Client) SearchPrompts(ctx context.Context, filter string, maxResults int, pageToken string) ([]Prompt, string, error) {
+	promptFilter := "tags.`" + TagIsPrompt + "` = 'true'"
+	if filter != "" {
+		promptFilter += " AND " + filter
+	}
+	response, err := p.SearchRegisteredModels(ctx, promptFilter, maxResults, nil, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	prompts := make([]Prompt, len(response.RegisteredModels))
+	for i := range response.RegisteredModels {
+		prompts[i] = *newPrompt(&response.RegisteredModels[i])
+	}
+	return prompts, response.NextPageToken, nil
+}
+
+func (p *Client) SetPromptAlias(ctx context.Context, name string, alias string, version string) error {
+	return p.SetRegisteredModelAlias(ctx, name, alias, version)
+}
+
+func (p *Client) DeletePromptAlias(ctx context.Context, name string, alias string) error {
+	return p.DeleteRegisteredModelAlias(ctx, name, alias)
+}
+
+func (p *Client) DeletePromptVersion(ctx context.Context, name string, version string) error {
+	return p.DeleteModelVersion(ctx, name, version)
+}
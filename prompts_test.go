@@ -0,0 +1,73 @@
+package mlflow_test
+
+import (
+	"context"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestPromptRegistry(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	v1, err := client.RegisterPrompt(ctx, "qa", "Answer {{question}} briefly.", "first draft", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1.Version != "1" || v1.CommitMessage != "first draft" || v1.Uri() != "prompts:/qa/1" {
+		t.Errorf("unexpected version %+v", v1)
+	}
+	v2, err := client.RegisterPrompt(ctx, "qa", "Answer {{ question }} about {{topic}}, citing {{topic}}.", "", map[string]string{"author": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetPromptAlias(ctx, "qa", "production", v2.Version); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := client.LoadPrompt(ctx, "prompts:/qa@production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != "2" || loaded.Tags["author"] != "alice" || len(loaded.Tags) != 1 {
+		t.Errorf("unexpected version %+v", loaded)
+	}
+	if variables := loaded.Variables(); len(variables) != 2 || variables[0] != "question" || variables[1] != "topic" {
+		t.Errorf("unexpected variables %v", variables)
+	}
+	text, err := loaded.Format(map[string]string{"question": "why", "topic": "Go"})
+	if err != nil || text != "Answer why about Go, citing Go." {
+		t.Errorf("unexpected text %q, %v", text, err)
+	}
+	if _, err := loaded.Format(map[string]string{"question": "why"}); err == nil {
+		t.Error("expected a missing variable to fail")
+	}
+	if loaded, err = client.LoadPrompt(ctx, "prompts:/qa/1"); err != nil || loaded.Template != "Answer {{question}} briefly." {
+		t.Errorf("unexpected version %+v, %v", loaded, err)
+	}
+
+	prompt, err := client.GetPrompt(ctx, "qa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.Aliases["production"] != "2" || len(prompt.Tags) != 0 {
+		t.Errorf("unexpected prompt %+v", prompt)
+	}
+	if _, err := client.CreateRegisteredModel(ctx, "classifier", ""); err != nil {
+		t.Fatal(err)
+	}
+	prompts, _, err := client.SearchPrompts(ctx, "", 0, "")
+	if err != nil || len(prompts) != 1 || prompts[0].Name != "qa" {
+		t.Errorf("unexpected prompts %+v, %v", prompts, err)
+	}
+	if _, err := client.RegisterPrompt(ctx, "classifier", "{{x}}", "", nil); err == nil {
+		t.Error("expected registering a prompt over a model to fail")
+	}
+	if _, err := client.LoadPrompt(ctx, "models:/qa/1"); err == nil {
+		t.Error("expected a models:/ uri to fail")
+	}
+}
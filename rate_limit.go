@@ -0,0 +1,67 @@
+package mlflow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to rps requests per second on average,
+// allowing bursts of up to burst requests. The limit is shared by every
+// goroutine using the client and applies to each retry attempt. An rps of
+// zero or less removes the limit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(p *Client) {
+		if rps <= 0 {
+			p.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		p.limiter = &rateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst)}
+	}
+}
+
+// rateLimiter is a token bucket. Waiters reserve a token up front, so they
+// are served in the order they arrive.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,51 @@
+package mlflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, WithRateLimit(50, 2))
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := client.SetTag(context.Background(), "run1", "key", "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two requests fit in the burst, the other four wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("six requests took only %s", elapsed)
+	}
+
+	client = New(server.URL, WithRateLimit(0.1, 1))
+	if err := client.SetTag(context.Background(), "run1", "key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.SetTag(ctx, "run1", "key", "value"); err == nil {
+		t.Error("expected the rate limited request to be cancelled")
+	}
+
+	for _, rps := range []float64{0, -1} {
+		client = New(server.URL, WithRateLimit(50, 1), WithRateLimit(rps, 1))
+		start = time.Now()
+		for i := 0; i < 5; i++ {
+			if err := client.SetTag(context.Background(), "run1", "key", "value"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("rps %v: expected no limit, five requests took %s", rps, elapsed)
+		}
+	}
+}
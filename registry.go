@@ -0,0 +1,399 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ModelVersionStatus string
+
+const (
+	PendingRegistration ModelVersionStatus = "PENDING_REGISTRATION"
+	FailedRegistration  ModelVersionStatus = "FAILED_REGISTRATION"
+	Ready               ModelVersionStatus = "READY"
+)
+
+type ResponseModelVersion struct {
+	ModelVersion ModelVersion `json:"model_version"`
+}
+
+type ResponseRegisteredModel struct {
+	RegisteredModel RegisteredModel `json:"registered_model"`
+}
+
+func decodeRegisteredModel(body []byte, err error) (*RegisteredModel, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseRegisteredModel
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.RegisteredModel, nil
+}
+
+func (p *Client) CreateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/create"
+	request := map[string]interface{}{"name": name}
+	if description != "" {
+		request["description"] = description
+	}
+	return decodeRegisteredModel(p.HandlePost(ctx, url, request))
+}
+
+func (p *Client) GetRegisteredModel(ctx context.Context, name string) (*RegisteredModel, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/get"
+	params := map[string]interface{}{"name": name}
+	return decodeRegisteredModel(p.cached(url, params, func() ([]byte, error) { return p.HandleGet(ctx, url, params) }))
+}
+
+func (p *Client) UpdateRegisteredModel(ctx context.Context, name string, description string) (*RegisteredModel, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/update"
+	return decodeRegisteredModel(p.HandlePatch(ctx, url, map[string]interface{}{"name": name, "description": description}))
+}
+
+func (p *Client) RenameRegisteredModel(ctx context.Context, name string, newName string) (*RegisteredModel, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/rename"
+	return decodeRegisteredModel(p.HandlePost(ctx, url, map[string]interface{}{"name": name, "new_name": newName}))
+}
+
+func (p *Client) DeleteRegisteredModel(ctx context.Context, name string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name})
+	return err
+}
+
+func decodeModelVersion(body []byte, err error) (*ModelVersion, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseModelVersion
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.ModelVersion, nil
+}
+
+func (p *Client) CreateModelVersion(ctx context.Context, name string, source string, runId string, tags ...ModelVersionTag) (*ModelVersion, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/create"
+	request := map[string]interface{}{"name": name, "source": source}
+	if runId != "" {
+		request["run_id"] = runId
+	}
+	if len(tags) > 0 {
+		request["tags"] = tags
+	}
+	return decodeModelVersion(p.HandlePost(ctx, url, request))
+}
+
+func (p *Client) GetModelVersion(ctx context.Context, name string, version string) (*ModelVersion, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/get"
+	return decodeModelVersion(p.HandleGet(ctx, url, map[string]interface{}{"name": name, "version": version}))
+}
+
+func (p *Client) UpdateModelVersion(ctx context.Context, name string, version string, description string) (*ModelVersion, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/update"
+	return decodeModelVersion(p.HandlePatch(ctx, url, map[string]interface{}{"name": name, "version": version, "description": description}))
+}
+
+func (p *Client) DeleteModelVersion(ctx context.Context, name string, version string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name, "version": version})
+	return err
+}
+
+// WaitForModelVersion polls a model version every interval until it leaves
+// PENDING_REGISTRATION, returning an error if registration failed. interval
+// defaults to a second when it is not positive.
+func (p *Client) WaitForModelVersion(ctx context.Context, name string, version string, interval time.Duration) (*ModelVersion, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		modelVersion, err := p.GetModelVersion(ctx, name, version)
+		if err != nil {
+			return nil, err
+		}
+		switch ModelVersionStatus(modelVersion.Status) {
+		case FailedRegistration:
+			return modelVersion, fmt.Errorf("mlflow: registration of %s version %s failed: %s", name, version, modelVersion.StatusMessage)
+		case PendingRegistration:
+		default:
+			return modelVersion, nil
+		}
+		select {
+		case <-ctx.Done():
+			return modelVersion, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+const (
+	StageNone       = "None"
+	StageStaging    = "Staging"
+	StageProduction = "Production"
+	StageArchived   = "Archived"
+)
+
+func (p *Client) TransitionModelVersionStage(ctx context.Context, name string, version string, stage string, archiveExisting bool) (*ModelVersion, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/transition-stage"
+	return decodeModelVersion(p.HandlePost(ctx, url, map[string]interface{}{"name": name, "version": version, "stage": stage, "archive_existing_versions": archiveExisting}))
+}
+
+type ResponseSearchRegisteredModels struct {
+	RegisteredModels []RegisteredModel `json:"registered_models"`
+	NextPageToken    string            `json:"next_page_token,omitempty"`
+}
+
+type ResponseSearchModelVersions struct {
+	ModelVersions []ModelVersion `json:"model_versions"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+}
+
+func searchParams(filter string, maxResults int, orderBy []string, pageToken string) map[string]interface{} {
+	params := map[string]interface{}{}
+	if filter != "" {
+		params["filter"] = filter
+	}
+	if maxResults > 0 {
+		params["max_results"] = maxResults
+	}
+	if len(orderBy) > 0 {
+		params["order_by"] = orderBy
+	}
+	if pageToken != "" {
+		params["page_token"] = pageToken
+	}
+	return params
+}
+
+func (p *Client) SearchRegisteredModels(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchRegisteredModels, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/search"
+	body, err := p.HandleGet(ctx, url, searchParams(filter, maxResults, orderBy, pageToken))
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchRegisteredModels
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Client) SearchModelVersions(ctx context.Context, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchModelVersions, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/search"
+	body, err := p.HandleGet(ctx, url, searchParams(filter, maxResults, orderBy, pageToken))
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchModelVersions
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+type ResponseModelVersions struct {
+	ModelVersions []ModelVersion `json:"model_versions"`
+}
+
+func (p *Client) GetLatestVersions(ctx context.Context, name string, stages []string) ([]ModelVersion, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/get-latest-versions"
+	request := map[string]interface{}{"name": name}
+	if len(stages) > 0 {
+		request["stages"] = stages
+	}
+	body, err := p.cached(url, request, func() ([]byte, error) { return p.HandlePost(ctx, url, request) })
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseModelVersions
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.ModelVersions, nil
+}
+
+func (p *Client) SetRegisteredModelAlias(ctx context.Context, name string, alias string, version string) error {
+	if err := p.requireAliases(); err != nil {
+		return err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/alias"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"name": name, "alias": alias, "version": version})
+	return err
+}
+
+func (p *Client) DeleteRegisteredModelAlias(ctx context.Context, name string, alias string) error {
+	if err := p.requireAliases(); err != nil {
+		return err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/alias"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name, "alias": alias})
+	return err
+}
+
+func (p *Client) GetModelVersionByAlias(ctx context.Context, name string, alias string) (*ModelVersion, error) {
+	if err := p.requireAliases(); err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/alias"
+	return decodeModelVersion(p.HandleGet(ctx, url, map[string]interface{}{"name": name, "alias": alias}))
+}
+
+// ResolveModelUri looks up the model version referenced by a models:/ uri,
+// accepting models:/<name>@<alias>, models:/<name>/<version> and
+// models:/<name>/<stage>.
+func (p *Client) ResolveModelUri(ctx context.Context, uri string) (*ModelVersion, error) {
+	ref := strings.TrimPrefix(uri, "models:/")
+	if ref == uri || ref == "" {
+		return nil, fmt.Errorf("mlflow: %s is not a models:/ uri", uri)
+	}
+	if i := strings.LastIndex(ref, "@"); i >= 0 && !strings.Contains(ref[i:], "/") {
+		return p.GetModelVersionByAlias(ctx, ref[:i], ref[i+1:])
+	}
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return nil, fmt.Errorf("mlflow: %s does not name a version, stage or alias", uri)
+	}
+	name, suffix := ref[:i], ref[i+1:]
+	if _, err := strconv.Atoi(suffix); err == nil {
+		return p.GetModelVersion(ctx, name, suffix)
+	}
+	var stages []string
+	if !strings.EqualFold(suffix, "latest") {
+		stages = []string{suffix}
+	}
+	versions, err := p.GetLatestVersions(ctx, name, stages)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("mlflow: no version of %s in stage %s", name, suffix)
+	}
+	// "latest" is the newest version across all stages.
+	latest := &versions[0]
+	for i := range versions {
+		a, _ := strconv.Atoi(versions[i].Version)
+		b, _ := strconv.Atoi(latest.Version)
+		if a > b {
+			latest = &versions[i]
+		}
+	}
+	return latest, nil
+}
+
+func (p *Client) SetRegisteredModelTag(ctx context.Context, name string, key string, value string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/set-tag"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"name": name, "key": key, "value": value})
+	return err
+}
+
+func (p *Client) DeleteRegisteredModelTag(ctx context.Context, name string, key string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registered-models/delete-tag"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name, "key": key})
+	return err
+}
+
+func (p *Client) SetModelVersionTag(ctx context.Context, name string, version string, key string, value string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/set-tag"
+	_, err := p.HandlePost(ctx, url, map[string]interface{}{"name": name, "version": version, "key": key, "value": value})
+	return err
+}
+
+func (p *Client) DeleteModelVersionTag(ctx context.Context, name string, version string, key string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/delete-tag"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"name": name, "version": version, "key": key})
+	return err
+}
+
+func (p *Client) GetModelVersionDownloadUri(ctx context.Context, name string, version string) (string, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/get-download-uri"
+	body, err := p.HandleGet(ctx, url, map[string]interface{}{"name": name, "version": version})
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		ArtifactUri string `json:"artifact_uri"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", err
+	}
+	return response.ArtifactUri, nil
+}
+
+// DownloadModelVersion downloads every artifact behind a registered model
+// version into localDir, keeping the layout relative to the version's
+// download uri. Paths escaping localDir are rejected before any download.
+func (p *Client) DownloadModelVersion(ctx context.Context, name string, version string, localDir string, opts ...DownloadOption) error {
+	uri, err := p.GetModelVersionDownloadUri(ctx, name, version)
+	if err != nil {
+		return err
+	}
+	repo, err := p.ArtifactRepository(uri)
+	if err != nil {
+		return err
+	}
+	options := downloadOptions{concurrency: 1, attempts: 3}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	paths, err := walkRepository(ctx, repo, "")
+	if err != nil {
+		return err
+	}
+	localPaths := make(map[string]string, len(paths))
+	for _, artifactPath := range paths {
+		if localPaths[artifactPath], err = localArtifactPath(localDir, artifactPath); err != nil {
+			return err
+		}
+	}
+	return downloadAll(ctx, paths, options, func(artifactPath string) error {
+		return repo.Download(ctx, artifactPath, localPaths[artifactPath])
+	})
+}
+
+// CopyModelVersion copies a model version into the registered model dstName,
+// creating it if needed. The new version's source is the models:/ uri of the
+// original and it keeps the original's run, tags and description, so the
+// provenance of the copy can be traced back.
+func (p *Client) CopyModelVersion(ctx context.Context, srcName string, srcVersion string, dstName string) (*ModelVersion, error) {
+	src, err := p.GetModelVersion(ctx, srcName, srcVersion)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.GetRegisteredModel(ctx, dstName); IsNotFound(err) {
+		if _, err := p.CreateRegisteredModel(ctx, dstName, ""); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/model-versions/create"
+	request := map[string]interface{}{"name": dstName, "source": "models:/" + src.Name + "/" + src.Version}
+	if src.RunId != "" {
+		request["run_id"] = src.RunId
+	}
+	if src.RunLink != "" {
+		request["run_link"] = src.RunLink
+	}
+	if src.Description != "" {
+		request["description"] = src.Description
+	}
+	if len(src.Tags) > 0 {
+		request["tags"] = src.Tags
+	}
+	return decodeModelVersion(p.HandlePost(ctx, url, request))
+}
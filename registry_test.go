@@ -0,0 +1,451 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveModelUri(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/registered-models/alias":
+			q := r.URL.Query()
+			json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: q.Get("name"), Version: "3", Aliases: []string{q.Get("alias")}}})
+		case "/api/2.0/mlflow/model-versions/get":
+			q := r.URL.Query()
+			json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: q.Get("name"), Version: q.Get("version")}})
+		case "/api/2.0/mlflow/registered-models/get-latest-versions":
+			var request struct {
+				Name   string   `json:"name"`
+				Stages []string `json:"stages"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			versions := []ModelVersion{{Name: request.Name, Version: "9", CurrentStage: StageStaging}, {Name: request.Name, Version: "10", CurrentStage: StageNone}}
+			if len(request.Stages) == 1 && request.Stages[0] == StageProduction {
+				versions = []ModelVersion{{Name: request.Name, Version: "7", CurrentStage: StageProduction}}
+			}
+			json.NewEncoder(w).Encode(ResponseModelVersions{ModelVersions: versions})
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	tests := []struct {
+		uri     string
+		version string
+	}{
+		{"models:/my/model@champion", "3"},
+		{"models:/my/model/5", "5"},
+		{"models:/my/model/Production", "7"},
+		{"models:/my/model/latest", "10"},
+	}
+	for _, test := range tests {
+		modelVersion, err := client.ResolveModelUri(context.Background(), test.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if modelVersion.Name != "my/model" || modelVersion.Version != test.version {
+			t.Errorf("Expected %s to resolve to version %s, got %+v", test.uri, test.version, modelVersion)
+		}
+	}
+	if _, err := client.ResolveModelUri(context.Background(), "runs:/abc/model"); err == nil {
+		t.Error("Expected an error for a non models:/ uri")
+	}
+}
+
+func TestWaitForModelVersion(t *testing.T) {
+	polls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/mlflow/model-versions/get" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		name := r.URL.Query().Get("name")
+		polls[name]++
+		status := PendingRegistration
+		if polls[name] == 3 {
+			status = Ready
+			if name == "broken" {
+				status = FailedRegistration
+			}
+		}
+		json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: name, Version: "1", Status: string(status), StatusMessage: "no such file"}})
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	modelVersion, err := client.WaitForModelVersion(context.Background(), "model", "1", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modelVersion.Status != string(Ready) || polls["model"] != 3 {
+		t.Errorf("unexpected version %+v after %d polls", modelVersion, polls["model"])
+	}
+	modelVersion, err = client.WaitForModelVersion(context.Background(), "broken", "1", time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no such file") {
+		t.Errorf("expected the registration failure, got %v", err)
+	}
+	if modelVersion == nil || modelVersion.Status != string(FailedRegistration) {
+		t.Errorf("unexpected version %+v", modelVersion)
+	}
+}
+
+func TestWaitForModelVersionDefaultInterval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: "model", Version: "1", Status: string(PendingRegistration)}})
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := New(server.URL).WaitForModelVersion(ctx, "model", "1", 0); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to be reached, got %v", err)
+	}
+}
+
+func TestDownloadModelVersion(t *testing.T) {
+	source := t.TempDir()
+	os.MkdirAll(filepath.Join(source, "data"), 0755)
+	os.WriteFile(filepath.Join(source, "MLmodel"), []byte("flavors"), 0644)
+	os.WriteFile(filepath.Join(source, "data", "model.pkl"), []byte("weights"), 0644)
+	listing := []FileInfo{{Path: "MLmodel"}, {Path: "data", IsDir: true}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		switch {
+		case r.URL.Path == "/api/2.0/mlflow/model-versions/get-download-uri":
+			if r.URL.Query().Get("version") != "1" {
+				t.Errorf("unexpected query %v", r.URL.Query())
+			}
+			uri := "file://" + filepath.ToSlash(source)
+			if name != "local" {
+				uri = "mlflow-artifacts:/1/run1/artifacts/model"
+			}
+			json.NewEncoder(w).Encode(map[string]string{"artifact_uri": uri})
+		case r.URL.Path == "/api/2.0/mlflow-artifacts/artifacts" && r.Method == "GET":
+			switch r.URL.Query().Get("path") {
+			case "1/run1/artifacts/model":
+				json.NewEncoder(w).Encode(map[string][]FileInfo{"files": listing})
+			case "1/run1/artifacts/model/data":
+				json.NewEncoder(w).Encode(map[string][]FileInfo{"files": {{Path: "model.pkl"}}})
+			}
+		case strings.HasPrefix(r.URL.Path, "/api/2.0/mlflow-artifacts/artifacts/1/run1/artifacts/model/"):
+			w.Write([]byte("proxied " + path.Base(r.URL.Path)))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	for name, expected := range map[string]map[string]string{
+		"local":   {"MLmodel": "flavors", "data/model.pkl": "weights"},
+		"proxied": {"MLmodel": "proxied MLmodel", "data/model.pkl": "proxied model.pkl"},
+	} {
+		dir := t.TempDir()
+		if err := client.DownloadModelVersion(context.Background(), name, "1", dir); err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range expected {
+			if b, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file))); string(b) != content {
+				t.Errorf("%s: expected %s to hold %q, got %q", name, file, content, b)
+			}
+		}
+	}
+
+	listing = []FileInfo{{Path: "MLmodel"}, {Path: "../escaped"}}
+	parent := t.TempDir()
+	err := client.DownloadModelVersion(context.Background(), "proxied", "1", filepath.Join(parent, "model"))
+	if err == nil || !strings.Contains(err.Error(), "artifact path") {
+		t.Errorf("expected the listing to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escaped")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing outside of the download directory, got %v", err)
+	}
+}
+
+func TestCopyModelVersion(t *testing.T) {
+	var models []string
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/model-versions/get":
+			json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: "staging-model", Version: "3", RunId: "run1", RunLink: "http://runs/run1", Description: "candidate", Tags: []ModelVersionTag{{Key: "validated", Value: "true"}}}})
+		case "/api/2.0/mlflow/registered-models/get":
+			name := r.URL.Query().Get("name")
+			for _, model := range models {
+				if model == name {
+					json.NewEncoder(w).Encode(ResponseRegisteredModel{RegisteredModel: RegisteredModel{Name: name}})
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "no model"}`))
+		case "/api/2.0/mlflow/registered-models/create":
+			var request map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&request)
+			models = append(models, request["name"].(string))
+			json.NewEncoder(w).Encode(ResponseRegisteredModel{RegisteredModel: RegisteredModel{Name: request["name"].(string)}})
+		case "/api/2.0/mlflow/model-versions/create":
+			created = nil
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(ResponseModelVersion{ModelVersion: ModelVersion{Name: created["name"].(string), Version: "1", Source: created["source"].(string), RunId: "run1"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	for i := 0; i < 2; i++ {
+		copied, err := client.CopyModelVersion(context.Background(), "staging-model", "3", "prod-model")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(models) != 1 || models[0] != "prod-model" {
+			t.Errorf("expected the destination to be created once, got %v", models)
+		}
+		if created["name"] != "prod-model" || created["source"] != "models:/staging-model/3" || created["run_id"] != "run1" || created["run_link"] != "http://runs/run1" || created["description"] != "candidate" {
+			t.Errorf("unexpected create request %v", created)
+		}
+		tags, _ := created["tags"].([]interface{})
+		if len(tags) != 1 || tags[0].(map[string]interface{})["key"] != "validated" || tags[0].(map[string]interface{})["value"] != "true" {
+			t.Errorf("unexpected tags %v", created["tags"])
+		}
+		if copied.Name != "prod-model" || copied.Source != "models:/staging-model/3" {
+			t.Errorf("unexpected copy %+v", copied)
+		}
+	}
+}
+
+func TestRegistryTagRequests(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		call   func() error
+		method string
+		path   string
+		body   map[string]interface{}
+	}{
+		{
+			func() error { return client.SetRegisteredModelTag(ctx, "model", "team", "ml") },
+			"POST", "/api/2.0/mlflow/registered-models/set-tag",
+			map[string]interface{}{"name": "model", "key": "team", "value": "ml"},
+		},
+		{
+			func() error { return client.DeleteRegisteredModelTag(ctx, "model", "team") },
+			"DELETE", "/api/2.0/mlflow/registered-models/delete-tag",
+			map[string]interface{}{"name": "model", "key": "team"},
+		},
+		{
+			func() error { return client.SetModelVersionTag(ctx, "model", "2", "validated", "true") },
+			"POST", "/api/2.0/mlflow/model-versions/set-tag",
+			map[string]interface{}{"name": "model", "version": "2", "key": "validated", "value": "true"},
+		},
+		{
+			func() error { return client.DeleteModelVersionTag(ctx, "model", "2", "validated") },
+			"DELETE", "/api/2.0/mlflow/model-versions/delete-tag",
+			map[string]interface{}{"name": "model", "version": "2", "key": "validated"},
+		},
+		{
+			func() error { return client.SetRegisteredModelAlias(ctx, "model", "champion", "2") },
+			"POST", "/api/2.0/mlflow/registered-models/alias",
+			map[string]interface{}{"name": "model", "alias": "champion", "version": "2"},
+		},
+		{
+			func() error { return client.DeleteRegisteredModelAlias(ctx, "model", "champion") },
+			"DELETE", "/api/2.0/mlflow/registered-models/alias",
+			map[string]interface{}{"name": "model", "alias": "champion"},
+		},
+	}
+	for _, test := range tests {
+		if err := test.call(); err != nil {
+			t.Fatal(err)
+		}
+		if method != test.method || path != test.path || !reflect.DeepEqual(body, test.body) {
+			t.Errorf("expected %s %s %v, got %s %s %v", test.method, test.path, test.body, method, path, body)
+		}
+	}
+}
+
+func TestRegisteredModelRequests(t *testing.T) {
+	var method, path, query string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"registered_model": {"name": "model", "description": "cnn"}}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		call   func() (*RegisteredModel, error)
+		method string
+		path   string
+		query  string
+		body   map[string]interface{}
+	}{
+		{
+			func() (*RegisteredModel, error) { return client.CreateRegisteredModel(ctx, "model", "cnn") },
+			"POST", "/api/2.0/mlflow/registered-models/create", "",
+			map[string]interface{}{"name": "model", "description": "cnn"},
+		},
+		{
+			func() (*RegisteredModel, error) { return client.CreateRegisteredModel(ctx, "model", "") },
+			"POST", "/api/2.0/mlflow/registered-models/create", "",
+			map[string]interface{}{"name": "model"},
+		},
+		{
+			func() (*RegisteredModel, error) { return client.GetRegisteredModel(ctx, "model") },
+			"GET", "/api/2.0/mlflow/registered-models/get", "name=model",
+			nil,
+		},
+		{
+			func() (*RegisteredModel, error) { return client.UpdateRegisteredModel(ctx, "model", "cnn") },
+			"PATCH", "/api/2.0/mlflow/registered-models/update", "",
+			map[string]interface{}{"name": "model", "description": "cnn"},
+		},
+		{
+			func() (*RegisteredModel, error) { return client.RenameRegisteredModel(ctx, "old", "model") },
+			"POST", "/api/2.0/mlflow/registered-models/rename", "",
+			map[string]interface{}{"name": "old", "new_name": "model"},
+		},
+	}
+	for _, test := range tests {
+		model, err := test.call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method != test.method || path != test.path || query != test.query || !reflect.DeepEqual(body, test.body) {
+			t.Errorf("expected %s %s?%s %v, got %s %s?%s %v", test.method, test.path, test.query, test.body, method, path, query, body)
+		}
+		if model.Name != "model" || model.Description != "cnn" {
+			t.Errorf("unexpected model %+v", model)
+		}
+	}
+	if err := client.DeleteRegisteredModel(ctx, "model"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "DELETE" || path != "/api/2.0/mlflow/registered-models/delete" || !reflect.DeepEqual(body, map[string]interface{}{"name": "model"}) {
+		t.Errorf("unexpected delete request %s %s %v", method, path, body)
+	}
+}
+
+func TestTransitionModelVersionStage(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"model_version": {"name": "model", "version": "2", "current_stage": "Production"}}`))
+	}))
+	defer server.Close()
+
+	version, err := New(server.URL).TransitionModelVersionStage(context.Background(), "model", "2", StageProduction, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"name": "model", "version": "2", "stage": "Production", "archive_existing_versions": true}
+	if method != "POST" || path != "/api/2.0/mlflow/model-versions/transition-stage" || !reflect.DeepEqual(body, expected) {
+		t.Errorf("unexpected request %s %s %v", method, path, body)
+	}
+	if version.CurrentStage != StageProduction {
+		t.Errorf("unexpected model version %+v", version)
+	}
+}
+
+func TestRegistrySearchRequests(t *testing.T) {
+	queries := map[string]url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		queries[r.URL.Path] = r.URL.Query()
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/registered-models/search":
+			w.Write([]byte(`{"registered_models": [{"name": "a"}, {"name": "b"}], "next_page_token": "next"}`))
+		case "/api/2.0/mlflow/model-versions/search":
+			w.Write([]byte(`{"model_versions": [{"name": "a", "version": "1"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	models, err := client.SearchRegisteredModels(ctx, "name LIKE 'a%'", 2, []string{"name ASC", "last_updated_timestamp DESC"}, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models.RegisteredModels) != 2 || models.RegisteredModels[1].Name != "b" || models.NextPageToken != "next" {
+		t.Errorf("unexpected response %+v", models)
+	}
+	expected := url.Values{"filter": {"name LIKE 'a%'"}, "max_results": {"2"}, "order_by": {"name ASC", "last_updated_timestamp DESC"}, "page_token": {"token"}}
+	if query := queries["/api/2.0/mlflow/registered-models/search"]; !reflect.DeepEqual(query, expected) {
+		t.Errorf("unexpected query %v", query)
+	}
+
+	versions, err := client.SearchModelVersions(ctx, "name = 'a'", 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions.ModelVersions) != 1 || versions.ModelVersions[0].Version != "1" || versions.NextPageToken != "" {
+		t.Errorf("unexpected response %+v", versions)
+	}
+	if query := queries["/api/2.0/mlflow/model-versions/search"]; !reflect.DeepEqual(query, url.Values{"filter": {"name = 'a'"}}) {
+		t.Errorf("unexpected query %v", query)
+	}
+}
+
+func TestGetLatestVersions(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"model_versions": [{"name": "model", "version": "3", "current_stage": "Staging"}, {"name": "model", "version": "2", "current_stage": "Production"}]}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	versions, err := client.GetLatestVersions(ctx, "model", []string{StageStaging, StageProduction})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Version != "3" || versions[1].CurrentStage != StageProduction {
+		t.Errorf("unexpected versions %+v", versions)
+	}
+	expected := map[string]interface{}{"name": "model", "stages": []interface{}{"Staging", "Production"}}
+	if method != "POST" || path != "/api/2.0/mlflow/registered-models/get-latest-versions" || !reflect.DeepEqual(body, expected) {
+		t.Errorf("unexpected request %s %s %v", method, path, body)
+	}
+	if _, err := client.GetLatestVersions(ctx, "model", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(body, map[string]interface{}{"name": "model"}) {
+		t.Errorf("expected no stages in the request, got %v", body)
+	}
+}
@@ -0,0 +1,139 @@
+package mlflow
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Requests are retried
+// on 429 and 503 responses, and, for idempotent methods, on other 5xx
+// responses and network errors.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. It doubles with every
+	// attempt up to MaxBackoff, and a random jitter of up to half of it is
+	// subtracted.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RetryNonIdempotent also retries POST and PATCH requests on network
+	// errors and 5xx responses. Most tracking API calls, including metric
+	// logging, tolerate being applied twice.
+	RetryNonIdempotent bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WithRetry retries transient failures according to policy.
+func WithRetry(policy RetryPolicy) Option {
+	return WithMiddleware(RetryMiddleware(policy))
+}
+
+// RetryMiddleware returns a Middleware that retries transient failures
+// according to policy, honoring the Retry-After header of the response.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			for attempt := 1; ; attempt++ {
+				resp, err := next(req)
+				if attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) {
+					return resp, err
+				}
+				body, rewindErr := rewindBody(req)
+				if rewindErr != nil {
+					return resp, err
+				}
+				wait := policy.backoff(attempt)
+				if resp != nil {
+					if after, ok := retryAfter(resp); ok {
+						wait = after
+					}
+					io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+					resp.Body.Close()
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+				req = req.Clone(withAttempt(req.Context(), attempt+1))
+				req.Body = body
+			}
+		}
+	}
+}
+
+func (policy RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// A streamed body can't be sent again.
+		return false
+	}
+	idempotent := policy.RetryNonIdempotent
+	switch req.Method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		idempotent = true
+	}
+	if err != nil {
+		return idempotent
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode >= 500:
+		return idempotent
+	}
+	return false
+}
+
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	wait := policy.MinBackoff
+	for i := 1; i < attempt && wait < policy.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > policy.MaxBackoff {
+		wait = policy.MaxBackoff
+	}
+	if half := int64(wait / 2); half > 0 {
+		wait -= time.Duration(rand.Int63n(half))
+	}
+	return wait
+}
+
+func rewindBody(req *http.Request) (io.ReadCloser, error) {
+	if req.GetBody == nil {
+		return req.Body, nil
+	}
+	return req.GetBody()
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as
+// an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
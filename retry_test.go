@@ -0,0 +1,64 @@
+package mlflow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	var attempts int
+	var bodies []string
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	client := New(server.URL, WithRetry(policy))
+	ctx := context.Background()
+
+	if err := client.SetTag(ctx, "run1", "key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || bodies[0] != bodies[2] || bodies[2] == "" {
+		t.Errorf("got %d attempts with bodies %v", attempts, bodies)
+	}
+
+	// A POST that may have been applied is not sent again.
+	attempts, status = 0, http.StatusInternalServerError
+	if err := client.SetTag(ctx, "run1", "key", "value"); err == nil || attempts != 1 {
+		t.Errorf("got %d attempts and error %v", attempts, err)
+	}
+	attempts = 0
+	if _, err := client.GetRun(ctx, "run1"); err != nil || attempts != 3 {
+		t.Errorf("got %d attempts and error %v", attempts, err)
+	}
+	attempts = 0
+	client = New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, RetryNonIdempotent: true}))
+	if err := client.SetTag(ctx, "run1", "key", "value"); err != nil || attempts != 3 {
+		t.Errorf("got %d attempts and error %v", attempts, err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		wait := policy.backoff(attempt + 1)
+		if wait > max || wait < max/2 {
+			t.Errorf("attempt %d waited %s", attempt+1, wait)
+		}
+	}
+}
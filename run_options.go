@@ -0,0 +1,67 @@
+package mlflow
+
+import "time"
+
+// RunOption configures a run created with CreateRun or StartRun.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	name        string
+	startTime   time.Time
+	tags        []RunTag
+	parentRunId string
+	contextTags bool
+}
+
+func WithRunName(name string) RunOption {
+	return func(o *runOptions) {
+		o.name = name
+	}
+}
+
+// WithStartTime sets the start time of the run, which defaults to now.
+func WithStartTime(startTime time.Time) RunOption {
+	return func(o *runOptions) {
+		o.startTime = startTime
+	}
+}
+
+// WithTags sets tags on the run when it is created.
+func WithTags(tags map[string]string) RunOption {
+	return func(o *runOptions) {
+		for _, key := range sortedKeys(tags) {
+			o.tags = append(o.tags, RunTag{Key: key, Value: tags[key]})
+		}
+	}
+}
+
+// WithParentRun nests the run under parentRunId.
+func WithParentRun(parentRunId string) RunOption {
+	return func(o *runOptions) {
+		o.parentRunId = parentRunId
+	}
+}
+
+// RunSpec is the run a set of RunOptions describes, for implementations of
+// API other than Client.
+type RunSpec struct {
+	Name      string
+	StartTime time.Time
+	// Tags include the parent run and context tags.
+	Tags []RunTag
+}
+
+func NewRunSpec(opts ...RunOption) RunSpec {
+	var options runOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.startTime.IsZero() {
+		options.startTime = time.Now()
+	}
+	if options.parentRunId != "" {
+		options.tags = append(options.tags, RunTag{Key: TagParentRunId, Value: options.parentRunId})
+	}
+	options.mergeContextTags()
+	return RunSpec{Name: options.name, StartTime: options.startTime, Tags: options.tags}
+}
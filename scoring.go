@@ -0,0 +1,108 @@
+package mlflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ScoringClient calls a model served with `mlflow models serve` or any other
+// MLflow scoring server.
+type ScoringClient struct {
+	// HTTP client used to communicate with the scoring server.
+	Client  *http.Client
+	BaseUrl string
+}
+
+func NewScoringClient(url string) *ScoringClient {
+	return &ScoringClient{
+		Client:  http.DefaultClient,
+		BaseUrl: strings.TrimSuffix(url, "/"),
+	}
+}
+
+type DataframeSplit struct {
+	Columns []string        `json:"columns"`
+	Data    [][]interface{} `json:"data"`
+	Index   []interface{}   `json:"index,omitempty"`
+}
+
+// ScoringRequest is the envelope accepted by /invocations. Exactly one of
+// the input fields should be set.
+type ScoringRequest struct {
+	DataframeSplit   *DataframeSplit          `json:"dataframe_split,omitempty"`
+	DataframeRecords []map[string]interface{} `json:"dataframe_records,omitempty"`
+	Instances        interface{}              `json:"instances,omitempty"`
+	Inputs           interface{}              `json:"inputs,omitempty"`
+	Params           map[string]interface{}   `json:"params,omitempty"`
+}
+
+func (s *ScoringClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseUrl+"/ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return readError(resp)
+	}
+	return nil
+}
+
+// Predict posts request to /invocations and decodes the predictions into
+// predictions, which should be a pointer as for json.Unmarshal.
+func (s *ScoringClient) Predict(ctx context.Context, request ScoringRequest, predictions interface{}) error {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseUrl+"/invocations", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newError(resp, body)
+	}
+	// Older scoring servers return the predictions without an envelope, as a
+	// bare array or object.
+	var raw json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	var response struct {
+		Predictions json.RawMessage `json:"predictions"`
+	}
+	if raw[0] != '{' || json.Unmarshal(raw, &response) != nil || response.Predictions == nil {
+		return json.Unmarshal(raw, predictions)
+	}
+	return json.Unmarshal(response.Predictions, predictions)
+}
+
+func (s *ScoringClient) PredictDataframeSplit(ctx context.Context, columns []string, data [][]interface{}, predictions interface{}) error {
+	return s.Predict(ctx, ScoringRequest{DataframeSplit: &DataframeSplit{Columns: columns, Data: data}}, predictions)
+}
+
+func (s *ScoringClient) PredictInstances(ctx context.Context, instances interface{}, predictions interface{}) error {
+	return s.Predict(ctx, ScoringRequest{Instances: instances}, predictions)
+}
+
+func (s *ScoringClient) PredictInputs(ctx context.Context, inputs interface{}, predictions interface{}) error {
+	return s.Predict(ctx, ScoringRequest{Inputs: inputs}, predictions)
+}
@@ -0,0 +1,80 @@
+package mlflow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScoringClient(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/invocations" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.Write([]byte(`{"predictions": [0.1, 0.9]}`))
+	}))
+	defer server.Close()
+	client := NewScoringClient(server.URL)
+
+	var predictions []float64
+	if err := client.PredictDataframeSplit(context.Background(), []string{"x", "y"}, [][]interface{}{{1, "a"}, {2, "b"}}, &predictions); err != nil {
+		t.Fatal(err)
+	}
+	if received != `{"dataframe_split":{"columns":["x","y"],"data":[[1,"a"],[2,"b"]]}}` {
+		t.Errorf("unexpected payload %s", received)
+	}
+	if len(predictions) != 2 || predictions[1] != 0.9 {
+		t.Errorf("unexpected predictions %v", predictions)
+	}
+	if err := client.PredictInstances(context.Background(), [][]float64{{1, 2}}, &predictions); err != nil {
+		t.Fatal(err)
+	}
+	if received != `{"instances":[[1,2]]}` {
+		t.Errorf("unexpected payload %s", received)
+	}
+	if err := client.PredictInputs(context.Background(), map[string][]float64{"x": {1}}, &predictions); err != nil {
+		t.Fatal(err)
+	}
+	if received != `{"inputs":{"x":[1]}}` {
+		t.Errorf("unexpected payload %s", received)
+	}
+}
+
+func TestScoringClientBareResponse(t *testing.T) {
+	var response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+	client := NewScoringClient(server.URL)
+
+	response = ` [0.1, 0.9]`
+	var predictions []float64
+	if err := client.PredictInstances(context.Background(), [][]float64{{1, 2}}, &predictions); err != nil {
+		t.Fatal(err)
+	}
+	if len(predictions) != 2 || predictions[1] != 0.9 {
+		t.Errorf("unexpected predictions %v", predictions)
+	}
+	response = `[{"label": "cat"}]`
+	var labels []map[string]string
+	if err := client.PredictInstances(context.Background(), [][]float64{{1, 2}}, &labels); err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 1 || labels[0]["label"] != "cat" {
+		t.Errorf("unexpected predictions %v", labels)
+	}
+	response = `{"label": "dog"}`
+	var label map[string]string
+	if err := client.PredictInstances(context.Background(), [][]float64{{1, 2}}, &label); err != nil {
+		t.Fatal(err)
+	}
+	if label["label"] != "dog" {
+		t.Errorf("unexpected predictions %v", label)
+	}
+}
@@ -0,0 +1,414 @@
+package mlflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DataType is the type of a column of a model schema.
+type DataType string
+
+const (
+	Boolean  DataType = "boolean"
+	Integer  DataType = "integer"
+	Long     DataType = "long"
+	Float    DataType = "float"
+	Double   DataType = "double"
+	String   DataType = "string"
+	Binary   DataType = "binary"
+	Datetime DataType = "datetime"
+	// Array columns hold lists of Items, in MLflow 2.10 and later.
+	Array DataType = "array"
+)
+
+// ColSpec is a column of a column-based schema.
+type ColSpec struct {
+	Name     string   `json:"name,omitempty"`
+	Type     DataType `json:"type"`
+	Items    *ColSpec `json:"items,omitempty"`
+	Optional bool     `json:"-"`
+}
+
+type colSpecJSON struct {
+	Name     string   `json:"name,omitempty"`
+	Type     DataType `json:"type"`
+	Items    *ColSpec `json:"items,omitempty"`
+	Required *bool    `json:"required,omitempty"`
+}
+
+func (c ColSpec) MarshalJSON() ([]byte, error) {
+	raw := colSpecJSON{Name: c.Name, Type: c.Type, Items: c.Items}
+	if c.Name != "" {
+		required := !c.Optional
+		raw.Required = &required
+	}
+	return json.Marshal(raw)
+}
+
+func (c *ColSpec) UnmarshalJSON(data []byte) error {
+	var raw colSpecJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = ColSpec{Name: raw.Name, Type: raw.Type, Items: raw.Items, Optional: raw.Required != nil && !*raw.Required}
+	return nil
+}
+
+// TensorSpec is a tensor of a tensor-based schema. Dtype is a numpy dtype
+// such as "float32", and -1 in Shape is a variable dimension.
+type TensorSpec struct {
+	Name  string
+	Dtype string
+	Shape []int
+}
+
+type tensorSpecJSON struct {
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type"`
+	TensorSpec struct {
+		Dtype string `json:"dtype"`
+		Shape []int  `json:"shape"`
+	} `json:"tensor-spec"`
+}
+
+func (s TensorSpec) MarshalJSON() ([]byte, error) {
+	raw := tensorSpecJSON{Name: s.Name, Type: "tensor"}
+	raw.TensorSpec.Dtype, raw.TensorSpec.Shape = s.Dtype, s.Shape
+	return json.Marshal(raw)
+}
+
+func (s *TensorSpec) UnmarshalJSON(data []byte) error {
+	var raw tensorSpecJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = TensorSpec{Name: raw.Name, Dtype: raw.TensorSpec.Dtype, Shape: raw.TensorSpec.Shape}
+	return nil
+}
+
+// Schema is either a list of columns or a list of tensors.
+type Schema struct {
+	Columns []ColSpec
+	Tensors []TensorSpec
+}
+
+func (s Schema) isEmpty() bool {
+	return len(s.Columns) == 0 && len(s.Tensors) == 0
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if len(s.Columns) > 0 && len(s.Tensors) > 0 {
+		return nil, fmt.Errorf("mlflow: schema mixes columns and tensors")
+	}
+	if len(s.Tensors) > 0 {
+		return json.Marshal(s.Tensors)
+	}
+	if s.Columns == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.Columns)
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var specs []struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return err
+	}
+	*s = Schema{}
+	if len(specs) > 0 && specs[0].Type == "tensor" {
+		return json.Unmarshal(data, &s.Tensors)
+	}
+	return json.Unmarshal(data, &s.Columns)
+}
+
+// ParamSpec is an inference parameter of a model, with its default value.
+type ParamSpec struct {
+	Name    string      `json:"name"`
+	Type    DataType    `json:"type"`
+	Default interface{} `json:"default"`
+	Shape   []int       `json:"shape"`
+}
+
+// Signature describes the inputs, outputs and inference params of a model.
+// In MLmodel files and in JSON it is stored as MLflow does, with each part
+// serialized as a JSON string.
+type Signature struct {
+	Inputs  Schema
+	Outputs Schema
+	Params  []ParamSpec
+}
+
+// fields returns the parts of the signature as JSON strings.
+func (s Signature) fields() (map[string]string, error) {
+	fields := map[string]string{}
+	parts := map[string]interface{}{"inputs": s.Inputs}
+	if !s.Outputs.isEmpty() {
+		parts["outputs"] = s.Outputs
+	}
+	if len(s.Params) > 0 {
+		parts["params"] = s.Params
+	}
+	for key, part := range parts {
+		b, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = string(b)
+	}
+	return fields, nil
+}
+
+func (s *Signature) setFields(fields map[string]string) error {
+	*s = Signature{}
+	if v := fields["inputs"]; v != "" {
+		if err := json.Unmarshal([]byte(v), &s.Inputs); err != nil {
+			return err
+		}
+	}
+	if v := fields["outputs"]; v != "" {
+		if err := json.Unmarshal([]byte(v), &s.Outputs); err != nil {
+			return err
+		}
+	}
+	if v := fields["params"]; v != "" {
+		if err := json.Unmarshal([]byte(v), &s.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Signature) MarshalYAML() (interface{}, error) {
+	return s.fields()
+}
+
+func (s *Signature) UnmarshalYAML(node *yaml.Node) error {
+	var fields map[string]string
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	return s.setFields(fields)
+}
+
+func (s Signature) MarshalJSON() ([]byte, error) {
+	fields, err := s.fields()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	return s.setFields(fields)
+}
+
+// InferSignature derives a signature from example inputs and outputs:
+//
+//   - a struct, or a slice of structs, gives a column per exported field,
+//     named by its json tag, optional when it is a pointer;
+//   - a map of slices gives a column per key, of the slices' element type;
+//   - a slice of numbers, possibly nested, gives a tensor whose first
+//     dimension is variable and whose other dimensions are those of the
+//     first element;
+//   - a number, string or other scalar gives a single unnamed column.
+//
+// output may be nil.
+func InferSignature(input interface{}, output interface{}) (*Signature, error) {
+	inputs, err := inferSchema(input)
+	if err != nil {
+		return nil, fmt.Errorf("mlflow: input: %w", err)
+	}
+	signature := &Signature{Inputs: inputs}
+	if output != nil {
+		if signature.Outputs, err = inferSchema(output); err != nil {
+			return nil, fmt.Errorf("mlflow: output: %w", err)
+		}
+	}
+	return signature, nil
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+func inferSchema(example interface{}) (Schema, error) {
+	v := reflect.ValueOf(example)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return Schema{}, fmt.Errorf("no example")
+	}
+	t := v.Type()
+	switch {
+	case t.Kind() == reflect.Struct && t != timeType:
+		return structSchema(t)
+	case t.Kind() == reflect.Map:
+		return mapSchema(v)
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t != bytesType:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct && elem != timeType {
+			return structSchema(elem)
+		}
+		return tensorSchema(v)
+	}
+	dataType, err := columnType(t)
+	if err != nil {
+		return Schema{}, err
+	}
+	return Schema{Columns: []ColSpec{{Type: dataType}}}, nil
+}
+
+func structSchema(t reflect.Type) (Schema, error) {
+	var schema Schema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		col, err := colSpec(name, field.Type)
+		if err != nil {
+			return Schema{}, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		schema.Columns = append(schema.Columns, col)
+	}
+	if len(schema.Columns) == 0 {
+		return Schema{}, fmt.Errorf("%s has no exported field", t)
+	}
+	return schema, nil
+}
+
+func mapSchema(v reflect.Value) (Schema, error) {
+	if v.Type().Key().Kind() != reflect.String {
+		return Schema{}, fmt.Errorf("map keys must be strings")
+	}
+	values := map[string]reflect.Value{}
+	for _, key := range v.MapKeys() {
+		values[key.String()] = v.MapIndex(key)
+	}
+	var schema Schema
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+		for value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if !value.IsValid() {
+			return Schema{}, fmt.Errorf("column %s has no example", key)
+		}
+		t := value.Type()
+		if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t != bytesType {
+			t = t.Elem()
+		}
+		col, err := colSpec(key, t)
+		if err != nil {
+			return Schema{}, fmt.Errorf("column %s: %w", key, err)
+		}
+		schema.Columns = append(schema.Columns, col)
+	}
+	if len(schema.Columns) == 0 {
+		return Schema{}, fmt.Errorf("map has no column")
+	}
+	return schema, nil
+}
+
+func colSpec(name string, t reflect.Type) (ColSpec, error) {
+	col := ColSpec{Name: name}
+	if t.Kind() == reflect.Ptr {
+		col.Optional = true
+		t = t.Elem()
+	}
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t != bytesType {
+		items, err := colSpec("", t.Elem())
+		if err != nil {
+			return ColSpec{}, err
+		}
+		items.Optional = false
+		col.Type, col.Items = Array, &items
+		return col, nil
+	}
+	dataType, err := columnType(t)
+	if err != nil {
+		return ColSpec{}, err
+	}
+	col.Type = dataType
+	return col, nil
+}
+
+func columnType(t reflect.Type) (DataType, error) {
+	if t == timeType {
+		return Datetime, nil
+	}
+	if t == bytesType {
+		return Binary, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return Boolean, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Integer, nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return Long, nil
+	case reflect.Float32:
+		return Float, nil
+	case reflect.Float64:
+		return Double, nil
+	case reflect.String:
+		return String, nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// dtypes are the numpy dtypes of Go numbers.
+var dtypes = map[reflect.Kind]string{
+	reflect.Bool:    "bool",
+	reflect.Int8:    "int8",
+	reflect.Int16:   "int16",
+	reflect.Int32:   "int32",
+	reflect.Int:     "int64",
+	reflect.Int64:   "int64",
+	reflect.Uint8:   "uint8",
+	reflect.Uint16:  "uint16",
+	reflect.Uint32:  "uint32",
+	reflect.Uint64:  "uint64",
+	reflect.Float32: "float32",
+	reflect.Float64: "float64",
+}
+
+func tensorSchema(v reflect.Value) (Schema, error) {
+	shape := []int{-1}
+	t := v.Type().Elem()
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		// Inner dimensions are those of the first element.
+		dim := -1
+		if v.Len() > 0 {
+			v = v.Index(0)
+			dim = v.Len()
+		}
+		shape = append(shape, dim)
+		t = t.Elem()
+	}
+	dtype, ok := dtypes[t.Kind()]
+	if !ok {
+		return Schema{}, fmt.Errorf("unsupported tensor element type %s", t)
+	}
+	return Schema{Tensors: []TensorSpec{{Dtype: dtype, Shape: shape}}}, nil
+}
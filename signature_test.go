@@ -0,0 +1,107 @@
+package mlflow
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type house struct {
+	Rooms   int32     `json:"rooms"`
+	Area    float64   `json:"area"`
+	City    string    `json:"city,omitempty"`
+	Garden  *bool     `json:"garden"`
+	Built   time.Time `json:"built"`
+	Photo   []byte    `json:"photo"`
+	Tags    []string  `json:"tags"`
+	Comment string    `json:"-"`
+	secret  string
+}
+
+func TestInferSignature(t *testing.T) {
+	signature, err := InferSignature([]house{{}}, [][]float32{{1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Signature{
+		Inputs: Schema{Columns: []ColSpec{
+			{Name: "rooms", Type: Integer},
+			{Name: "area", Type: Double},
+			{Name: "city", Type: String},
+			{Name: "garden", Type: Boolean, Optional: true},
+			{Name: "built", Type: Datetime},
+			{Name: "photo", Type: Binary},
+			{Name: "tags", Type: Array, Items: &ColSpec{Type: String}},
+		}},
+		Outputs: Schema{Tensors: []TensorSpec{{Dtype: "float32", Shape: []int{-1, 3}}}},
+	}
+	if !reflect.DeepEqual(signature, expected) {
+		t.Errorf("unexpected signature %+v", signature)
+	}
+
+	signature, err = InferSignature(map[string]interface{}{"b": []int64{1}, "a": []string{"x"}}, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = &Signature{
+		Inputs:  Schema{Columns: []ColSpec{{Name: "a", Type: String}, {Name: "b", Type: Long}}},
+		Outputs: Schema{Columns: []ColSpec{{Type: Double}}},
+	}
+	if !reflect.DeepEqual(signature, expected) {
+		t.Errorf("unexpected signature %+v", signature)
+	}
+
+	for _, input := range []interface{}{nil, struct{ c chan int }{}, []complex64{1}, map[int]int{}} {
+		if _, err := InferSignature(input, nil); err == nil {
+			t.Errorf("expected %T to fail", input)
+		}
+	}
+}
+
+func TestSignatureYAML(t *testing.T) {
+	// An MLmodel signature block written by the Python client.
+	const mlModel = `signature:
+  inputs: '[{"type": "double", "name": "x", "required": true}, {"type": "string", "name": "y", "required": false}]'
+  outputs: '[{"type": "tensor", "tensor-spec": {"dtype": "float32", "shape": [-1, 2]}}]'
+  params: '[{"name": "temperature", "type": "double", "default": 0.5, "shape": null}]'
+`
+	var model struct {
+		Signature *Signature `yaml:"signature"`
+	}
+	if err := yaml.Unmarshal([]byte(mlModel), &model); err != nil {
+		t.Fatal(err)
+	}
+	expected := &Signature{
+		Inputs:  Schema{Columns: []ColSpec{{Name: "x", Type: Double}, {Name: "y", Type: String, Optional: true}}},
+		Outputs: Schema{Tensors: []TensorSpec{{Dtype: "float32", Shape: []int{-1, 2}}}},
+		Params:  []ParamSpec{{Name: "temperature", Type: Double, Default: 0.5}},
+	}
+	if !reflect.DeepEqual(model.Signature, expected) {
+		t.Fatalf("unexpected signature %+v", model.Signature)
+	}
+
+	b, err := yaml.Marshal(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip struct {
+		Signature *Signature `yaml:"signature"`
+	}
+	if err := yaml.Unmarshal(b, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip.Signature, expected) {
+		t.Errorf("unexpected signature after a round trip:\n%s", b)
+	}
+	b, err = json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]string
+	if err := json.Unmarshal(b, &fields); err != nil || fields["outputs"] != `[{"type":"tensor","tensor-spec":{"dtype":"float32","shape":[-1,2]}}]` {
+		t.Errorf("unexpected json %s", b)
+	}
+}
@@ -0,0 +1,212 @@
+package mlflow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	emptyPayloadHash    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	unsignedPayloadHash = "UNSIGNED-PAYLOAD"
+)
+
+type awsCredentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+func awsCredentialsFromEnv() awsCredentials {
+	return awsCredentials{
+		AccessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+func awsRegionFromEnv() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+		return region
+	}
+	return "us-east-1"
+}
+
+// signV4 adds AWS Signature Version 4 headers to req. payloadHash is the hex
+// SHA-256 of the body, or unsignedPayloadHash for streamed S3 uploads.
+func signV4(req *http.Request, payloadHash string, creds awsCredentials, region string, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for key, values := range req.Header {
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, "x-amz-") || key == "content-type" {
+			headers[key] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalPath := awsEscape(req.URL.Path, false)
+	if service != "s3" {
+		canonicalPath = awsEscape(canonicalPath, false)
+	}
+	if canonicalPath == "" {
+		canonicalPath = "/"
+	}
+	req.URL.RawPath = awsEscape(req.URL.Path, false)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSha256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSha256(key, region)
+	key = hmacSha256(key, service)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyId+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func awsCanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key, true)+"="+awsEscape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything but the RFC 3986 unreserved
+// characters, keeping '/' unless encodeSlash is set.
+func awsEscape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return b.String()
+}
+
+// RequestSigner signs a request just before it is sent, after headers and
+// credentials have been added.
+type RequestSigner interface {
+	SignRequest(req *http.Request) error
+}
+
+// SigV4Signer signs requests with AWS Signature Version 4, as required by an
+// API Gateway with IAM authorization in front of the tracking server.
+type SigV4Signer struct {
+	Region          string
+	Service         string
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+
+	now func() time.Time
+}
+
+// NewSigV4Signer returns a signer using the credentials in AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN. An empty region is taken from
+// the environment and an empty service defaults to "execute-api".
+func NewSigV4Signer(region string, service string) *SigV4Signer {
+	if region == "" {
+		region = awsRegionFromEnv()
+	}
+	if service == "" {
+		service = "execute-api"
+	}
+	creds := awsCredentialsFromEnv()
+	return &SigV4Signer{
+		Region:          region,
+		Service:         service,
+		AccessKeyId:     creds.AccessKeyId,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+	}
+}
+
+// SignRequest signs req. Bodies that can't be replayed, such as streamed
+// artifact uploads, are signed as UNSIGNED-PAYLOAD.
+func (s *SigV4Signer) SignRequest(req *http.Request) error {
+	payloadHash := emptyPayloadHash
+	if req.Body != nil && req.Body != http.NoBody {
+		payloadHash = unsignedPayloadHash
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			hash := sha256.New()
+			_, err = io.Copy(hash, body)
+			body.Close()
+			if err != nil {
+				return err
+			}
+			payloadHash = hex.EncodeToString(hash.Sum(nil))
+		}
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	creds := awsCredentials{AccessKeyId: s.AccessKeyId, SecretAccessKey: s.SecretAccessKey, SessionToken: s.SessionToken}
+	signV4(req, payloadHash, creds, s.Region, s.Service, now())
+	return nil
+}
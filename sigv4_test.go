@@ -0,0 +1,43 @@
+package mlflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigV4Signer(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite.
+	signer := &SigV4Signer{
+		Region:          "us-east-1",
+		Service:         "service",
+		AccessKeyId:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	if err := signer.SignRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if req.Header.Get("Authorization") != expected {
+		t.Errorf("unexpected Authorization %s", req.Header.Get("Authorization"))
+	}
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, WithToken("ignored"), WithRequestSigner(signer))
+	if _, err := client.HandlePost(context.Background(), server.URL+"/api/2.0/mlflow/runs/set-tag", map[string]interface{}{"key": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=content-type;host;x-amz-date, ") {
+		t.Errorf("request was not signed: %s", authorization)
+	}
+}
@@ -0,0 +1,222 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+)
+
+// SweepSpec describes the trials of a Sweep: either every combination of
+// the values in Grid, or Trials random draws from the distributions in
+// Random.
+type SweepSpec struct {
+	Grid   map[string][]string
+	Random map[string]Distribution
+	// Trials is the number of random trials.
+	Trials int
+	// Seed seeds the random draws, so that a sweep can be repeated.
+	Seed int64
+	// Parallelism is the number of trials run at once. Defaults to 1.
+	Parallelism int
+	// Metric is the metric the objective value is logged as. Defaults to
+	// "objective".
+	Metric string
+	// Maximize selects the trial with the highest value as best instead of
+	// the lowest.
+	Maximize bool
+}
+
+// Distribution draws a random param value.
+type Distribution func(r *rand.Rand) string
+
+// Choice draws one of values.
+func Choice(values ...string) Distribution {
+	return func(r *rand.Rand) string {
+		return values[r.Intn(len(values))]
+	}
+}
+
+// Uniform draws a float in [min, max).
+func Uniform(min float64, max float64) Distribution {
+	return func(r *rand.Rand) string {
+		return strconv.FormatFloat(min+r.Float64()*(max-min), 'g', -1, 64)
+	}
+}
+
+// LogUniform draws a float in [min, max) whose logarithm is uniformly
+// distributed, as learning rates usually are. min must be positive.
+func LogUniform(min float64, max float64) Distribution {
+	return func(r *rand.Rand) string {
+		return strconv.FormatFloat(math.Exp(math.Log(min)+r.Float64()*(math.Log(max)-math.Log(min))), 'g', -1, 64)
+	}
+}
+
+// IntRange draws an integer in [min, max].
+func IntRange(min int, max int) Distribution {
+	return func(r *rand.Rand) string {
+		return strconv.Itoa(min + r.Intn(max-min+1))
+	}
+}
+
+// Trial is a trial of a Sweep, run as a child run of the sweep.
+type Trial struct {
+	Number int
+	Params map[string]string
+	Run    *ActiveRun
+}
+
+// Objective runs a trial and returns its value. The params of the trial are
+// already logged to its run.
+type Objective func(ctx context.Context, trial *Trial) (float64, error)
+
+// TrialResult is the outcome of a trial. Err is set when the trial failed.
+type TrialResult struct {
+	Number int
+	Params map[string]string
+	RunId  string
+	Value  float64
+	Err    error
+}
+
+// SweepResult is the outcome of a Sweep.
+type SweepResult struct {
+	ParentRunId string
+	// Best is the run of the best successful trial.
+	Best      *Run
+	BestValue float64
+	Trials    []TrialResult
+}
+
+var ErrNoSuccessfulTrial = errors.New("mlflow: no trial of the sweep succeeded")
+
+// Sweep runs the trials of spec with objective in experimentId, each as a
+// child run of a parent run created with opts, and returns the best run.
+// A trial whose objective fails or panics is ended FAILED, and doesn't stop
+// the other trials; Sweep returns ErrNoSuccessfulTrial along with the
+// results when none succeeded. No more trials are started once ctx is done.
+func (p *Client) Sweep(ctx context.Context, experimentId string, spec SweepSpec, objective Objective, opts ...RunOption) (*SweepResult, error) {
+	trials, err := spec.trials()
+	if err != nil {
+		return nil, err
+	}
+	if spec.Metric == "" {
+		spec.Metric = "objective"
+	}
+	if spec.Parallelism <= 0 {
+		spec.Parallelism = 1
+	}
+	result := &SweepResult{Trials: make([]TrialResult, len(trials))}
+	err = p.WithRun(ctx, experimentId, func(parent *ActiveRun) error {
+		result.ParentRunId = parent.Id()
+		numbers := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < spec.Parallelism; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for number := range numbers {
+					result.Trials[number] = p.runTrial(ctx, experimentId, parent.Id(), number, trials[number], spec.Metric, objective)
+				}
+			}()
+		}
+		n := 0
+	dispatch:
+		for ; n < len(trials); n++ {
+			select {
+			case numbers <- n:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		close(numbers)
+		wg.Wait()
+		result.Trials = result.Trials[:n]
+
+		best := -1
+		for i, trial := range result.Trials {
+			if trial.Err != nil {
+				continue
+			}
+			if best < 0 || spec.Maximize && trial.Value > result.BestValue || !spec.Maximize && trial.Value < result.BestValue {
+				best, result.BestValue = i, trial.Value
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if best < 0 {
+			return ErrNoSuccessfulTrial
+		}
+		run, err := p.GetRun(ctx, result.Trials[best].RunId)
+		if err != nil {
+			return err
+		}
+		result.Best = run
+		return parent.LogMetric(ctx, spec.Metric, result.BestValue, 0)
+	}, opts...)
+	return result, err
+}
+
+func (p *Client) runTrial(ctx context.Context, experimentId string, parentRunId string, number int, params map[string]string, metric string, objective Objective) TrialResult {
+	result := TrialResult{Number: number, Params: params}
+	result.Err = p.WithRun(ctx, experimentId, func(r *ActiveRun) (err error) {
+		result.RunId = r.Id()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		if err := r.LogParams(ctx, params); err != nil {
+			return err
+		}
+		value, err := objective(ctx, &Trial{Number: number, Params: params, Run: r})
+		if err != nil {
+			return err
+		}
+		result.Value = value
+		return r.LogMetric(ctx, metric, value, 0)
+	}, WithParentRun(parentRunId), WithRunName(fmt.Sprintf("trial-%d", number)))
+	return result
+}
+
+// trials returns the params of every trial.
+func (s SweepSpec) trials() ([]map[string]string, error) {
+	if len(s.Grid) > 0 && len(s.Random) > 0 {
+		return nil, errors.New("mlflow: a sweep takes either a grid or random distributions")
+	}
+	var trials []map[string]string
+	if len(s.Random) > 0 {
+		r := rand.New(rand.NewSource(s.Seed))
+		keys := sortedKeys(s.Random)
+		for i := 0; i < s.Trials; i++ {
+			params := map[string]string{}
+			for _, key := range keys {
+				params[key] = s.Random[key](r)
+			}
+			trials = append(trials, params)
+		}
+		return trials, nil
+	}
+	if len(s.Grid) == 0 {
+		return nil, errors.New("mlflow: a sweep needs a grid or random distributions")
+	}
+	trials = []map[string]string{{}}
+	for _, key := range sortedKeys(s.Grid) {
+		var next []map[string]string
+		for _, params := range trials {
+			for _, value := range s.Grid[key] {
+				combination := map[string]string{key: value}
+				for k, v := range params {
+					combination[k] = v
+				}
+				next = append(next, combination)
+			}
+		}
+		trials = next
+	}
+	return trials, nil
+}
@@ -0,0 +1,109 @@
+package mlflow_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	mlflow "github.com/neka-nat/go-mlflow.git"
+	"github.com/neka-nat/go-mlflow.git/mlflowtest"
+)
+
+func TestSweepGrid(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	spec := mlflow.SweepSpec{Grid: map[string][]string{"lr": {"0.1", "0.01"}, "layers": {"1", "2", "3"}}, Parallelism: 3, Metric: "loss"}
+	result, err := client.Sweep(ctx, "0", spec, func(ctx context.Context, trial *mlflow.Trial) (float64, error) {
+		if trial.Params["layers"] == "3" {
+			return 0, errors.New("out of memory")
+		}
+		lr, _ := strconv.ParseFloat(trial.Params["lr"], 64)
+		layers, _ := strconv.Atoi(trial.Params["layers"])
+		return lr / float64(layers), nil
+	}, mlflow.WithRunName("sweep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trials) != 6 {
+		t.Fatalf("expected 6 trials, got %+v", result.Trials)
+	}
+	failed := 0
+	for _, trial := range result.Trials {
+		if trial.Err != nil {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Errorf("expected 2 failed trials, got %d", failed)
+	}
+	lr, _ := result.Best.Param("lr")
+	layers, _ := result.Best.Param("layers")
+	if result.BestValue != 0.005 || lr != "0.01" || layers != "2" {
+		t.Errorf("unexpected best trial %v with %+v", result.BestValue, result.Best.Data)
+	}
+	if parent, _ := result.Best.Tag(mlflow.TagParentRunId); parent != result.ParentRunId {
+		t.Errorf("expected the trial to be a child of %s, got %s", result.ParentRunId, parent)
+	}
+	if loss, _ := result.Best.Metric("loss"); loss != 0.005 {
+		t.Errorf("expected the objective to be logged, got %v", loss)
+	}
+	parent, err := client.GetRun(ctx, result.ParentRunId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent.Info.RunName != "sweep" || parent.Info.Status != string(mlflow.Finished) {
+		t.Errorf("unexpected parent run %+v", parent.Info)
+	}
+}
+
+func TestSweepRandom(t *testing.T) {
+	server := mlflowtest.NewServer()
+	defer server.Close()
+	client := mlflow.New(server.URL)
+	ctx := context.Background()
+
+	spec := mlflow.SweepSpec{
+		Random: map[string]mlflow.Distribution{
+			"lr":        mlflow.LogUniform(1e-4, 1e-1),
+			"layers":    mlflow.IntRange(1, 4),
+			"optimizer": mlflow.Choice("adam", "sgd"),
+		},
+		Trials:   5,
+		Seed:     1,
+		Maximize: true,
+	}
+	objective := func(ctx context.Context, trial *mlflow.Trial) (float64, error) {
+		return strconv.ParseFloat(trial.Params["lr"], 64)
+	}
+	first, err := client.Sweep(ctx, "0", spec, objective)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Sweep(ctx, "0", spec, objective)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, trial := range first.Trials {
+		if !reflect.DeepEqual(trial.Params, second.Trials[i].Params) {
+			t.Errorf("expected the same seed to draw the same params, got %v and %v", trial.Params, second.Trials[i].Params)
+		}
+		if lr := trial.Value; lr < 1e-4 || lr >= 1e-1 || lr > first.BestValue {
+			t.Errorf("unexpected value %v, best %v", lr, first.BestValue)
+		}
+		if layers, _ := strconv.Atoi(trial.Params["layers"]); layers < 1 || layers > 4 {
+			t.Errorf("unexpected layers %q", trial.Params["layers"])
+		}
+	}
+
+	_, err = client.Sweep(ctx, "0", spec, func(ctx context.Context, trial *mlflow.Trial) (float64, error) {
+		panic("diverged")
+	})
+	if err != mlflow.ErrNoSuccessfulTrial {
+		t.Errorf("expected no successful trial, got %v", err)
+	}
+}
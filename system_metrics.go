@@ -0,0 +1,228 @@
+package mlflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SystemMetricPrefix is the prefix under which the MLflow UI shows system
+// metrics.
+const SystemMetricPrefix = "system/"
+
+// SystemMetricsLoggerOptions configures a SystemMetricsLogger. Zero values
+// select the defaults.
+type SystemMetricsLoggerOptions struct {
+	// SamplingInterval is how often the system is sampled. Defaults to 10s.
+	SamplingInterval time.Duration
+	// SamplesBeforeLogging is the number of samples averaged into each
+	// logged value. Defaults to 1.
+	SamplesBeforeLogging int
+	// DiskPath is the filesystem whose usage is reported. Defaults to "/".
+	DiskPath string
+	// GPU also samples NVIDIA GPUs through nvidia-smi, when it is installed.
+	GPU bool
+}
+
+// SystemMetricsLogger samples CPU, memory, disk, network and optionally GPU
+// utilization in a background goroutine and logs them to a run under the
+// system/ prefix, with the metric names of MLflow's Python client, such as
+// system/cpu_utilization_percentage. Host metrics are read from /proc, so
+// on other systems than Linux only GPUs are sampled. The error of the last
+// failed logging call is returned by Close.
+type SystemMetricsLogger struct {
+	client *Client
+	runId  string
+	ctx    context.Context
+	opts   SystemMetricsLoggerOptions
+	sample func() map[string]float64
+
+	mu      sync.Mutex
+	err     error
+	closed  bool
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+var ErrSystemMetricsLoggerClosed = errors.New("mlflow: system metrics logger is closed")
+
+// NewSystemMetricsLogger starts a SystemMetricsLogger for runId. Metrics are
+// logged with ctx.
+func (p *Client) NewSystemMetricsLogger(ctx context.Context, runId string, opts SystemMetricsLoggerOptions) *SystemMetricsLogger {
+	if opts.DiskPath == "" {
+		opts.DiskPath = "/"
+	}
+	sampler := newHostSampler(opts.DiskPath)
+	var gpu *gpuSampler
+	if opts.GPU {
+		gpu = &gpuSampler{}
+	}
+	return newSystemMetricsLogger(ctx, p, runId, opts, func() map[string]float64 {
+		values := sampler.sample()
+		if gpu != nil {
+			for key, value := range gpu.sample() {
+				values[key] = value
+			}
+		}
+		return values
+	})
+}
+
+func newSystemMetricsLogger(ctx context.Context, client *Client, runId string, opts SystemMetricsLoggerOptions, sample func() map[string]float64) *SystemMetricsLogger {
+	if opts.SamplingInterval <= 0 {
+		opts.SamplingInterval = 10 * time.Second
+	}
+	if opts.SamplesBeforeLogging <= 0 {
+		opts.SamplesBeforeLogging = 1
+	}
+	l := &SystemMetricsLogger{
+		client:  client,
+		runId:   runId,
+		ctx:     ctx,
+		opts:    opts,
+		sample:  sample,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+func (l *SystemMetricsLogger) run() {
+	defer close(l.stopped)
+	ticker := time.NewTicker(l.opts.SamplingInterval)
+	defer ticker.Stop()
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	samples := 0
+	var step int64
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+		for key, value := range l.sample() {
+			sums[key] += value
+			counts[key]++
+		}
+		samples++
+		if samples < l.opts.SamplesBeforeLogging {
+			continue
+		}
+		timestamp := Millis(time.Now())
+		var metrics []Metric
+		for _, key := range sortedKeys(sums) {
+			metrics = append(metrics, Metric{Key: SystemMetricPrefix + key, Value: sums[key] / float64(counts[key]), Timestamp: timestamp, Step: step})
+		}
+		if len(metrics) > 0 {
+			if err := l.client.LogBatch(l.ctx, l.runId, metrics, nil, nil); err != nil {
+				l.mu.Lock()
+				l.err = err
+				l.mu.Unlock()
+			}
+		}
+		step++
+		sums, counts, samples = map[string]float64{}, map[string]int{}, 0
+	}
+}
+
+// Close stops sampling. Samples not logged yet are dropped.
+func (l *SystemMetricsLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrSystemMetricsLoggerClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+	close(l.done)
+	<-l.stopped
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// hostSampler samples CPU, memory, disk and network usage, reporting
+// network traffic since it was created.
+type hostSampler struct {
+	diskPath string
+
+	cpuIdle, cpuTotal uint64
+	netRx, netTx      uint64
+}
+
+func diskMetrics(used uint64, available uint64) map[string]float64 {
+	values := map[string]float64{
+		"disk_usage_megabytes":     float64(used) / 1e6,
+		"disk_available_megabytes": float64(available) / 1e6,
+	}
+	if used+available > 0 {
+		values["disk_usage_percentage"] = 100 * float64(used) / float64(used+available)
+	}
+	return values
+}
+
+// gpuSampler samples NVIDIA GPUs with nvidia-smi. It stops trying once
+// nvidia-smi fails.
+type gpuSampler struct {
+	failed bool
+}
+
+const mebibyte = 1 << 20
+
+func (g *gpuSampler) sample() map[string]float64 {
+	if g.failed {
+		return nil
+	}
+	out, err := exec.Command("nvidia-smi", "--query-gpu=utilization.gpu,memory.used,memory.total,power.draw,power.limit", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		g.failed = true
+		return nil
+	}
+	return parseNvidiaSmi(out)
+}
+
+// parseNvidiaSmi parses the csv output of nvidia-smi --query-gpu with the
+// fields utilization.gpu, memory.used, memory.total, power.draw and
+// power.limit, one GPU per line. Fields the GPU doesn't report are skipped.
+func parseNvidiaSmi(out []byte) map[string]float64 {
+	values := map[string]float64{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for i := 0; scanner.Scan(); i++ {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 5 {
+			continue
+		}
+		var n [5]float64
+		var ok [5]bool
+		for j, field := range fields {
+			value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			n[j], ok[j] = value, err == nil
+		}
+		prefix := fmt.Sprintf("gpu_%d_", i)
+		if ok[0] {
+			values[prefix+"utilization_percentage"] = n[0]
+		}
+		if ok[1] {
+			values[prefix+"memory_usage_megabytes"] = n[1] * mebibyte / 1e6
+			if ok[2] && n[2] > 0 {
+				values[prefix+"memory_usage_percentage"] = 100 * n[1] / n[2]
+			}
+		}
+		if ok[3] {
+			values[prefix+"power_usage_watts"] = n[3]
+			if ok[4] && n[4] > 0 {
+				values[prefix+"power_usage_percentage"] = 100 * n[3] / n[4]
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package mlflow
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func newHostSampler(diskPath string) *hostSampler {
+	s := &hostSampler{diskPath: diskPath}
+	if f, err := os.Open("/proc/stat"); err == nil {
+		s.cpuIdle, s.cpuTotal, _ = parseProcStat(f)
+		f.Close()
+	}
+	if f, err := os.Open("/proc/net/dev"); err == nil {
+		s.netRx, s.netTx, _ = parseNetDev(f)
+		f.Close()
+	}
+	return s
+}
+
+func (s *hostSampler) sample() map[string]float64 {
+	values := map[string]float64{}
+	if f, err := os.Open("/proc/stat"); err == nil {
+		idle, total, ok := parseProcStat(f)
+		f.Close()
+		if ok && total > s.cpuTotal {
+			values["cpu_utilization_percentage"] = 100 * (1 - float64(idle-s.cpuIdle)/float64(total-s.cpuTotal))
+			s.cpuIdle, s.cpuTotal = idle, total
+		}
+	}
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		total, available, ok := parseMeminfo(f)
+		f.Close()
+		if ok && total > 0 {
+			used := total - available
+			values["system_memory_usage_megabytes"] = float64(used) / 1e6
+			values["system_memory_usage_percentage"] = 100 * float64(used) / float64(total)
+		}
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(s.diskPath, &st); err == nil {
+		size := uint64(st.Bsize)
+		for key, value := range diskMetrics((st.Blocks-st.Bfree)*size, st.Bavail*size) {
+			values[key] = value
+		}
+	}
+	if f, err := os.Open("/proc/net/dev"); err == nil {
+		rx, tx, ok := parseNetDev(f)
+		f.Close()
+		if ok {
+			values["network_receive_megabytes"] = float64(rx-s.netRx) / 1e6
+			values["network_transmit_megabytes"] = float64(tx-s.netTx) / 1e6
+		}
+	}
+	return values
+}
+
+// parseProcStat returns the idle and total jiffies of all CPUs from
+// /proc/stat. Idle includes iowait, and total leaves out guest time, which
+// is already counted as user time.
+func parseProcStat(r io.Reader) (uint64, uint64, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		var idle, total uint64
+		for i, field := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			n, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			total += n
+			if i == 3 || i == 4 {
+				idle += n
+			}
+		}
+		return idle, total, true
+	}
+	return 0, 0, false
+}
+
+// parseMeminfo returns MemTotal and MemAvailable from /proc/meminfo in
+// bytes.
+func parseMeminfo(r io.Reader) (uint64, uint64, bool) {
+	var total, available uint64
+	var found int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		var dst *uint64
+		switch fields[0] {
+		case "MemTotal:":
+			dst = &total
+		case "MemAvailable:":
+			dst = &available
+		default:
+			continue
+		}
+		n, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		// The kernel reports kB, meaning KiB.
+		*dst = n * 1024
+		found++
+	}
+	return total, available, found == 2
+}
+
+// parseNetDev returns the bytes received and transmitted by all interfaces
+// from /proc/net/dev.
+func parseNetDev(r io.Reader) (uint64, uint64, bool) {
+	var rx, tx uint64
+	ok := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		fields := strings.Fields(line[i+1:])
+		if len(fields) < 9 {
+			continue
+		}
+		received, err1 := strconv.ParseUint(fields[0], 10, 64)
+		transmitted, err2 := strconv.ParseUint(fields[8], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		rx += received
+		tx += transmitted
+		ok = true
+	}
+	return rx, tx, ok
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package mlflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProc(t *testing.T) {
+	idle, total, ok := parseProcStat(strings.NewReader("cpu  100 5 50 800 20 3 2 0 10 0\ncpu0 50 2 25 400 10 1 1 0 5 0\n"))
+	if !ok || idle != 820 || total != 980 {
+		t.Errorf("unexpected cpu times idle=%d total=%d ok=%v", idle, total, ok)
+	}
+
+	memTotal, available, ok := parseMeminfo(strings.NewReader("MemTotal:       16000 kB\nMemFree:         1000 kB\nMemAvailable:    4000 kB\n"))
+	if !ok || memTotal != 16000*1024 || available != 4000*1024 {
+		t.Errorf("unexpected memory total=%d available=%d ok=%v", memTotal, available, ok)
+	}
+
+	netDev := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0:    5000      50    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+`
+	rx, tx, ok := parseNetDev(strings.NewReader(netDev))
+	if !ok || rx != 6000 || tx != 4000 {
+		t.Errorf("unexpected network rx=%d tx=%d ok=%v", rx, tx, ok)
+	}
+}
+
+func TestHostSampler(t *testing.T) {
+	values := newHostSampler("/").sample()
+	for _, key := range []string{"system_memory_usage_percentage", "disk_usage_percentage", "network_receive_megabytes"} {
+		value, ok := values[key]
+		if !ok || value < 0 || strings.HasSuffix(key, "percentage") && value > 100 {
+			t.Errorf("unexpected %s: %v (present %v)", key, value, ok)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package mlflow
+
+func newHostSampler(diskPath string) *hostSampler {
+	return &hostSampler{diskPath: diskPath}
+}
+
+// sample reports nothing, since host metrics are only read from /proc.
+func (s *hostSampler) sample() map[string]float64 {
+	return map[string]float64{}
+}
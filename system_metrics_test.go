@@ -0,0 +1,89 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSystemMetricsLogger(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]Metric
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/mlflow/runs/log-batch" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		var batch struct {
+			RunId   string   `json:"run_id"`
+			Metrics []Metric `json:"metrics"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || batch.RunId != "run" {
+			t.Errorf("unexpected batch %+v, %v", batch, err)
+		}
+		mu.Lock()
+		batches = append(batches, batch.Metrics)
+		mu.Unlock()
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	logged := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(batches)
+	}
+	var n float64
+	logger := newSystemMetricsLogger(context.Background(), New(server.URL), "run", SystemMetricsLoggerOptions{SamplingInterval: time.Millisecond, SamplesBeforeLogging: 2}, func() map[string]float64 {
+		n++
+		return map[string]float64{"cpu_utilization_percentage": n}
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for logged() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(); err != ErrSystemMetricsLoggerClosed {
+		t.Errorf("expected a second Close to fail, got %v", err)
+	}
+
+	if len(batches) < 2 {
+		t.Fatalf("expected at least 2 batches, got %+v", batches)
+	}
+	// Every value averages two samples.
+	for i, batch := range batches[:2] {
+		if len(batch) != 1 {
+			t.Errorf("unexpected batch %d: %+v", i, batch)
+			continue
+		}
+		if metric := batch[0]; metric.Key != "system/cpu_utilization_percentage" || metric.Step != int64(i) || metric.Value != float64(4*i+3)/2 {
+			t.Errorf("unexpected metric %d: %+v", i, metric)
+		}
+	}
+}
+
+func TestParseNvidiaSmi(t *testing.T) {
+	values := parseNvidiaSmi([]byte("45, 2048, 8192, 70.5, 141\n0, 0, 16384, [N/A], [N/A]\n"))
+	expected := map[string]float64{
+		"gpu_0_utilization_percentage":  45,
+		"gpu_0_memory_usage_megabytes":  2048 * mebibyte / 1e6,
+		"gpu_0_memory_usage_percentage": 25,
+		"gpu_0_power_usage_watts":       70.5,
+		"gpu_0_power_usage_percentage":  50,
+		"gpu_1_utilization_percentage":  0,
+		"gpu_1_memory_usage_megabytes":  0,
+		"gpu_1_memory_usage_percentage": 0,
+	}
+	if len(values) != len(expected) {
+		t.Errorf("unexpected values %v", values)
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, values[key])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mlflow
+
+import "context"
+
+// System tags MLflow gives a meaning to.
+const (
+	TagUser              = "mlflow.user"
+	TagRunName           = "mlflow.runName"
+	TagParentRunId       = "mlflow.parentRunId"
+	TagNote              = "mlflow.note.content"
+	TagSourceName        = "mlflow.source.name"
+	TagSourceType        = "mlflow.source.type"
+	TagSourceGitCommit   = "mlflow.source.git.commit"
+	TagSourceGitBranch   = "mlflow.source.git.branch"
+	TagSourceGitRepoUrl  = "mlflow.source.git.repoURL"
+	TagProjectEntryPoint = "mlflow.project.entryPoint"
+	TagDockerImageName   = "mlflow.docker.image.name"
+	TagLoggedModels      = "mlflow.log-model.history"
+	TagLoggedArtifacts   = "mlflow.loggedArtifacts"
+)
+
+// SourceType is the value of the TagSourceType tag.
+type SourceType string
+
+const (
+	SourceTypeNotebook SourceType = "NOTEBOOK"
+	SourceTypeJob      SourceType = "JOB"
+	SourceTypeProject  SourceType = "PROJECT"
+	SourceTypeLocal    SourceType = "LOCAL"
+	SourceTypeUnknown  SourceType = "UNKNOWN"
+)
+
+// SetRunNote sets the markdown description shown for the run in the UI.
+func (p *Client) SetRunNote(ctx context.Context, runId string, note string) error {
+	return p.SetTag(ctx, runId, TagNote, note)
+}
+
+// SetExperimentNote sets the markdown description shown for the experiment
+// in the UI.
+func (p *Client) SetExperimentNote(ctx context.Context, experimentId string, note string) error {
+	return p.SetExperimentTag(ctx, experimentId, TagNote, note)
+}
+
+// SetRunSource records the program that produced the run.
+func (p *Client) SetRunSource(ctx context.Context, runId string, name string, sourceType SourceType) error {
+	return p.LogBatch(ctx, runId, nil, nil, []RunTag{{Key: TagSourceName, Value: name}, {Key: TagSourceType, Value: string(sourceType)}})
+}
+
+// SetRunUser records the user who produced the run.
+func (p *Client) SetRunUser(ctx context.Context, runId string, user string) error {
+	return p.SetTag(ctx, runId, TagUser, user)
+}
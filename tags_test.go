@@ -0,0 +1,60 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSystemTagSetters(t *testing.T) {
+	tags := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Key   string   `json:"key"`
+			Value string   `json:"value"`
+			Tags  []RunTag `json:"tags"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/runs/set-tag":
+			tags["run "+request.Key] = request.Value
+		case "/api/2.0/mlflow/experiments/set-experiment-tag":
+			tags["experiment "+request.Key] = request.Value
+		case "/api/2.0/mlflow/runs/log-batch":
+			for _, tag := range request.Tags {
+				tags["run "+tag.Key] = tag.Value
+			}
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	if err := client.SetRunNote(ctx, "run1", "# Baseline"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetExperimentNote(ctx, "1", "NLP models"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetRunSource(ctx, "run1", "train.go", SourceTypeLocal); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetRunUser(ctx, "run1", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"run mlflow.note.content":        "# Baseline",
+		"experiment mlflow.note.content": "NLP models",
+		"run mlflow.source.name":         "train.go",
+		"run mlflow.source.type":         "LOCAL",
+		"run mlflow.user":                "alice",
+	}
+	for key, value := range expected {
+		if tags[key] != value {
+			t.Errorf("%s: got %q, want %q", key, tags[key], value)
+		}
+	}
+}
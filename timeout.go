@@ -0,0 +1,45 @@
+package mlflow
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// WithTimeout bounds every API call, including retries and reading the
+// response, to timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *Client) {
+		p.timeout = timeout
+	}
+}
+
+// WithArtifactTimeout bounds every artifact upload and download through the
+// tracking server to timeout, instead of the WithTimeout default. Zero leaves
+// artifact transfers without a timeout.
+func WithArtifactTimeout(timeout time.Duration) Option {
+	return func(p *Client) {
+		p.artifactTimeout = timeout
+	}
+}
+
+type timeoutKey struct{}
+
+// ContextWithTimeout overrides the client's timeouts for calls made with the
+// returned context. Unlike context.WithTimeout the clock starts with each
+// call rather than now, so one context can be reused for many calls.
+func ContextWithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// cancelBody releases the context of a request once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,46 @@
+package mlflow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, WithTimeout(20*time.Millisecond))
+	ctx := context.Background()
+
+	if err := client.SetTag(ctx, "run1", "key", "value"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if err := client.SetTag(ContextWithTimeout(ctx, time.Second), "run1", "key", "value"); err != nil {
+		t.Error(err)
+	}
+
+	// Artifact transfers have their own timeout, unlimited by default.
+	body, err := client.OpenArtifact(ctx, "run1", "model.pkl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(body); err != nil || string(b) != "{}" {
+		t.Errorf("got %q, %v", b, err)
+	}
+	body.Close()
+	client = New(server.URL, WithArtifactTimeout(20*time.Millisecond))
+	if _, err := client.OpenArtifact(ctx, "run1", "model.pkl"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+}
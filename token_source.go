@@ -0,0 +1,54 @@
+package mlflow
+
+import (
+	"sync"
+	"time"
+)
+
+// Token is a bearer token with an optional expiry. A zero Expiry means the
+// token does not expire.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	Expiry      time.Time
+}
+
+// TokenSource supplies tokens for WithTokenSource. Sources of
+// golang.org/x/oauth2 can be adapted with the mlflowoauth2 package.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// tokenExpiryDelta refreshes tokens a little before they expire so that
+// they are still valid when they reach the server.
+const tokenExpiryDelta = 10 * time.Second
+
+// reuseTokenSource returns the last token until it is about to expire.
+type reuseTokenSource struct {
+	mu     sync.Mutex
+	source TokenSource
+	token  *Token
+}
+
+func (s *reuseTokenSource) Token() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && (s.token.Expiry.IsZero() || time.Until(s.token.Expiry) > tokenExpiryDelta) {
+		return s.token, nil
+	}
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// authorization returns the Authorization header for token.
+func (t *Token) authorization() string {
+	tokenType := t.TokenType
+	if tokenType == "" || tokenType == "bearer" {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
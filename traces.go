@@ -0,0 +1,483 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type TraceStatus string
+
+const (
+	TraceOk          TraceStatus = "OK"
+	TraceError       TraceStatus = "ERROR"
+	TraceInProgress  TraceStatus = "IN_PROGRESS"
+	TraceUnspecified TraceStatus = "TRACE_STATUS_UNSPECIFIED"
+)
+
+type SpanStatusCode string
+
+const (
+	SpanOk    SpanStatusCode = "OK"
+	SpanError SpanStatusCode = "ERROR"
+	SpanUnset SpanStatusCode = "UNSET"
+)
+
+type SpanType string
+
+const (
+	SpanTypeLlm       SpanType = "LLM"
+	SpanTypeChain     SpanType = "CHAIN"
+	SpanTypeAgent     SpanType = "AGENT"
+	SpanTypeTool      SpanType = "TOOL"
+	SpanTypeChatModel SpanType = "CHAT_MODEL"
+	SpanTypeRetriever SpanType = "RETRIEVER"
+	SpanTypeParser    SpanType = "PARSER"
+	SpanTypeEmbedding SpanType = "EMBEDDING"
+	SpanTypeReranker  SpanType = "RERANKER"
+	SpanTypeUnknown   SpanType = "UNKNOWN"
+)
+
+type TraceInfo struct {
+	RequestId       string                 `json:"request_id"`
+	ExperimentId    string                 `json:"experiment_id"`
+	TimestampMs     int64                  `json:"timestamp_ms"`
+	ExecutionTimeMs int64                  `json:"execution_time_ms,omitempty"`
+	Status          TraceStatus            `json:"status"`
+	RequestMetadata []TraceRequestMetadata `json:"request_metadata,omitempty"`
+	Tags            []TraceTag             `json:"tags,omitempty"`
+}
+
+type TraceRequestMetadata struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type TraceTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ResponseTraceInfo struct {
+	TraceInfo TraceInfo `json:"trace_info"`
+}
+
+// Span is a span in the JSON layout MLflow stores in a trace's traces.json
+// artifact. Times are in nanoseconds and attribute values are JSON encoded.
+type Span struct {
+	Name          string            `json:"name"`
+	Context       SpanContext       `json:"context"`
+	ParentId      string            `json:"parent_id,omitempty"`
+	StartTime     int64             `json:"start_time"`
+	EndTime       int64             `json:"end_time"`
+	StatusCode    SpanStatusCode    `json:"status_code"`
+	StatusMessage string            `json:"status_message"`
+	Attributes    map[string]string `json:"attributes"`
+	Events        []SpanEvent       `json:"events"`
+}
+
+type SpanContext struct {
+	SpanId  string `json:"span_id"`
+	TraceId string `json:"trace_id"`
+}
+
+type SpanEvent struct {
+	Name       string            `json:"name"`
+	Timestamp  int64             `json:"timestamp"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type TraceData struct {
+	Spans []Span `json:"spans"`
+}
+
+// ActiveTrace collects the spans of a trace started with StartTrace until it
+// is finished with EndTrace.
+type ActiveTrace struct {
+	Info TraceInfo
+
+	mu        sync.Mutex
+	traceId   string
+	startTime time.Time
+	spans     []*Span
+}
+
+const maxTraceMetadataLength = 250
+
+func randomId(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return "0x" + hex.EncodeToString(b)
+}
+
+func jsonAttribute(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return string(b)
+}
+
+// truncateMetadata cuts s to maxTraceMetadataLength bytes, ending with "..."
+// when it is longer, without splitting a UTF-8 character.
+func truncateMetadata(s string) string {
+	if len(s) <= maxTraceMetadataLength {
+		return s
+	}
+	n := maxTraceMetadataLength - 3
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+func (p *Client) StartTrace(ctx context.Context, experimentId string, tags map[string]string) (*ActiveTrace, error) {
+	if err := p.requireTraces(); err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/traces"
+	now := time.Now()
+	request := map[string]interface{}{
+		"experiment_id":    experimentId,
+		"timestamp_ms":     now.UnixMilli(),
+		"request_metadata": []TraceRequestMetadata{{Key: "mlflow.trace_schema.version", Value: "2"}},
+	}
+	if len(tags) > 0 {
+		traceTags := make([]TraceTag, 0, len(tags))
+		for key, value := range tags {
+			traceTags = append(traceTags, TraceTag{Key: key, Value: value})
+		}
+		request["tags"] = traceTags
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseTraceInfo
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &ActiveTrace{Info: response.TraceInfo, traceId: randomId(16), startTime: now}, nil
+}
+
+// StartSpan opens a span in the trace. Pass a nil parent for the root span.
+func (t *ActiveTrace) StartSpan(name string, spanType SpanType, parent *Span, inputs interface{}) *Span {
+	span := &Span{
+		Name:       name,
+		Context:    SpanContext{SpanId: randomId(8), TraceId: t.traceId},
+		StartTime:  time.Now().UnixNano(),
+		StatusCode: SpanUnset,
+		Attributes: map[string]string{
+			"mlflow.traceRequestId": jsonAttribute(t.Info.RequestId),
+			"mlflow.spanType":       jsonAttribute(spanType),
+		},
+		Events: []SpanEvent{},
+	}
+	if parent != nil {
+		span.ParentId = parent.Context.SpanId
+	}
+	if inputs != nil {
+		span.Attributes["mlflow.spanInputs"] = jsonAttribute(inputs)
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return span
+}
+
+// EndSpan closes span, recording its outputs, any extra attributes and the
+// final status.
+func (t *ActiveTrace) EndSpan(span *Span, outputs interface{}, attributes map[string]interface{}, status SpanStatusCode, statusMessage string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span.EndTime = time.Now().UnixNano()
+	span.StatusCode = status
+	span.StatusMessage = statusMessage
+	if outputs != nil {
+		span.Attributes["mlflow.spanOutputs"] = jsonAttribute(outputs)
+	}
+	for key, value := range attributes {
+		span.Attributes[key] = jsonAttribute(value)
+	}
+}
+
+func (t *ActiveTrace) root() *Span {
+	for _, span := range t.spans {
+		if span.ParentId == "" {
+			return span
+		}
+	}
+	return nil
+}
+
+// EndTrace finishes the trace on the tracking server and uploads its spans
+// to the trace's artifact location. Spans that are still open are ended with
+// the trace.
+func (p *Client) EndTrace(ctx context.Context, trace *ActiveTrace, status TraceStatus) (*TraceInfo, error) {
+	trace.mu.Lock()
+	now := time.Now()
+	spans := make([]Span, len(trace.spans))
+	for i, span := range trace.spans {
+		if span.EndTime == 0 {
+			span.EndTime = now.UnixNano()
+		}
+		spans[i] = *span
+	}
+	metadata := []TraceRequestMetadata{}
+	var tags []TraceTag
+	if root := trace.root(); root != nil {
+		metadata = append(metadata,
+			TraceRequestMetadata{Key: "mlflow.traceInputs", Value: truncateMetadata(root.Attributes["mlflow.spanInputs"])},
+			TraceRequestMetadata{Key: "mlflow.traceOutputs", Value: truncateMetadata(root.Attributes["mlflow.spanOutputs"])},
+		)
+		tags = append(tags, TraceTag{Key: "mlflow.traceName", Value: root.Name})
+	}
+	trace.mu.Unlock()
+
+	artifactLocation := ""
+	for _, tag := range trace.Info.Tags {
+		if tag.Key == "mlflow.artifactLocation" {
+			artifactLocation = tag.Value
+		}
+	}
+	if artifactLocation != "" {
+		repo, err := p.ArtifactRepository(artifactLocation)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(TraceData{Spans: spans})
+		if err != nil {
+			return nil, err
+		}
+		if err := uploadBytes(ctx, repo, data, "traces.json"); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/traces/" + url.PathEscape(trace.Info.RequestId)
+	request := map[string]interface{}{
+		"request_id":       trace.Info.RequestId,
+		"timestamp_ms":     now.UnixMilli(),
+		"status":           status,
+		"request_metadata": metadata,
+	}
+	if len(tags) > 0 {
+		request["tags"] = tags
+	}
+	body, err := p.HandlePatch(ctx, endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseTraceInfo
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.TraceInfo.ExecutionTimeMs == 0 {
+		// Servers that do not report it get the time measured here.
+		response.TraceInfo.ExecutionTimeMs = now.Sub(trace.startTime).Milliseconds()
+	}
+	trace.Info = response.TraceInfo
+	return &response.TraceInfo, nil
+}
+
+type Trace struct {
+	Info TraceInfo `json:"info"`
+	Data TraceData `json:"data"`
+}
+
+type ResponseSearchTraces struct {
+	Traces        []TraceInfo `json:"traces"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+}
+
+func (p *Client) SearchTraces(ctx context.Context, experimentIds []string, filter string, maxResults int, orderBy []string, pageToken string) (*ResponseSearchTraces, error) {
+	if err := p.requireTraces(); err != nil {
+		return nil, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/traces"
+	params := searchParams(filter, maxResults, orderBy, pageToken)
+	params["experiment_ids"] = experimentIds
+	body, err := p.HandleGet(ctx, url, params)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseSearchTraces
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Client) GetTraceInfo(ctx context.Context, requestId string) (*TraceInfo, error) {
+	if err := p.requireTraces(); err != nil {
+		return nil, err
+	}
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/traces/" + url.PathEscape(requestId) + "/info"
+	body, err := p.HandleGet(ctx, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseTraceInfo
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.TraceInfo, nil
+}
+
+// GetTrace fetches a trace's info together with its spans.
+func (p *Client) GetTrace(ctx context.Context, requestId string) (*Trace, error) {
+	info, err := p.GetTraceInfo(ctx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.getStream(ctx, p.ajaxApiUrl("get-trace-artifact"), map[string]interface{}{"request_id": requestId})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	trace := Trace{Info: *info}
+	if err := json.NewDecoder(body).Decode(&trace.Data); err != nil {
+		return nil, err
+	}
+	return &trace, nil
+}
+
+// DeleteTraces deletes the traces of an experiment, either those listed in
+// requestIds or up to maxTraces traces older than maxTimestampMillis, and
+// returns how many were deleted.
+func (p *Client) DeleteTraces(ctx context.Context, experimentId string, maxTimestampMillis int64, maxTraces int, requestIds []string) (int, error) {
+	if err := p.requireTraces(); err != nil {
+		return 0, err
+	}
+	url := p.BaseUrl + "/api/2.0/mlflow/traces/delete-traces"
+	request := map[string]interface{}{"experiment_id": experimentId}
+	if len(requestIds) > 0 {
+		request["request_ids"] = requestIds
+	} else {
+		request["max_timestamp_millis"] = maxTimestampMillis
+		if maxTraces > 0 {
+			request["max_traces"] = maxTraces
+		}
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return 0, err
+	}
+	var response struct {
+		TracesDeleted int `json:"traces_deleted"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return 0, err
+	}
+	return response.TracesDeleted, nil
+}
+
+func (p *Client) SetTraceTag(ctx context.Context, requestId string, key string, value string) error {
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/traces/" + url.PathEscape(requestId) + "/tags"
+	_, err := p.HandlePatch(ctx, endpoint, map[string]interface{}{"key": key, "value": value})
+	return err
+}
+
+func (p *Client) DeleteTraceTag(ctx context.Context, requestId string, key string) error {
+	endpoint := p.BaseUrl + "/api/2.0/mlflow/traces/" + url.PathEscape(requestId) + "/tags?key=" + url.QueryEscape(key)
+	_, err := p.HandleDelete(ctx, endpoint, map[string]interface{}{"key": key})
+	return err
+}
+
+type AssessmentSourceType string
+
+const (
+	SourceHuman    AssessmentSourceType = "HUMAN"
+	SourceLlmJudge AssessmentSourceType = "LLM_JUDGE"
+	SourceCode     AssessmentSourceType = "CODE"
+)
+
+type AssessmentSource struct {
+	SourceType AssessmentSourceType `json:"source_type"`
+	SourceId   string               `json:"source_id"`
+}
+
+// Assessment is a judgment attached to a trace, either feedback on the
+// trace's quality or the expected outcome for its inputs.
+type Assessment struct {
+	AssessmentId   string            `json:"assessment_id,omitempty"`
+	AssessmentName string            `json:"assessment_name"`
+	TraceId        string            `json:"trace_id"`
+	SpanId         string            `json:"span_id,omitempty"`
+	Source         AssessmentSource  `json:"source"`
+	CreateTime     string            `json:"create_time,omitempty"`
+	LastUpdateTime string            `json:"last_update_time,omitempty"`
+	Feedback       *Feedback         `json:"feedback,omitempty"`
+	Expectation    *Expectation      `json:"expectation,omitempty"`
+	Rationale      string            `json:"rationale,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+type Feedback struct {
+	Value interface{}      `json:"value"`
+	Error *AssessmentError `json:"error,omitempty"`
+}
+
+type AssessmentError struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+type Expectation struct {
+	Value interface{} `json:"value"`
+}
+
+type ResponseAssessment struct {
+	Assessment Assessment `json:"assessment"`
+}
+
+func decodeAssessment(body []byte, err error) (*Assessment, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseAssessment
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Assessment, nil
+}
+
+func (p *Client) assessmentsUrl(traceId string) string {
+	return p.BaseUrl + "/api/3.0/mlflow/traces/" + url.PathEscape(traceId) + "/assessments"
+}
+
+func (p *Client) LogAssessment(ctx context.Context, traceId string, assessment Assessment) (*Assessment, error) {
+	assessment.TraceId = traceId
+	return decodeAssessment(p.HandlePost(ctx, p.assessmentsUrl(traceId), map[string]interface{}{"assessment": assessment}))
+}
+
+// LogFeedback records a judgment such as pass/fail or a score on a trace.
+func (p *Client) LogFeedback(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource, rationale string) (*Assessment, error) {
+	return p.LogAssessment(ctx, traceId, Assessment{AssessmentName: name, Source: source, Feedback: &Feedback{Value: value}, Rationale: rationale})
+}
+
+// LogExpectation records the ground truth expected for a trace's inputs.
+func (p *Client) LogExpectation(ctx context.Context, traceId string, name string, value interface{}, source AssessmentSource) (*Assessment, error) {
+	return p.LogAssessment(ctx, traceId, Assessment{AssessmentName: name, Source: source, Expectation: &Expectation{Value: value}})
+}
+
+func (p *Client) GetAssessment(ctx context.Context, traceId string, assessmentId string) (*Assessment, error) {
+	return decodeAssessment(p.HandleGet(ctx, p.assessmentsUrl(traceId)+"/"+url.PathEscape(assessmentId), nil))
+}
+
+func (p *Client) DeleteAssessment(ctx context.Context, traceId string, assessmentId string) error {
+	_, err := p.HandleDelete(ctx, p.assessmentsUrl(traceId)+"/"+url.PathEscape(assessmentId), map[string]interface{}{})
+	return err
+}
@@ -0,0 +1,297 @@
+package mlflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestTraceLifecycle(t *testing.T) {
+	root := t.TempDir()
+	var ended map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/2.0/mlflow/traces":
+			json.NewEncoder(w).Encode(ResponseTraceInfo{TraceInfo: TraceInfo{RequestId: "tr-1", ExperimentId: "0", Status: TraceInProgress, Tags: []TraceTag{{Key: "mlflow.artifactLocation", Value: root}}}})
+		case r.Method == "PATCH" && r.URL.Path == "/api/2.0/mlflow/traces/tr-1":
+			json.NewDecoder(r.Body).Decode(&ended)
+			json.NewEncoder(w).Encode(ResponseTraceInfo{TraceInfo: TraceInfo{RequestId: "tr-1", ExperimentId: "0", Status: TraceOk}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	trace, err := client.StartTrace(context.Background(), "0", map[string]string{"team": "nlp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := trace.StartSpan("agent", SpanTypeAgent, nil, map[string]string{"question": "hi"})
+	llm := trace.StartSpan("llm", SpanTypeLlm, agent, []string{"hi"})
+	trace.EndSpan(llm, "hello", map[string]interface{}{"tokens": 3}, SpanOk, "")
+	trace.EndSpan(agent, map[string]string{"answer": "hello"}, nil, SpanOk, "")
+	trace.startTime = trace.startTime.Add(-time.Second)
+	info, err := client.EndTrace(context.Background(), trace, TraceOk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != TraceOk {
+		t.Errorf("Expected status OK, got %s", info.Status)
+	}
+	if info.ExecutionTimeMs < 1000 {
+		t.Errorf("Expected the execution time measured from StartTrace, got %dms", info.ExecutionTimeMs)
+	}
+	if ended["status"] != "OK" {
+		t.Errorf("unexpected end request %v", ended)
+	}
+
+	b, err := os.ReadFile(filepath.Join(root, "traces.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data TraceData
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(data.Spans))
+	}
+	if data.Spans[1].ParentId != data.Spans[0].Context.SpanId || data.Spans[0].Context.TraceId != data.Spans[1].Context.TraceId {
+		t.Errorf("unexpected span hierarchy %+v", data.Spans)
+	}
+	if data.Spans[1].Attributes["mlflow.spanOutputs"] != `"hello"` || data.Spans[1].Attributes["tokens"] != "3" || data.Spans[1].Attributes["mlflow.spanType"] != `"LLM"` {
+		t.Errorf("unexpected attributes %v", data.Spans[1].Attributes)
+	}
+}
+
+func TestSearchTraces(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/2.0/mlflow/traces" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		q := r.URL.Query()
+		queries = append(queries, q)
+		response := ResponseSearchTraces{Traces: []TraceInfo{{RequestId: "tr-1", ExperimentId: "1", Status: TraceOk}}, NextPageToken: "page2"}
+		if q.Get("page_token") == "page2" {
+			response = ResponseSearchTraces{Traces: []TraceInfo{{RequestId: "tr-2", ExperimentId: "2", Status: TraceError}}}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	page, err := client.SearchTraces(ctx, []string{"1", "2"}, "status = 'OK'", 1, []string{"timestamp_ms DESC"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := queries[0]
+	if strings.Join(q["experiment_ids"], ",") != "1,2" || q.Get("filter") != "status = 'OK'" || q.Get("max_results") != "1" || strings.Join(q["order_by"], ",") != "timestamp_ms DESC" || q.Has("page_token") {
+		t.Errorf("unexpected query %v", q)
+	}
+	if len(page.Traces) != 1 || page.Traces[0].RequestId != "tr-1" || page.NextPageToken != "page2" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	page, err = client.SearchTraces(ctx, []string{"1", "2"}, "", 0, nil, page.NextPageToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = queries[1]
+	if q.Get("page_token") != "page2" || q.Has("filter") || q.Has("max_results") || q.Has("order_by") {
+		t.Errorf("unexpected query %v", q)
+	}
+	if len(page.Traces) != 1 || page.Traces[0].RequestId != "tr-2" || page.Traces[0].Status != TraceError || page.NextPageToken != "" {
+		t.Errorf("unexpected last page %+v", page)
+	}
+}
+
+func TestGetTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/2.0/mlflow/traces/tr 1/info":
+			json.NewEncoder(w).Encode(ResponseTraceInfo{TraceInfo: TraceInfo{RequestId: "tr 1", ExperimentId: "1", Status: TraceOk, Tags: []TraceTag{{Key: "team", Value: "nlp"}}}})
+		case r.Method == "GET" && r.URL.Path == "/ajax-api/2.0/mlflow/get-trace-artifact":
+			if r.URL.Query().Get("request_id") != "tr 1" {
+				t.Errorf("unexpected query %v", r.URL.Query())
+			}
+			w.Write([]byte(`{"spans": [{"name": "agent", "context": {"span_id": "s1", "trace_id": "t1"}, "start_time": 1, "end_time": 2}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	info, err := client.GetTraceInfo(context.Background(), "tr 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.RequestId != "tr 1" || info.Status != TraceOk || len(info.Tags) != 1 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	trace, err := client.GetTrace(context.Background(), "tr 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Info.RequestId != "tr 1" || len(trace.Data.Spans) != 1 || trace.Data.Spans[0].Name != "agent" || trace.Data.Spans[0].Context.SpanId != "s1" {
+		t.Errorf("unexpected trace %+v", trace)
+	}
+}
+
+func TestDeleteTraces(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/2.0/mlflow/traces/delete-traces" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		w.Write([]byte(`{"traces_deleted": 2}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	n, err := client.DeleteTraces(context.Background(), "1", 0, 0, []string{"tr-1", "tr-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 deleted traces, got %d", n)
+	}
+	if _, err := client.DeleteTraces(context.Background(), "1", 1700000000000, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	byIds, byAge := requests[0], requests[1]
+	if byIds["experiment_id"] != "1" || fmt.Sprint(byIds["request_ids"]) != "[tr-1 tr-2]" || byIds["max_timestamp_millis"] != nil || byIds["max_traces"] != nil {
+		t.Errorf("unexpected request %v", byIds)
+	}
+	if byAge["experiment_id"] != "1" || byAge["max_timestamp_millis"] != float64(1700000000000) || byAge["max_traces"] != float64(10) || byAge["request_ids"] != nil {
+		t.Errorf("unexpected request %v", byAge)
+	}
+}
+
+func TestTraceTagRequests(t *testing.T) {
+	var method, path, key string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, key = r.Method, r.URL.Path, r.URL.Query().Get("key")
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	if err := client.SetTraceTag(context.Background(), "tr-1", "team", "nlp"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "PATCH" || path != "/api/2.0/mlflow/traces/tr-1/tags" || body["key"] != "team" || body["value"] != "nlp" {
+		t.Errorf("unexpected set request %s %s %v", method, path, body)
+	}
+	if err := client.DeleteTraceTag(context.Background(), "tr-1", "a&b"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "DELETE" || path != "/api/2.0/mlflow/traces/tr-1/tags" || key != "a&b" || body["key"] != "a&b" {
+		t.Errorf("unexpected delete request %s %s %s %v", method, path, key, body)
+	}
+}
+
+func TestAssessmentRequests(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method == "DELETE" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		assessment := Assessment{AssessmentId: "a-1", AssessmentName: "correct", TraceId: "tr-1", Source: AssessmentSource{SourceType: SourceHuman, SourceId: "alice"}, Feedback: &Feedback{Value: true}}
+		json.NewEncoder(w).Encode(ResponseAssessment{Assessment: assessment})
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+	source := AssessmentSource{SourceType: SourceHuman, SourceId: "alice"}
+	sent := func() map[string]interface{} {
+		assessment, _ := body["assessment"].(map[string]interface{})
+		return assessment
+	}
+
+	assessment, err := client.LogFeedback(ctx, "tr-1", "correct", true, source, "matches the docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := sent()
+	if method != "POST" || path != "/api/3.0/mlflow/traces/tr-1/assessments" {
+		t.Errorf("unexpected request %s %s", method, path)
+	}
+	if a["assessment_name"] != "correct" || a["trace_id"] != "tr-1" || a["rationale"] != "matches the docs" || a["expectation"] != nil {
+		t.Errorf("unexpected feedback %v", a)
+	}
+	if a["source"].(map[string]interface{})["source_type"] != "HUMAN" || a["feedback"].(map[string]interface{})["value"] != true {
+		t.Errorf("unexpected feedback %v", a)
+	}
+	if assessment.AssessmentId != "a-1" || assessment.Feedback == nil || assessment.Feedback.Value != true {
+		t.Errorf("unexpected assessment %+v", assessment)
+	}
+
+	if _, err := client.LogExpectation(ctx, "tr-1", "answer", map[string]string{"text": "42"}, source); err != nil {
+		t.Fatal(err)
+	}
+	a = sent()
+	if a["assessment_name"] != "answer" || a["feedback"] != nil || a["expectation"].(map[string]interface{})["value"].(map[string]interface{})["text"] != "42" {
+		t.Errorf("unexpected expectation %v", a)
+	}
+
+	if _, err := client.LogAssessment(ctx, "tr-2", Assessment{AssessmentName: "score", TraceId: "ignored", SpanId: "s1", Source: source, Feedback: &Feedback{Value: 0.5}, Metadata: map[string]string{"judge": "v1"}}); err != nil {
+		t.Fatal(err)
+	}
+	a = sent()
+	if path != "/api/3.0/mlflow/traces/tr-2/assessments" || a["trace_id"] != "tr-2" || a["span_id"] != "s1" || a["metadata"].(map[string]interface{})["judge"] != "v1" {
+		t.Errorf("unexpected assessment request %s %v", path, a)
+	}
+
+	if _, err := client.GetAssessment(ctx, "tr-1", "a-1"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "GET" || path != "/api/3.0/mlflow/traces/tr-1/assessments/a-1" {
+		t.Errorf("unexpected get request %s %s", method, path)
+	}
+	if err := client.DeleteAssessment(ctx, "tr-1", "a-1"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "DELETE" || path != "/api/3.0/mlflow/traces/tr-1/assessments/a-1" {
+		t.Errorf("unexpected delete request %s %s", method, path)
+	}
+}
+
+func TestTruncateMetadata(t *testing.T) {
+	short := strings.Repeat("a", maxTraceMetadataLength)
+	if truncateMetadata(short) != short {
+		t.Error("expected metadata of the maximum length to be kept")
+	}
+	for _, s := range []string{strings.Repeat("a", 300), strings.Repeat("é", 200), "a" + strings.Repeat("日本", 100), strings.Repeat("🙂", 100)} {
+		truncated := truncateMetadata(s)
+		if len(truncated) > maxTraceMetadataLength || !strings.HasSuffix(truncated, "...") || !utf8.ValidString(truncated) {
+			t.Errorf("unexpected truncation %q (%d bytes)", truncated, len(truncated))
+		}
+		if !strings.HasPrefix(s, strings.TrimSuffix(truncated, "...")) || len(truncated) < maxTraceMetadataLength-6 {
+			t.Errorf("expected %q to keep as much of the input as fits", truncated)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type RegistryWebhookEvent string
+
+const (
+	ModelVersionCreated                  RegistryWebhookEvent = "MODEL_VERSION_CREATED"
+	ModelVersionTransitionedStage        RegistryWebhookEvent = "MODEL_VERSION_TRANSITIONED_STAGE"
+	TransitionRequestCreated             RegistryWebhookEvent = "TRANSITION_REQUEST_CREATED"
+	CommentCreated                       RegistryWebhookEvent = "COMMENT_CREATED"
+	RegisteredModelCreated               RegistryWebhookEvent = "REGISTERED_MODEL_CREATED"
+	ModelVersionTagSet                   RegistryWebhookEvent = "MODEL_VERSION_TAG_SET"
+	ModelVersionTransitionedToStaging    RegistryWebhookEvent = "MODEL_VERSION_TRANSITIONED_TO_STAGING"
+	ModelVersionTransitionedToProduction RegistryWebhookEvent = "MODEL_VERSION_TRANSITIONED_TO_PRODUCTION"
+	ModelVersionTransitionedToArchived   RegistryWebhookEvent = "MODEL_VERSION_TRANSITIONED_TO_ARCHIVED"
+	TransitionRequestToStagingCreated    RegistryWebhookEvent = "TRANSITION_REQUEST_TO_STAGING_CREATED"
+	TransitionRequestToProductionCreated RegistryWebhookEvent = "TRANSITION_REQUEST_TO_PRODUCTION_CREATED"
+	TransitionRequestToArchivedCreated   RegistryWebhookEvent = "TRANSITION_REQUEST_TO_ARCHIVED_CREATED"
+)
+
+type RegistryWebhookStatus string
+
+const (
+	WebhookActive   RegistryWebhookStatus = "ACTIVE"
+	WebhookTestMode RegistryWebhookStatus = "TEST_MODE"
+	WebhookDisabled RegistryWebhookStatus = "DISABLED"
+)
+
+type RegistryWebhook struct {
+	Id                   string                 `json:"id,omitempty"`
+	CreationTimestamp    int64                  `json:"creation_timestamp,omitempty"`
+	LastUpdatedTimestamp int64                  `json:"last_updated_timestamp,omitempty"`
+	ModelName            string                 `json:"model_name,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Events               []RegistryWebhookEvent `json:"events,omitempty"`
+	Status               RegistryWebhookStatus  `json:"status,omitempty"`
+	HttpUrlSpec          *HttpUrlSpec           `json:"http_url_spec,omitempty"`
+	JobSpec              *JobSpec               `json:"job_spec,omitempty"`
+}
+
+type HttpUrlSpec struct {
+	Url string `json:"url"`
+	// Secret is used to sign each payload delivered to Url.
+	Secret                string `json:"secret,omitempty"`
+	EnableSslVerification *bool  `json:"enable_ssl_verification,omitempty"`
+	Authorization         string `json:"authorization,omitempty"`
+}
+
+type JobSpec struct {
+	JobId        string `json:"job_id"`
+	AccessToken  string `json:"access_token,omitempty"`
+	WorkspaceUrl string `json:"workspace_url,omitempty"`
+}
+
+type ResponseRegistryWebhook struct {
+	Webhook RegistryWebhook `json:"webhook"`
+}
+
+type ResponseListRegistryWebhooks struct {
+	Webhooks      []RegistryWebhook `json:"webhooks"`
+	NextPageToken string            `json:"next_page_token,omitempty"`
+}
+
+type WebhookTestResult struct {
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+}
+
+func decodeRegistryWebhook(body []byte, err error) (*RegistryWebhook, error) {
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseRegistryWebhook
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Webhook, nil
+}
+
+// CreateRegistryWebhook registers webhook. Leave ModelName empty to receive
+// events for every registered model.
+func (p *Client) CreateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registry-webhooks/create"
+	return decodeRegistryWebhook(p.HandlePost(ctx, url, webhook))
+}
+
+func (p *Client) ListRegistryWebhooks(ctx context.Context, modelName string, events []RegistryWebhookEvent, pageToken string) (*ResponseListRegistryWebhooks, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registry-webhooks/list"
+	params := map[string]interface{}{}
+	if modelName != "" {
+		params["model_name"] = modelName
+	}
+	if len(events) > 0 {
+		values := make([]string, len(events))
+		for i, event := range events {
+			values[i] = string(event)
+		}
+		params["events"] = values
+	}
+	if pageToken != "" {
+		params["page_token"] = pageToken
+	}
+	body, err := p.HandleGet(ctx, url, params)
+	if err != nil {
+		return nil, err
+	}
+	var response ResponseListRegistryWebhooks
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// TestRegistryWebhook sends a test payload for event, or the webhook's first
+// event when it is empty, and reports how the receiver responded.
+func (p *Client) TestRegistryWebhook(ctx context.Context, id string, event RegistryWebhookEvent) (*WebhookTestResult, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registry-webhooks/test"
+	request := map[string]interface{}{"id": id}
+	if event != "" {
+		request["event"] = event
+	}
+	body, err := p.HandlePost(ctx, url, request)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Webhook WebhookTestResult `json:"webhook"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Webhook, nil
+}
+
+// UpdateRegistryWebhook changes the webhook identified by webhook.Id. Only
+// the fields that are set are updated.
+func (p *Client) UpdateRegistryWebhook(ctx context.Context, webhook RegistryWebhook) (*RegistryWebhook, error) {
+	url := p.BaseUrl + "/api/2.0/mlflow/registry-webhooks/update"
+	return decodeRegistryWebhook(p.HandlePatch(ctx, url, webhook))
+}
+
+func (p *Client) DeleteRegistryWebhook(ctx context.Context, id string) error {
+	url := p.BaseUrl + "/api/2.0/mlflow/registry-webhooks/delete"
+	_, err := p.HandleDelete(ctx, url, map[string]interface{}{"id": id})
+	return err
+}
+
+// WebhookEvent is the payload delivered by a registry webhook. Fields that
+// don't apply to the event are left empty; Raw holds the full payload.
+type WebhookEvent struct {
+	Event          RegistryWebhookEvent `json:"event"`
+	WebhookId      string               `json:"webhook_id"`
+	EventTimestamp int64                `json:"event_timestamp"`
+	ModelName      string               `json:"model_name,omitempty"`
+	Version        string               `json:"version,omitempty"`
+	FromStage      string               `json:"from_stage,omitempty"`
+	ToStage        string               `json:"to_stage,omitempty"`
+	Text           string               `json:"text,omitempty"`
+	Comment        string               `json:"comment,omitempty"`
+	Tags           []ModelVersionTag    `json:"tags,omitempty"`
+	Raw            json.RawMessage      `json:"-"`
+}
+
+const WebhookSignatureHeader = "X-Databricks-Signature"
+
+const maxWebhookPayload = 1 << 20
+
+// VerifyWebhookSignature reports whether signature, the hex encoded
+// HMAC-SHA256 sent in the X-Databricks-Signature header, matches payload
+// for the webhook secret.
+func VerifyWebhookSignature(payload []byte, signature string, secret string) bool {
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// ParseWebhookEvent reads a webhook delivery from r. When secret is not empty
+// the payload signature must match or ErrInvalidWebhookSignature is returned.
+func ParseWebhookEvent(r *http.Request, secret string) (*WebhookEvent, error) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload))
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" && !VerifyWebhookSignature(payload, r.Header.Get(WebhookSignatureHeader), secret) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	event.Raw = payload
+	return &event, nil
+}
+
+var ErrInvalidWebhookSignature = errors.New("mlflow: invalid webhook signature")
+
+// WebhookHandler returns an http.Handler that verifies and decodes webhook
+// deliveries and passes them to fn. Deliveries with a bad signature get 401,
+// malformed payloads 400 and errors returned by fn 500, so the registry
+// retries them.
+func WebhookHandler(secret string, fn func(ctx context.Context, event *WebhookEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := ParseWebhookEvent(r, secret)
+		if err == ErrInvalidWebhookSignature {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := fn(r.Context(), event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
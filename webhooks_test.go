@@ -0,0 +1,134 @@
+package mlflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	payload := `{"event":"MODEL_VERSION_TRANSITIONED_STAGE","webhook_id":"c5596721253c4b429368cf6f4341b88a","event_timestamp":1589859029343,"model_name":"Airline_Delay_SparkML","version":"8","to_stage":"Production","from_stage":"None","text":"Registered model 'someModel' version 8 transitioned from None to Production."}`
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(payload))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	var received *WebhookEvent
+	handler := WebhookHandler("s3cret", func(ctx context.Context, event *WebhookEvent) error {
+		received = event
+		return nil
+	})
+	tests := []struct {
+		name      string
+		signature string
+		status    int
+	}{
+		{"Valid", signature, http.StatusOK},
+		{"Tampered", strings.Repeat("0", len(signature)), http.StatusUnauthorized},
+		{"Missing", "", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/hooks", strings.NewReader(payload))
+			req.Header.Set(WebhookSignatureHeader, test.signature)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != test.status {
+				t.Errorf("Expected status %d, got %d", test.status, rec.Code)
+			}
+		})
+	}
+	if received == nil || received.Event != ModelVersionTransitionedStage || received.ModelName != "Airline_Delay_SparkML" || received.Version != "8" || received.ToStage != StageProduction {
+		t.Errorf("unexpected event %+v", received)
+	}
+}
+
+func TestRegistryWebhookRequests(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		query  url.Values
+		body   map[string]interface{}
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, request{r.Method, r.URL.Path, r.URL.Query(), body})
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/registry-webhooks/list":
+			w.Write([]byte(`{"webhooks": [{"id": "wh1", "status": "ACTIVE"}], "next_page_token": "next"}`))
+		case "/api/2.0/mlflow/registry-webhooks/test":
+			w.Write([]byte(`{"webhook": {"status_code": 200, "body": "ok"}}`))
+		case "/api/2.0/mlflow/registry-webhooks/delete":
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{"webhook": {"id": "wh1", "model_name": "model", "status": "ACTIVE"}}`))
+		}
+	}))
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+	last := func() request {
+		return requests[len(requests)-1]
+	}
+
+	webhook, err := client.CreateRegistryWebhook(ctx, RegistryWebhook{ModelName: "model", Events: []RegistryWebhookEvent{ModelVersionCreated}, HttpUrlSpec: &HttpUrlSpec{Url: "https://example.com/hook", Secret: "s3cret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); r.method != "POST" || r.path != "/api/2.0/mlflow/registry-webhooks/create" || r.body["model_name"] != "model" || r.body["http_url_spec"].(map[string]interface{})["url"] != "https://example.com/hook" || r.body["id"] != nil {
+		t.Errorf("unexpected create request %+v", r)
+	}
+	if webhook.Id != "wh1" || webhook.Status != WebhookActive {
+		t.Errorf("unexpected webhook %+v", webhook)
+	}
+
+	list, err := client.ListRegistryWebhooks(ctx, "model", []RegistryWebhookEvent{ModelVersionCreated, RegisteredModelCreated}, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); r.method != "GET" || r.path != "/api/2.0/mlflow/registry-webhooks/list" || r.query.Get("model_name") != "model" || strings.Join(r.query["events"], ",") != "MODEL_VERSION_CREATED,REGISTERED_MODEL_CREATED" || r.query.Get("page_token") != "token" {
+		t.Errorf("unexpected list request %+v", r)
+	}
+	if len(list.Webhooks) != 1 || list.Webhooks[0].Id != "wh1" || list.NextPageToken != "next" {
+		t.Errorf("unexpected list %+v", list)
+	}
+	if _, err := client.ListRegistryWebhooks(ctx, "", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); len(r.query) != 0 {
+		t.Errorf("expected no parameters, got %v", r.query)
+	}
+
+	if _, err := client.UpdateRegistryWebhook(ctx, RegistryWebhook{Id: "wh1", Status: WebhookDisabled}); err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); r.method != "PATCH" || r.path != "/api/2.0/mlflow/registry-webhooks/update" || r.body["id"] != "wh1" || r.body["status"] != "DISABLED" || len(r.body) != 2 {
+		t.Errorf("unexpected update request %+v", r)
+	}
+
+	result, err := client.TestRegistryWebhook(ctx, "wh1", ModelVersionCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); r.method != "POST" || r.path != "/api/2.0/mlflow/registry-webhooks/test" || r.body["id"] != "wh1" || r.body["event"] != "MODEL_VERSION_CREATED" {
+		t.Errorf("unexpected test request %+v", r)
+	}
+	if result.StatusCode != 200 || result.Body != "ok" {
+		t.Errorf("unexpected test result %+v", result)
+	}
+
+	if err := client.DeleteRegistryWebhook(ctx, "wh1"); err != nil {
+		t.Fatal(err)
+	}
+	if r := last(); r.method != "DELETE" || r.path != "/api/2.0/mlflow/registry-webhooks/delete" || len(r.query) != 0 || r.body["id"] != "wh1" {
+		t.Errorf("unexpected delete request %+v", r)
+	}
+}
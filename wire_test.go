@@ -0,0 +1,69 @@
+package mlflow
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestWireFields checks the types generated from internal/protogen/proto
+// against the fixtures in testdata/wire, which hold every field of the
+// corresponding messages of MLflow's service.proto and model_registry.proto.
+// A field the server sends but a type lacks would be dropped silently by
+// json.Unmarshal, so it fails here instead: copy it into the .proto files and
+// run go generate. Update the fixtures when MLflow adds fields.
+func TestWireFields(t *testing.T) {
+	for file, v := range map[string]interface{}{
+		"run.json":              Run{},
+		"experiment.json":       Experiment{},
+		"registered_model.json": RegisteredModel{},
+		"model_version.json":    ModelVersion{},
+	} {
+		b, err := os.ReadFile("testdata/wire/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var message interface{}
+		if err := json.Unmarshal(b, &message); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range missingFields(message, reflect.TypeOf(v), strings.TrimSuffix(file, ".json")) {
+			t.Errorf("%T has no field for %s", v, field)
+		}
+	}
+}
+
+// missingFields returns the paths of the keys of message that typ has no
+// json field for.
+func missingFields(message interface{}, typ reflect.Type, path string) []string {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	var missing []string
+	switch message := message.(type) {
+	case []interface{}:
+		for _, item := range message {
+			missing = append(missing, missingFields(item, typ, path+"[]")...)
+		}
+	case map[string]interface{}:
+		if typ.Kind() != reflect.Struct {
+			return nil
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < typ.NumField(); i++ {
+			name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+			fields[name] = typ.Field(i).Type
+		}
+		for key, value := range message {
+			fieldType, ok := fields[key]
+			if !ok {
+				missing = append(missing, path+"."+key)
+				continue
+			}
+			missing = append(missing, missingFields(value, fieldType, path+"."+key)...)
+		}
+	}
+	return missing
+}